@@ -10,7 +10,9 @@ import (
 	"github.com/tasuku43/gion/internal/infra/gitcmd"
 )
 
-func AddRepo(ctx context.Context, rootDir, workspaceID, repoKey, alias, branch, baseRef string, fetch bool) (workspace.Repo, bool, string, error) {
+// AddRepo adds a worktree for repoKey, fetching the repo store first if needed,
+// and carries files into it according to carry.
+func AddRepo(ctx context.Context, rootDir, workspaceID, repoKey, alias, branch, baseRef string, fetch bool, carry []workspace.CarryRule) (workspace.Repo, bool, string, error) {
 	repoSpec := repo.SpecFromKey(repoKey)
 	_, exists, err := repo.Exists(rootDir, repoSpec)
 	if err != nil {
@@ -44,7 +46,7 @@ func AddRepo(ctx context.Context, rootDir, workspaceID, repoKey, alias, branch,
 		}
 	}
 
-	added, err := workspace.AddWithBranch(ctx, rootDir, workspaceID, repoSpec, alias, branch, baseRef, fetch)
+	added, err := workspace.AddWithBranch(ctx, rootDir, workspaceID, repoSpec, alias, branch, baseRef, fetch, carry...)
 	if err != nil {
 		return workspace.Repo{}, false, "", err
 	}
@@ -8,6 +8,7 @@ import (
 
 	coreapplyplan "github.com/tasuku43/gion-core/applyplan"
 	"github.com/tasuku43/gion/internal/app/add"
+	"github.com/tasuku43/gion/internal/app/archive"
 	"github.com/tasuku43/gion/internal/app/create"
 	"github.com/tasuku43/gion/internal/app/manifestplan"
 	"github.com/tasuku43/gion/internal/app/remove_repo"
@@ -24,15 +25,34 @@ type Options struct {
 	PrefetchTimeout  time.Duration
 	PrefetchOK       bool
 	Step             func(text string)
+	// Warn reports hook failures marked on_failure: warn.
+	Warn func(text string)
 }
 
 func Apply(ctx context.Context, rootDir string, plan manifestplan.Result, opts Options) error {
 	execPlan := coreapplyplan.BuildExecution(plan.Changes)
 
+	for _, rename := range plan.Renames {
+		if err := applyWorkspaceRename(ctx, rootDir, plan.Desired, rename, opts.Step); err != nil {
+			return err
+		}
+	}
+
+	for _, move := range plan.Moves {
+		if err := applyRepoMove(ctx, rootDir, move, opts.Step); err != nil {
+			return err
+		}
+	}
+
 	for _, change := range execPlan.WorkspaceRemovals {
 		if change.Kind != manifestplan.WorkspaceRemove {
 			continue
 		}
+		if ws, ok := plan.Actual.Workspaces[change.WorkspaceID]; ok {
+			if err := runWorkspacePreRemoveHooks(ctx, rootDir, plan.Desired, change.WorkspaceID, ws, opts); err != nil {
+				return err
+			}
+		}
 		logStep(opts.Step, fmt.Sprintf("remove workspace %s", change.WorkspaceID))
 		if err := rm.Remove(ctx, rootDir, change.WorkspaceID, opts.AllowDirty); err != nil {
 			return err
@@ -43,7 +63,7 @@ func Apply(ctx context.Context, rootDir string, plan manifestplan.Result, opts O
 		if change.Kind != manifestplan.WorkspaceUpdate {
 			continue
 		}
-		if err := applyRepoRemovals(ctx, rootDir, change, opts); err != nil {
+		if err := applyRepoRemovals(ctx, rootDir, plan, change, opts); err != nil {
 			return err
 		}
 	}
@@ -52,7 +72,7 @@ func Apply(ctx context.Context, rootDir string, plan manifestplan.Result, opts O
 		if change.Kind != manifestplan.WorkspaceUpdate {
 			continue
 		}
-		if err := applyRepoBranchRenames(ctx, rootDir, change, opts.Step); err != nil {
+		if err := applyRepoBranchRenames(ctx, rootDir, plan.Desired, change, opts.Step); err != nil {
 			return err
 		}
 	}
@@ -75,6 +95,20 @@ func Apply(ctx context.Context, rootDir string, plan manifestplan.Result, opts O
 		}
 	}
 
+	for _, update := range plan.LabelUpdates {
+		logStep(opts.Step, fmt.Sprintf("update labels %s", update.WorkspaceID))
+		if err := workspace.SaveLabels(workspace.WorkspaceDir(rootDir, update.WorkspaceID), update.To); err != nil {
+			return fmt.Errorf("workspace %s labels: %w", update.WorkspaceID, err)
+		}
+	}
+
+	for _, update := range plan.ExpiryUpdates {
+		logStep(opts.Step, fmt.Sprintf("update expiry %s", update.WorkspaceID))
+		if err := workspace.SaveExpiresAt(workspace.WorkspaceDir(rootDir, update.WorkspaceID), update.To); err != nil {
+			return fmt.Errorf("workspace %s expiry: %w", update.WorkspaceID, err)
+		}
+	}
+
 	return nil
 }
 
@@ -83,12 +117,22 @@ func applyWorkspaceAdd(ctx context.Context, rootDir string, desired manifest.Fil
 	if !ok {
 		return fmt.Errorf("workspace not found in manifest: %s", change.WorkspaceID)
 	}
+	var archived archive.Archive
+	restoring := strings.TrimSpace(ws.RestoreFrom) != ""
+	if restoring {
+		var err error
+		if archived, err = loadRestoreArchive(rootDir, ws); err != nil {
+			return fmt.Errorf("workspace %s: %w", change.WorkspaceID, err)
+		}
+	}
 	logStep(opts.Step, fmt.Sprintf("create workspace %s", change.WorkspaceID))
 	_, err := create.CreateWorkspace(ctx, rootDir, change.WorkspaceID, workspace.Metadata{
 		Description: ws.Description,
 		Mode:        ws.Mode,
 		PresetName:  ws.PresetName,
 		SourceURL:   ws.SourceURL,
+		Labels:      ws.Labels,
+		ExpiresAt:   ws.ExpiresAt,
 	})
 	if err != nil {
 		return err
@@ -101,32 +145,100 @@ func applyWorkspaceAdd(ctx context.Context, rootDir string, desired manifest.Fil
 	}
 	for _, repoEntry := range ws.Repos {
 		logStep(opts.Step, fmt.Sprintf("worktree add %s", repoEntry.Alias))
-		if strings.EqualFold(strings.TrimSpace(ws.Mode), workspace.MetadataModeReview) {
-			if err := applyReviewRepoAdd(ctx, rootDir, change.WorkspaceID, repoEntry); err != nil {
+		archivedEntry, isArchived := archivedRepo(archived, repoEntry)
+		switch {
+		case restoring && isArchived:
+			if err := applyRestoredRepoAdd(ctx, rootDir, change.WorkspaceID, archived, archivedEntry, fetch, desired.CarryRules(repoEntry.RepoKey)); err != nil {
 				return err
 			}
-			continue
+		case strings.TrimSpace(repoEntry.Ref) != "":
+			if err := applyPinnedRepoAdd(ctx, rootDir, change.WorkspaceID, repoEntry.RepoKey, repoEntry.Alias, strings.TrimSpace(repoEntry.Ref), fetch, desired.CarryRules(repoEntry.RepoKey)); err != nil {
+				return err
+			}
+		case strings.TrimSpace(ws.CopiedFrom) != "":
+			createdBranch, err := applyCopiedRepoAdd(ctx, rootDir, change.WorkspaceID, ws, repoEntry, fetch, desired.CarryRules(repoEntry.RepoKey))
+			if err != nil {
+				return err
+			}
+			if baseRef := strings.TrimSpace(repoEntry.BaseRef); createdBranch && baseRef != "" {
+				baseBranchToRecord, baseBranchMixed = coreapplyplan.UpdateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseRef)
+			}
+		case strings.EqualFold(strings.TrimSpace(ws.Mode), workspace.MetadataModeReview):
+			if err := applyReviewRepoAdd(ctx, rootDir, change.WorkspaceID, repoEntry, desired.CarryRules(repoEntry.RepoKey)); err != nil {
+				return err
+			}
+		default:
+			_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoEntry.RepoKey, repoEntry.Alias, repoEntry.Branch, repoEntry.BaseRef, fetch, desired.CarryRules(repoEntry.RepoKey))
+			if err != nil {
+				return err
+			}
+			if createdBranch {
+				baseBranchToRecord, baseBranchMixed = coreapplyplan.UpdateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseBranch)
+			}
 		}
-		_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoEntry.RepoKey, repoEntry.Alias, repoEntry.Branch, repoEntry.BaseRef, fetch)
-		if err != nil {
+		if err := runPostRepoAddHooks(ctx, rootDir, desired, change.WorkspaceID, ws, repoEntry, opts); err != nil {
 			return err
 		}
-		if createdBranch {
-			baseBranchToRecord, baseBranchMixed = coreapplyplan.UpdateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseBranch)
-		}
 	}
 	if baseBranchMixed {
 		// Workspace-level base_branch can't represent multiple different bases across repos.
 		// Keep it empty so `gion import` doesn't inject an incorrect base_ref into every repo.
 		baseBranchToRecord = ""
 	}
+	if restoring {
+		baseBranchToRecord = archived.Metadata.BaseBranch
+	}
 	if err := recordBaseBranchIfMissing(rootDir, change.WorkspaceID, baseBranchToRecord); err != nil {
 		return err
 	}
-	return nil
+	target := hookTarget{point: manifest.HookPostCreate, workspaceID: change.WorkspaceID, ws: ws}
+	return runHooks(ctx, rootDir, desired.WorkspaceHooks(ws).PostCreate, target, opts)
 }
 
-func applyReviewRepoAdd(ctx context.Context, rootDir, workspaceID string, repoEntry manifest.Repo) error {
+// applyWorkspaceRename moves the workspace directory and its worktrees, then
+// records the desired description, mode, preset, source URL, labels and expiry
+// in its metadata.
+func applyWorkspaceRename(ctx context.Context, rootDir string, desired manifest.File, rename manifestplan.WorkspaceRename, step func(text string)) error {
+	logStep(step, fmt.Sprintf("rename workspace %s -> %s", rename.From, rename.To))
+	if err := workspace.Rename(ctx, rootDir, rename.From, rename.To); err != nil {
+		return err
+	}
+	ws, ok := desired.Workspaces[rename.To]
+	if !ok {
+		return nil
+	}
+	wsDir := workspace.WorkspaceDir(rootDir, rename.To)
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return err
+	}
+	meta.Description = ws.Description
+	meta.Mode = ws.Mode
+	meta.PresetName = ws.PresetName
+	meta.SourceURL = ws.SourceURL
+	meta.Labels = append([]string(nil), ws.Labels...)
+	meta.ExpiresAt = ws.ExpiresAt
+	return workspace.SaveMetadata(wsDir, meta)
+}
+
+// applyRepoMove moves a worktree to its new alias and/or workspace and carries
+// its pin over in the workspace metadata.
+func applyRepoMove(ctx context.Context, rootDir string, move manifestplan.RepoMove, step func(text string)) error {
+	logStep(step, fmt.Sprintf("move repo %s/%s -> %s/%s", move.FromWorkspace, move.FromAlias, move.ToWorkspace, move.ToAlias))
+	if err := workspace.MoveRepo(ctx, rootDir, move.FromWorkspace, move.FromAlias, move.ToWorkspace, move.ToAlias); err != nil {
+		return err
+	}
+	ref, pinned := manifestplan.PinnedRef(move.Branch)
+	if !pinned {
+		return nil
+	}
+	if err := workspace.SavePin(workspace.WorkspaceDir(rootDir, move.FromWorkspace), move.FromAlias, ""); err != nil {
+		return err
+	}
+	return workspace.SavePin(workspace.WorkspaceDir(rootDir, move.ToWorkspace), move.ToAlias, ref)
+}
+
+func applyReviewRepoAdd(ctx context.Context, rootDir, workspaceID string, repoEntry manifest.Repo, carry []workspace.CarryRule) error {
 	repoSpec := repo.SpecFromKey(repoEntry.RepoKey)
 	_, exists, err := repo.Exists(rootDir, repoSpec)
 	if err != nil {
@@ -161,17 +273,22 @@ func applyReviewRepoAdd(ctx context.Context, rootDir, workspaceID string, repoEn
 		}
 	}
 
-	_, err = workspace.AddWithTrackingBranch(ctx, rootDir, workspaceID, repoSpec, repoEntry.Alias, branch, remoteRef, false)
+	_, err = workspace.AddWithTrackingBranch(ctx, rootDir, workspaceID, repoSpec, repoEntry.Alias, branch, remoteRef, false, carry...)
 	return err
 }
 
-func applyRepoRemovals(ctx context.Context, rootDir string, change manifestplan.WorkspaceChange, opts Options) error {
+func applyRepoRemovals(ctx context.Context, rootDir string, plan manifestplan.Result, change manifestplan.WorkspaceChange, opts Options) error {
 	for _, repoChange := range change.Repos {
 		switch repoChange.Kind {
 		case manifestplan.RepoRemove, manifestplan.RepoUpdate:
 			if coreapplyplan.IsInPlaceBranchRename(repoChange) {
 				continue
 			}
+			if ws, repoEntry, ok := findRepo(plan.Actual, change.WorkspaceID, repoChange.Alias); ok {
+				if err := runRepoPreRemoveHooks(ctx, rootDir, plan.Desired, change.WorkspaceID, ws, repoEntry, opts); err != nil {
+					return err
+				}
+			}
 			logStep(opts.Step, fmt.Sprintf("worktree remove %s", repoChange.Alias))
 			if err := remove_repo.RemoveRepo(ctx, rootDir, change.WorkspaceID, repoChange.Alias, remove_repo.Options{
 				AllowDirty:       opts.AllowDirty,
@@ -179,16 +296,27 @@ func applyRepoRemovals(ctx context.Context, rootDir string, change manifestplan.
 			}); err != nil {
 				return err
 			}
+			if _, pinned := manifestplan.PinnedRef(repoChange.FromBranch); pinned {
+				if err := workspace.SavePin(workspace.WorkspaceDir(rootDir, change.WorkspaceID), repoChange.Alias, ""); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	return nil
 }
 
-func applyRepoBranchRenames(ctx context.Context, rootDir string, change manifestplan.WorkspaceChange, step func(text string)) error {
+func applyRepoBranchRenames(ctx context.Context, rootDir string, desired manifest.File, change manifestplan.WorkspaceChange, step func(text string)) error {
 	for _, repoChange := range change.Repos {
 		if !coreapplyplan.IsInPlaceBranchRename(repoChange) {
 			continue
 		}
+		if isPinnedSwitch(repoChange) {
+			if err := applyPinnedSwitch(ctx, rootDir, change.WorkspaceID, desired, repoChange, step); err != nil {
+				return err
+			}
+			continue
+		}
 		worktreePath := workspace.WorktreePath(rootDir, change.WorkspaceID, repoChange.Alias)
 
 		currentBranch, err := gitcmd.RevParse(ctx, worktreePath, "--abbrev-ref", "HEAD")
@@ -215,30 +343,46 @@ func applyRepoAdds(ctx context.Context, rootDir string, desired manifest.File, c
 		fetch = false
 	}
 	for _, repoChange := range change.Repos {
+		if ref, pinned := manifestplan.PinnedRef(repoChange.ToBranch); pinned && !coreapplyplan.IsInPlaceBranchRename(repoChange) {
+			logStep(opts.Step, fmt.Sprintf("worktree add %s", repoChange.Alias))
+			if err := applyPinnedRepoAdd(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, ref, fetch, desired.CarryRules(repoChange.ToRepo)); err != nil {
+				return err
+			}
+			if err := runAddedRepoHooks(ctx, rootDir, desired, change.WorkspaceID, repoChange.Alias, opts); err != nil {
+				return err
+			}
+			continue
+		}
 		switch repoChange.Kind {
 		case manifestplan.RepoAdd:
 			logStep(opts.Step, fmt.Sprintf("worktree add %s", repoChange.Alias))
 			baseRef := desiredBaseRef(desired, change.WorkspaceID, repoChange.Alias)
-			_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, repoChange.ToBranch, baseRef, fetch)
+			_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, repoChange.ToBranch, baseRef, fetch, desired.CarryRules(repoChange.ToRepo))
 			if err != nil {
 				return err
 			}
 			if createdBranch {
 				baseBranchToRecord, baseBranchMixed = coreapplyplan.UpdateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseBranch)
 			}
+			if err := runAddedRepoHooks(ctx, rootDir, desired, change.WorkspaceID, repoChange.Alias, opts); err != nil {
+				return err
+			}
 		case manifestplan.RepoUpdate:
 			if coreapplyplan.IsInPlaceBranchRename(repoChange) {
 				continue
 			}
 			logStep(opts.Step, fmt.Sprintf("worktree add %s", repoChange.Alias))
 			baseRef := desiredBaseRef(desired, change.WorkspaceID, repoChange.Alias)
-			_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, repoChange.ToBranch, baseRef, fetch)
+			_, createdBranch, baseBranch, err := add.AddRepo(ctx, rootDir, change.WorkspaceID, repoChange.ToRepo, repoChange.Alias, repoChange.ToBranch, baseRef, fetch, desired.CarryRules(repoChange.ToRepo))
 			if err != nil {
 				return err
 			}
 			if createdBranch {
 				baseBranchToRecord, baseBranchMixed = coreapplyplan.UpdateBaseBranchCandidate(baseBranchToRecord, baseBranchMixed, baseBranch)
 			}
+			if err := runAddedRepoHooks(ctx, rootDir, desired, change.WorkspaceID, repoChange.Alias, opts); err != nil {
+				return err
+			}
 		}
 	}
 	if baseBranchMixed {
@@ -0,0 +1,57 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/app/add"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// applyCopiedRepoAdd adds a worktree of a copied workspace: the branch is created
+// from the HEAD of the same alias in the source workspace, then the source's
+// uncommitted changes are brought over when requested. The branch must not exist
+// yet, locally or on origin, since checking it out would leave the copy (and the
+// carried changes) on an unrelated commit. It reports whether the branch was created.
+func applyCopiedRepoAdd(ctx context.Context, rootDir, workspaceID string, ws manifest.Workspace, repoEntry manifest.Repo, fetch bool, carry []workspace.CarryRule) (bool, error) {
+	sourceID := strings.TrimSpace(ws.CopiedFrom)
+	sourcePath := workspace.WorktreePath(rootDir, sourceID, repoEntry.Alias)
+	if exists, err := paths.DirExists(sourcePath); err != nil {
+		return false, err
+	} else if !exists {
+		return false, fmt.Errorf("copy source workspace %s has no repo %s", sourceID, repoEntry.Alias)
+	}
+	head, err := gitcmd.RevParse(ctx, sourcePath, "HEAD")
+	if err != nil {
+		return false, err
+	}
+	// Fetch here rather than in AddRepo so the check sees the remote branches.
+	store, err := repo.Open(ctx, rootDir, repo.SpecFromKey(repoEntry.RepoKey), fetch)
+	if err != nil {
+		return false, err
+	}
+	for _, ref := range []string{"refs/heads/" + repoEntry.Branch, "refs/remotes/origin/" + repoEntry.Branch} {
+		_, exists, err := gitcmd.ShowRef(ctx, store.StorePath, ref)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			return false, fmt.Errorf("cannot copy %s/%s: branch %s already exists (%s)", sourceID, repoEntry.Alias, repoEntry.Branch, ref)
+		}
+	}
+	_, createdBranch, _, err := add.AddRepo(ctx, rootDir, workspaceID, repoEntry.RepoKey, repoEntry.Alias, repoEntry.Branch, strings.TrimSpace(head), false, carry)
+	if err != nil {
+		return false, err
+	}
+	if ws.CopyChanges {
+		if err := workspace.CopyChanges(ctx, sourcePath, workspace.WorktreePath(rootDir, workspaceID, repoEntry.Alias)); err != nil {
+			return createdBranch, fmt.Errorf("copy changes of %s/%s: %w", sourceID, repoEntry.Alias, err)
+		}
+	}
+	return createdBranch, nil
+}
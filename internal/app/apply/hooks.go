@@ -0,0 +1,140 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/hookcmd"
+)
+
+// hookTarget is the workspace, and optionally the repo, a hook runs for.
+// Its fields are passed to the hook as GION_* environment variables.
+type hookTarget struct {
+	point       string
+	workspaceID string
+	ws          manifest.Workspace
+	alias       string
+	repoKey     string
+	branch      string
+}
+
+// dir is the worktree for repo hooks and the workspace directory otherwise.
+func (t hookTarget) dir(rootDir string) string {
+	if t.alias != "" {
+		return workspace.WorktreePath(rootDir, t.workspaceID, t.alias)
+	}
+	return workspace.WorkspaceDir(rootDir, t.workspaceID)
+}
+
+func (t hookTarget) env(rootDir string) []string {
+	env := []string{
+		"GION_ROOT=" + rootDir,
+		"GION_HOOK=" + t.point,
+		"GION_WORKSPACE_ID=" + t.workspaceID,
+		"GION_WORKSPACE_PATH=" + workspace.WorkspaceDir(rootDir, t.workspaceID),
+		"GION_WORKSPACE_MODE=" + strings.TrimSpace(t.ws.Mode),
+		"GION_PRESET=" + strings.TrimSpace(t.ws.PresetName),
+	}
+	if t.alias != "" {
+		env = append(env,
+			"GION_REPO_ALIAS="+t.alias,
+			"GION_REPO_KEY="+t.repoKey,
+			"GION_REPO_PATH="+workspace.WorktreePath(rootDir, t.workspaceID, t.alias),
+			"GION_BRANCH="+t.branch,
+		)
+	}
+	return env
+}
+
+func (t hookTarget) String() string {
+	if t.alias != "" {
+		return fmt.Sprintf("%s/%s", t.workspaceID, t.alias)
+	}
+	return t.workspaceID
+}
+
+// runHooks runs hooks in order. A failing hook stops apply unless it is marked
+// on_failure: warn, in which case the failure is reported and the next hook runs.
+func runHooks(ctx context.Context, rootDir string, hooks []manifest.Hook, target hookTarget, opts Options) error {
+	for _, hook := range hooks {
+		logStep(opts.Step, fmt.Sprintf("hook %s %s", target.point, hook.Label()))
+		err := hookcmd.Run(ctx, hook.Run, target.dir(rootDir), target.env(rootDir))
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("%s: hook %s %q failed: %w", target, target.point, hook.Label(), err)
+		if !hook.Warns() {
+			return err
+		}
+		if opts.Warn != nil {
+			opts.Warn(err.Error())
+		}
+	}
+	return nil
+}
+
+// runPostRepoAddHooks runs the workspace-wide and repo-key post_repo_add hooks
+// for a worktree that was just added.
+func runPostRepoAddHooks(ctx context.Context, rootDir string, file manifest.File, workspaceID string, ws manifest.Workspace, repoEntry manifest.Repo, opts Options) error {
+	hooks := append(file.WorkspaceHooks(ws).PostRepoAdd, file.RepoKeyHooks(repoEntry.RepoKey).PostRepoAdd...)
+	return runHooks(ctx, rootDir, hooks, repoHookTarget(manifest.HookPostRepoAdd, workspaceID, ws, repoEntry), opts)
+}
+
+// runAddedRepoHooks runs the post_repo_add hooks of a repo added to an existing workspace.
+func runAddedRepoHooks(ctx context.Context, rootDir string, desired manifest.File, workspaceID, alias string, opts Options) error {
+	ws, repoEntry, ok := findRepo(desired, workspaceID, alias)
+	if !ok {
+		return nil
+	}
+	return runPostRepoAddHooks(ctx, rootDir, desired, workspaceID, ws, repoEntry, opts)
+}
+
+// runRepoPreRemoveHooks runs the repo-key pre_remove hooks before a worktree is removed.
+func runRepoPreRemoveHooks(ctx context.Context, rootDir string, file manifest.File, workspaceID string, ws manifest.Workspace, repoEntry manifest.Repo, opts Options) error {
+	hooks := file.RepoKeyHooks(repoEntry.RepoKey).PreRemove
+	return runHooks(ctx, rootDir, hooks, repoHookTarget(manifest.HookPreRemove, workspaceID, ws, repoEntry), opts)
+}
+
+// runWorkspacePreRemoveHooks runs the pre_remove hooks of a workspace, then
+// those of each of its repos, before the workspace is removed.
+func runWorkspacePreRemoveHooks(ctx context.Context, rootDir string, file manifest.File, workspaceID string, ws manifest.Workspace, opts Options) error {
+	target := hookTarget{point: manifest.HookPreRemove, workspaceID: workspaceID, ws: ws}
+	if err := runHooks(ctx, rootDir, file.WorkspaceHooks(ws).PreRemove, target, opts); err != nil {
+		return err
+	}
+	for _, repoEntry := range ws.Repos {
+		if err := runRepoPreRemoveHooks(ctx, rootDir, file, workspaceID, ws, repoEntry, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func repoHookTarget(point, workspaceID string, ws manifest.Workspace, repoEntry manifest.Repo) hookTarget {
+	branch := strings.TrimSpace(repoEntry.Branch)
+	if branch == "" {
+		branch = strings.TrimSpace(repoEntry.Ref)
+	}
+	return hookTarget{
+		point:       point,
+		workspaceID: workspaceID,
+		ws:          ws,
+		alias:       strings.TrimSpace(repoEntry.Alias),
+		repoKey:     strings.TrimSpace(repoEntry.RepoKey),
+		branch:      branch,
+	}
+}
+
+// findRepo returns the repo entry with alias in workspaceID of file.
+func findRepo(file manifest.File, workspaceID, alias string) (manifest.Workspace, manifest.Repo, bool) {
+	ws := file.Workspaces[workspaceID]
+	for _, repoEntry := range ws.Repos {
+		if strings.TrimSpace(repoEntry.Alias) == strings.TrimSpace(alias) {
+			return ws, repoEntry, true
+		}
+	}
+	return ws, manifest.Repo{}, false
+}
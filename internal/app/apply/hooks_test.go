@@ -0,0 +1,79 @@
+package apply
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/output"
+)
+
+type captureLogger struct {
+	steps      []string
+	logOutputs []string
+}
+
+func (c *captureLogger) Step(text string)      { c.steps = append(c.steps, text) }
+func (c *captureLogger) Log(text string)       {}
+func (c *captureLogger) LogOutput(text string) { c.logOutputs = append(c.logOutputs, text) }
+
+func TestRunHooks_EnvAndOutput(t *testing.T) {
+	rootDir := t.TempDir()
+	repoEntry := manifest.Repo{Alias: "web", RepoKey: "github.com/org/web.git", Branch: "PROJ-1"}
+	if err := os.MkdirAll(workspace.WorktreePath(rootDir, "PROJ-1", "web"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	logger := &captureLogger{}
+	output.SetStepLogger(logger)
+	defer output.SetStepLogger(nil)
+
+	hooks := []manifest.Hook{{Name: "env", Run: `echo "$GION_HOOK $GION_WORKSPACE_ID $GION_REPO_ALIAS $GION_BRANCH"; pwd; echo oops >&2`}}
+	target := repoHookTarget(manifest.HookPostRepoAdd, "PROJ-1", manifest.Workspace{}, repoEntry)
+	if err := runHooks(context.Background(), rootDir, hooks, target, Options{Step: output.Step}); err != nil {
+		t.Fatalf("runHooks: %v", err)
+	}
+	if len(logger.steps) != 1 || logger.steps[0] != "hook post_repo_add env" {
+		t.Fatalf("unexpected steps: %v", logger.steps)
+	}
+	want := []string{"post_repo_add PROJ-1 web PROJ-1", workspace.WorktreePath(rootDir, "PROJ-1", "web"), "oops"}
+	if strings.Join(logger.logOutputs, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("output = %q, want %q", logger.logOutputs, want)
+	}
+}
+
+func TestRunHooks_OnFailure(t *testing.T) {
+	rootDir := t.TempDir()
+	if err := os.MkdirAll(workspace.WorkspaceDir(rootDir, "PROJ-1"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	target := hookTarget{point: manifest.HookPostCreate, workspaceID: "PROJ-1"}
+	var warnings []string
+	opts := Options{Warn: func(text string) { warnings = append(warnings, text) }}
+
+	hooks := []manifest.Hook{
+		{Name: "optional", Run: "exit 3", OnFailure: manifest.HookOnFailureWarn},
+		{Name: "marker", Run: "touch ran"},
+	}
+	if err := runHooks(context.Background(), rootDir, hooks, target, opts); err != nil {
+		t.Fatalf("runHooks: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `PROJ-1: hook post_create "optional" failed`) {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	if _, err := os.Stat(filepath.Join(workspace.WorkspaceDir(rootDir, "PROJ-1"), "ran")); err != nil {
+		t.Fatalf("hook after a warning did not run: %v", err)
+	}
+
+	hooks = []manifest.Hook{{Name: "required", Run: "exit 1"}, {Name: "never", Run: "touch never"}}
+	err := runHooks(context.Background(), rootDir, hooks, target, opts)
+	if err == nil || !strings.Contains(err.Error(), `hook post_create "required" failed`) {
+		t.Fatalf("expected blocking failure, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workspace.WorkspaceDir(rootDir, "PROJ-1"), "never")); !os.IsNotExist(err) {
+		t.Fatalf("hook after a blocking failure ran: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	coreapplyplan "github.com/tasuku43/gion-core/applyplan"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+func applyPinnedRepoAdd(ctx context.Context, rootDir, workspaceID, repoKey, alias, ref string, fetch bool, carry []workspace.CarryRule) error {
+	repoSpec := repo.SpecFromKey(repoKey)
+	_, exists, err := repo.Exists(rootDir, repoSpec)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+			return err
+		}
+	}
+	if _, err := workspace.AddDetached(ctx, rootDir, workspaceID, repoSpec, alias, ref, fetch, carry...); err != nil {
+		return err
+	}
+	return workspace.SavePin(workspace.WorkspaceDir(rootDir, workspaceID), alias, ref)
+}
+
+// isPinnedSwitch reports whether an in-place repo update moves to or from a pinned ref.
+// The core planner sees these as branch renames; they are applied with a checkout instead.
+func isPinnedSwitch(change manifestplan.RepoChange) bool {
+	if !coreapplyplan.IsInPlaceBranchRename(change) {
+		return false
+	}
+	_, fromPinned := manifestplan.PinnedRef(change.FromBranch)
+	_, toPinned := manifestplan.PinnedRef(change.ToBranch)
+	return fromPinned || toPinned
+}
+
+func applyPinnedSwitch(ctx context.Context, rootDir, workspaceID string, desired manifest.File, change manifestplan.RepoChange, step func(text string)) error {
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	worktreePath := workspace.WorktreePath(rootDir, workspaceID, change.Alias)
+
+	status, err := workspace.Status(ctx, rootDir, workspaceID)
+	if err != nil {
+		return err
+	}
+	for _, repoStatus := range status.Repos {
+		if repoStatus.Alias != change.Alias {
+			continue
+		}
+		if repoStatus.Error != nil {
+			return fmt.Errorf("check status for %s: %w", change.Alias, repoStatus.Error)
+		}
+		if repoStatus.Dirty {
+			return fmt.Errorf("repo has dirty changes: %s", change.Alias)
+		}
+	}
+
+	if fromRef, ok := manifestplan.PinnedRef(change.FromBranch); ok {
+		// Commits made on top of a detached HEAD would be left unreachable by the checkout.
+		if !workspace.PinMatches(ctx, worktreePath, fromRef) {
+			return fmt.Errorf("cannot switch repo %q: HEAD is not at pinned ref %q", change.Alias, fromRef)
+		}
+	} else {
+		currentBranch, err := gitcmd.RevParse(ctx, worktreePath, "--abbrev-ref", "HEAD")
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(currentBranch) != strings.TrimSpace(change.FromBranch) {
+			return fmt.Errorf("cannot switch repo %q: on %q, want %q", change.Alias, currentBranch, change.FromBranch)
+		}
+	}
+
+	if toRef, ok := manifestplan.PinnedRef(change.ToBranch); ok {
+		logStep(step, fmt.Sprintf("checkout %s %s", change.Alias, toRef))
+		commit, err := workspace.ResolvePinnedRef(ctx, worktreePath, toRef)
+		if err != nil {
+			return err
+		}
+		if err := gitcmd.CheckoutDetach(ctx, worktreePath, commit); err != nil {
+			return err
+		}
+		return workspace.SavePin(wsDir, change.Alias, toRef)
+	}
+
+	branch := strings.TrimSpace(change.ToBranch)
+	logStep(step, fmt.Sprintf("checkout %s %s", change.Alias, branch))
+	if err := checkoutBranchFromPin(ctx, rootDir, worktreePath, change.ToRepo, branch, desiredBaseRef(desired, workspaceID, change.Alias)); err != nil {
+		return err
+	}
+	return workspace.SavePin(wsDir, change.Alias, "")
+}
+
+// checkoutBranchFromPin switches a detached worktree to branch, creating it the same way
+// a worktree add would (tracking origin/<branch> when it exists, otherwise from the base ref).
+func checkoutBranchFromPin(ctx context.Context, rootDir, worktreePath, repoKey, branch, baseRef string) error {
+	if err := workspace.ValidateBranchName(ctx, branch); err != nil {
+		return err
+	}
+	if _, ok, err := gitcmd.ShowRef(ctx, worktreePath, fmt.Sprintf("refs/heads/%s", branch)); err != nil {
+		return err
+	} else if ok {
+		return gitcmd.CheckoutBranch(ctx, worktreePath, branch)
+	}
+	if _, ok, err := gitcmd.ShowRef(ctx, worktreePath, fmt.Sprintf("refs/remotes/origin/%s", branch)); err != nil {
+		return err
+	} else if ok {
+		return gitcmd.CheckoutTrackingBranch(ctx, worktreePath, branch, "origin/"+branch)
+	}
+	if strings.TrimSpace(baseRef) == "" {
+		store, err := repo.Open(ctx, rootDir, repo.SpecFromKey(repoKey), false)
+		if err != nil {
+			return err
+		}
+		baseRef, err = workspace.ResolveBaseRef(ctx, store.StorePath)
+		if err != nil {
+			return err
+		}
+	}
+	return gitcmd.CheckoutNewBranch(ctx, worktreePath, branch, baseRef)
+}
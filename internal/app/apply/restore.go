@@ -0,0 +1,69 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/app/add"
+	"github.com/tasuku43/gion/internal/app/archive"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+// loadRestoreArchive loads the archive a restored workspace is created from.
+func loadRestoreArchive(rootDir string, ws manifest.Workspace) (archive.Archive, error) {
+	dir, err := archive.Resolve(rootDir, ws.RestoreFrom)
+	if err != nil {
+		return archive.Archive{}, err
+	}
+	return archive.Load(dir)
+}
+
+// archivedRepo returns the archived state of repoEntry, if the archive has the
+// same alias on the same branch (or pinned ref).
+func archivedRepo(archived archive.Archive, repoEntry manifest.Repo) (archive.Repo, bool) {
+	r, ok := archived.Repo(repoEntry.Alias)
+	if !ok || r.RepoKey != repoEntry.RepoKey {
+		return archive.Repo{}, false
+	}
+	if ref := strings.TrimSpace(repoEntry.Ref); ref != "" {
+		return r, r.Branch == "" && r.Ref == ref
+	}
+	return r, r.Branch != "" && r.Branch == strings.TrimSpace(repoEntry.Branch)
+}
+
+// applyRestoredRepoAdd adds a worktree of a restored workspace: the branch is
+// recreated at its archived commit (from the archive's bundle when it had
+// unpushed commits), checked out, and the archived uncommitted changes are
+// reapplied. Pinned repos are checked out at their ref.
+func applyRestoredRepoAdd(ctx context.Context, rootDir, workspaceID string, archived archive.Archive, r archive.Repo, fetch bool, carry []workspace.CarryRule) error {
+	if r.Branch == "" {
+		if err := applyPinnedRepoAdd(ctx, rootDir, workspaceID, r.RepoKey, r.Alias, r.Ref, fetch, carry); err != nil {
+			return err
+		}
+	} else {
+		repoSpec := repo.SpecFromKey(r.RepoKey)
+		_, exists, err := repo.Exists(rootDir, repoSpec)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+				return err
+			}
+		}
+		store, err := repo.Open(ctx, rootDir, repoSpec, false)
+		if err != nil {
+			return err
+		}
+		if err := archived.RestoreBranch(ctx, store.StorePath, r); err != nil {
+			return fmt.Errorf("restore %s: %w", r.Alias, err)
+		}
+		if _, _, _, err := add.AddRepo(ctx, rootDir, workspaceID, r.RepoKey, r.Alias, r.Branch, r.Head, fetch, carry); err != nil {
+			return err
+		}
+	}
+	return archived.RestoreChanges(ctx, workspace.WorktreePath(rootDir, workspaceID, r.Alias), r)
+}
@@ -0,0 +1,257 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// FileName is the archive description written next to the bundles and patches.
+const FileName = "archive.json"
+
+const currentVersion = 1
+
+// Repo is one archived worktree.
+type Repo struct {
+	Alias   string `json:"alias"`
+	RepoKey string `json:"repo_key"`
+	Branch  string `json:"branch,omitempty"`
+	Ref     string `json:"ref,omitempty"`
+	BaseRef string `json:"base_ref,omitempty"`
+	// Head is the commit the worktree was at.
+	Head string `json:"head"`
+	// Bundle is the file holding the branch's commits not on origin, if any.
+	Bundle string `json:"bundle,omitempty"`
+	// Patch is the file holding uncommitted and untracked changes, if any.
+	Patch string `json:"patch,omitempty"`
+}
+
+// Archive describes an archived workspace.
+type Archive struct {
+	Version     int                `json:"version"`
+	WorkspaceID string             `json:"workspace_id"`
+	ArchivedAt  string             `json:"archived_at"`
+	Metadata    workspace.Metadata `json:"metadata"`
+	Repos       []Repo             `json:"repos"`
+	// Dir is the archive directory; set by Create and Load.
+	Dir string `json:"-"`
+}
+
+// Name returns the archive directory name, as accepted by Resolve.
+func (a Archive) Name() string {
+	return filepath.Base(a.Dir)
+}
+
+// Repo returns the archived worktree with the given alias.
+func (a Archive) Repo(alias string) (Repo, bool) {
+	for _, r := range a.Repos {
+		if r.Alias == alias {
+			return r, true
+		}
+	}
+	return Repo{}, false
+}
+
+// Remove deletes the archive directory, for an archive that turned out not to be
+// needed (the workspace was kept after all).
+func (a Archive) Remove() error {
+	if strings.TrimSpace(a.Dir) == "" {
+		return fmt.Errorf("archive directory is required")
+	}
+	return os.RemoveAll(a.Dir)
+}
+
+// Entry returns the gion.yaml entry that recreates the archived workspace.
+func (a Archive) Entry() manifest.Workspace {
+	ws := manifest.Workspace{
+		Description: a.Metadata.Description,
+		Mode:        a.Metadata.Mode,
+		PresetName:  a.Metadata.PresetName,
+		SourceURL:   a.Metadata.SourceURL,
+		Labels:      append([]string(nil), a.Metadata.Labels...),
+		ExpiresAt:   a.Metadata.ExpiresAt,
+	}
+	for _, r := range a.Repos {
+		ws.Repos = append(ws.Repos, manifest.Repo{
+			Alias:   r.Alias,
+			RepoKey: r.RepoKey,
+			Branch:  r.Branch,
+			Ref:     r.Ref,
+			BaseRef: r.BaseRef,
+		})
+	}
+	return ws
+}
+
+// Create archives the workspace into a new directory under GION_ROOT/archive:
+// a bundle of each branch's commits that are not on origin, a patch of
+// uncommitted and untracked changes, and the workspace metadata. entry is the
+// workspace's gion.yaml entry and supplies base_ref and pinned refs. The
+// workspace itself is left in place.
+func Create(ctx context.Context, rootDir, workspaceID string, entry manifest.Workspace, now time.Time) (Archive, error) {
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	if exists, err := paths.DirExists(wsDir); err != nil {
+		return Archive{}, err
+	} else if !exists {
+		return Archive{}, fmt.Errorf("workspace does not exist: %s", workspaceID)
+	}
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return Archive{}, err
+	}
+	repos, warnings, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return Archive{}, err
+	}
+	if len(warnings) > 0 {
+		return Archive{}, fmt.Errorf("workspace %s: %w", workspaceID, warnings[0])
+	}
+
+	archivedAt := now.UTC()
+	dir := filepath.Join(paths.ArchiveRoot(rootDir), fmt.Sprintf("%s-%s", workspaceID, archivedAt.Format("20060102T150405Z")))
+	if _, err := os.Stat(dir); err == nil {
+		return Archive{}, fmt.Errorf("archive already exists: %s", dir)
+	} else if !os.IsNotExist(err) {
+		return Archive{}, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return Archive{}, err
+	}
+
+	entries := make(map[string]manifest.Repo, len(entry.Repos))
+	for _, repoEntry := range entry.Repos {
+		entries[repoEntry.Alias] = repoEntry
+	}
+	archive := Archive{
+		Version:     currentVersion,
+		WorkspaceID: workspaceID,
+		ArchivedAt:  archivedAt.Format(time.RFC3339),
+		Metadata:    meta,
+		Dir:         dir,
+	}
+	for _, repo := range repos {
+		archived, err := archiveRepo(ctx, dir, repo, entries[repo.Alias], meta.Pins[repo.Alias])
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return Archive{}, fmt.Errorf("archive %s: %w", repo.Alias, err)
+		}
+		archive.Repos = append(archive.Repos, archived)
+	}
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return Archive{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, FileName), append(data, '\n'), 0o644); err != nil {
+		_ = os.RemoveAll(dir)
+		return Archive{}, err
+	}
+	return archive, nil
+}
+
+func archiveRepo(ctx context.Context, dir string, repo workspace.Repo, entry manifest.Repo, pin string) (Repo, error) {
+	head, err := gitcmd.RevParse(ctx, repo.WorktreePath, "HEAD")
+	if err != nil {
+		return Repo{}, err
+	}
+	archived := Repo{
+		Alias:   repo.Alias,
+		RepoKey: repo.RepoKey,
+		Branch:  strings.TrimSpace(repo.Branch),
+		BaseRef: strings.TrimSpace(entry.BaseRef),
+		Head:    strings.TrimSpace(head),
+	}
+	if archived.RepoKey == "" {
+		archived.RepoKey = entry.RepoKey
+	}
+	if archived.Branch == "" {
+		archived.Ref = strings.TrimSpace(entry.Ref)
+		if archived.Ref == "" {
+			archived.Ref = strings.TrimSpace(pin)
+		}
+		if archived.Ref == "" {
+			archived.Ref = archived.Head
+		}
+	}
+
+	if archived.Branch != "" {
+		branchRef := "refs/heads/" + archived.Branch
+		unpushed, err := gitcmd.RevListCount(ctx, repo.StorePath, branchRef, "--not", "--remotes=origin")
+		if err != nil {
+			return Repo{}, err
+		}
+		if unpushed > 0 {
+			archived.Bundle = repo.Alias + ".bundle"
+			bundlePath := filepath.Join(dir, archived.Bundle)
+			gitcmd.Logf("git bundle create %s %s --not --remotes=origin", bundlePath, branchRef)
+			if err := gitcmd.BundleCreate(ctx, repo.StorePath, bundlePath, branchRef, "--not", "--remotes=origin"); err != nil {
+				return Repo{}, err
+			}
+		}
+	}
+
+	patch, err := gitcmd.DiffBinaryWorktree(ctx, repo.WorktreePath)
+	if err != nil {
+		return Repo{}, err
+	}
+	if patch != "" {
+		archived.Patch = repo.Alias + ".patch"
+		if err := os.WriteFile(filepath.Join(dir, archived.Patch), []byte(patch), 0o644); err != nil {
+			return Repo{}, err
+		}
+		output.Logf("write %s", archived.Patch)
+	}
+	return archived, nil
+}
+
+// Resolve returns the directory of an archive given its name under
+// GION_ROOT/archive or a path to it.
+func Resolve(rootDir, nameOrPath string) (string, error) {
+	nameOrPath = strings.TrimSpace(nameOrPath)
+	if nameOrPath == "" {
+		return "", fmt.Errorf("archive is required")
+	}
+	dir := nameOrPath
+	if !filepath.IsAbs(dir) && !strings.ContainsRune(dir, filepath.Separator) && !strings.ContainsRune(dir, '/') {
+		dir = filepath.Join(paths.ArchiveRoot(rootDir), dir)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, FileName)); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("archive not found: %s", nameOrPath)
+		}
+		return "", err
+	}
+	return dir, nil
+}
+
+// Load reads the archive in dir.
+func Load(dir string) (Archive, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		return Archive{}, fmt.Errorf("read archive: %w", err)
+	}
+	var archive Archive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return Archive{}, fmt.Errorf("parse archive %s: %w", filepath.Join(dir, FileName), err)
+	}
+	if archive.Version != currentVersion {
+		return Archive{}, fmt.Errorf("archive %s: unsupported version %d", dir, archive.Version)
+	}
+	archive.Dir = dir
+	return archive, nil
+}
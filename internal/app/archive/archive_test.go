@@ -0,0 +1,254 @@
+package archive_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tasuku43/gion/internal/app/archive"
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+const repoSpec = "https://example.com/org/repo.git"
+
+var archivedAt = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func TestCreate_CleanBranchWritesNoBundleOrPatch(t *testing.T) {
+	ctx, rootDir, _ := setupWorkspace(t)
+	entry := manifest.Workspace{Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1", BaseRef: "origin/main"}}}
+
+	archived, err := archive.Create(ctx, rootDir, "WS-1", entry, archivedAt)
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if archived.Name() != "WS-1-20260102T030405Z" {
+		t.Fatalf("name = %q", archived.Name())
+	}
+	if len(archived.Repos) != 1 {
+		t.Fatalf("repos = %+v", archived.Repos)
+	}
+	r := archived.Repos[0]
+	if r.Branch != "WS-1" || r.BaseRef != "origin/main" || r.Ref != "" || r.Bundle != "" || r.Patch != "" {
+		t.Fatalf("unexpected repo: %+v", r)
+	}
+	files, err := os.ReadDir(archived.Dir)
+	if err != nil {
+		t.Fatalf("read archive dir: %v", err)
+	}
+	if len(files) != 1 || files[0].Name() != archive.FileName {
+		t.Fatalf("expected only %s, got %v", archive.FileName, files)
+	}
+
+	loaded, err := archive.Load(archived.Dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.WorkspaceID != "WS-1" || len(loaded.Repos) != 1 || loaded.Repos[0].Head != r.Head {
+		t.Fatalf("unexpected loaded archive: %+v", loaded)
+	}
+	if got := loaded.Entry(); len(got.Repos) != 1 || got.Repos[0].Branch != "WS-1" || got.Repos[0].BaseRef != "origin/main" {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+}
+
+func TestCreate_BundlesUnpushedCommitsAndPatchesBinaryFiles(t *testing.T) {
+	ctx, rootDir, _ := setupWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	commitFile(t, worktreePath, "feature.txt", "v1\n", "unpushed work")
+	head := runGit(t, worktreePath, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("v2\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	binary := []byte{0x00, 0x01, 0x02, 0xff, 0xfe, 0x00, 'g', 'i', 'o', 'n'}
+	if err := os.WriteFile(filepath.Join(worktreePath, "image.bin"), binary, 0o644); err != nil {
+		t.Fatalf("write binary: %v", err)
+	}
+	statusBefore := runGit(t, worktreePath, "status", "--porcelain")
+
+	entry := manifest.Workspace{Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1"}}}
+	archived, err := archive.Create(ctx, rootDir, "WS-1", entry, archivedAt)
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	r := archived.Repos[0]
+	if r.Head != head || r.Bundle != "repo.bundle" || r.Patch != "repo.patch" {
+		t.Fatalf("unexpected repo: %+v", r)
+	}
+	if heads := runGit(t, worktreePath, "bundle", "list-heads", filepath.Join(archived.Dir, r.Bundle)); !strings.Contains(heads, head+" refs/heads/WS-1") {
+		t.Fatalf("bundle heads = %q, want %s refs/heads/WS-1", heads, head)
+	}
+	patch, err := os.ReadFile(filepath.Join(archived.Dir, r.Patch))
+	if err != nil {
+		t.Fatalf("read patch: %v", err)
+	}
+	if !strings.Contains(string(patch), "GIT binary patch") || !strings.Contains(string(patch), "feature.txt") {
+		t.Fatalf("expected a binary patch with both files, got:\n%s", patch)
+	}
+	if got := runGit(t, worktreePath, "status", "--porcelain"); got != statusBefore {
+		t.Fatalf("archiving changed the worktree status:\n%s\nwant:\n%s", got, statusBefore)
+	}
+
+	runGit(t, worktreePath, "checkout", "--", ".")
+	runGit(t, worktreePath, "clean", "-fd")
+	if err := archived.RestoreChanges(ctx, worktreePath, r); err != nil {
+		t.Fatalf("restore changes: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "image.bin")); err != nil || !bytes.Equal(data, binary) {
+		t.Fatalf("binary file not restored: %v, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "feature.txt")); err != nil || string(data) != "v2\n" {
+		t.Fatalf("feature.txt not restored: %q, %v", data, err)
+	}
+}
+
+func TestCreate_PinnedRepoRecordsRefWithoutBundle(t *testing.T) {
+	ctx, rootDir, seedDir := setupWorkspace(t)
+	runGit(t, seedDir, "tag", "v1")
+	runGit(t, seedDir, "push", "origin", "v1")
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-PIN", workspace.Metadata{}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddDetached(ctx, rootDir, "WS-PIN", repoSpec, "", "v1", true); err != nil {
+		t.Fatalf("add detached: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-PIN", "repo")
+	commitFile(t, worktreePath, "detached.txt", "on top\n", "detached commit")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	entry := manifest.Workspace{Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Ref: "v1"}}}
+	archived, err := archive.Create(ctx, rootDir, "WS-PIN", entry, archivedAt)
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	r := archived.Repos[0]
+	if r.Branch != "" || r.Ref != "v1" || r.Bundle != "" || r.Patch == "" {
+		t.Fatalf("unexpected pinned repo: %+v", r)
+	}
+	if got := archived.Entry().Repos[0]; got.Ref != "v1" || got.Branch != "" {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+}
+
+func TestResolve_AcceptsNameOrPath(t *testing.T) {
+	ctx, rootDir, _ := setupWorkspace(t)
+	entry := manifest.Workspace{Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1"}}}
+	archived, err := archive.Create(ctx, rootDir, "WS-1", entry, archivedAt)
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	for _, input := range []string{archived.Name(), archived.Dir} {
+		dir, err := archive.Resolve(rootDir, input)
+		if err != nil {
+			t.Fatalf("resolve %q: %v", input, err)
+		}
+		if dir != archived.Dir {
+			t.Fatalf("resolve %q = %q, want %q", input, dir, archived.Dir)
+		}
+	}
+
+	t.Chdir(filepath.Dir(archived.Dir))
+	if dir, err := archive.Resolve(rootDir, "./"+archived.Name()); err != nil || dir != archived.Dir {
+		t.Fatalf("resolve relative path = %q, %v", dir, err)
+	}
+
+	if _, err := archive.Resolve(rootDir, "WS-1-missing"); err == nil || !strings.Contains(err.Error(), "archive not found") {
+		t.Fatalf("expected archive not found, got %v", err)
+	}
+	if _, err := archive.Resolve(rootDir, " "); err == nil {
+		t.Fatalf("expected error for empty archive")
+	}
+}
+
+func setupWorkspace(t *testing.T) (context.Context, string, string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	seedDir := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	return ctx, rootDir, seedDir
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) string {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	commitFile(t, seedDir, "README.md", "hello\n", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return seedDir
+}
+
+func commitFile(t *testing.T, dir, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", message)
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
@@ -0,0 +1,67 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+// RestoreBranch makes sure the archived branch exists in the repo store at the
+// archived commit: it is fetched from the bundle when the archive has one, or
+// created at the archived commit otherwise. A branch that already exists at
+// another commit is an error, so newer work is never overwritten.
+func (a Archive) RestoreBranch(ctx context.Context, storePath string, r Repo) error {
+	branch := strings.TrimSpace(r.Branch)
+	if branch == "" {
+		return fmt.Errorf("archive %s: repo %s has no branch", a.Name(), r.Alias)
+	}
+	sha, exists, err := gitcmd.ShowRef(ctx, storePath, "refs/heads/"+branch)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if strings.TrimSpace(sha) != r.Head {
+			return fmt.Errorf("branch %s already exists at %s (archived at %s)", branch, shortSHA(sha), shortSHA(r.Head))
+		}
+		return nil
+	}
+	if r.Bundle != "" {
+		bundlePath := filepath.Join(a.Dir, r.Bundle)
+		gitcmd.Logf("git fetch %s refs/heads/%s:refs/heads/%s", bundlePath, branch, branch)
+		return gitcmd.BundleFetchBranch(ctx, storePath, bundlePath, branch)
+	}
+	gitcmd.Logf("git branch %s %s", branch, r.Head)
+	res, err := gitcmd.Run(ctx, []string{"branch", branch, r.Head}, gitcmd.Options{Dir: storePath})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git branch failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git branch failed: %w", err)
+	}
+	return nil
+}
+
+// RestoreChanges reapplies the archived uncommitted and untracked changes of r
+// to the worktree. Restored changes are left unstaged.
+func (a Archive) RestoreChanges(ctx context.Context, worktreePath string, r Repo) error {
+	if r.Patch == "" {
+		return nil
+	}
+	patchPath := filepath.Join(a.Dir, r.Patch)
+	gitcmd.Logf("git apply --binary %s", patchPath)
+	if err := gitcmd.ApplyPatch(ctx, worktreePath, patchPath); err != nil {
+		return fmt.Errorf("restore changes of %s: %w", r.Alias, err)
+	}
+	return nil
+}
+
+func shortSHA(sha string) string {
+	sha = strings.TrimSpace(sha)
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -22,17 +22,23 @@ func CreateWorkspace(ctx context.Context, rootDir, workspaceID string, meta work
 	return wsDir, nil
 }
 
-func ApplyPreset(ctx context.Context, rootDir, workspaceID string, preset preset.Preset, branches []string, step PresetStepFunc) error {
-	total := len(preset.Repos)
-	for i, repoSpec := range preset.Repos {
-		branch := workspaceID
-		if len(branches) == len(preset.Repos) && i < len(branches) && strings.TrimSpace(branches[i]) != "" {
+// ApplyPreset adds the repos of a preset to the workspace. branches override the
+// per-repo defaults of the preset when set; alias and base ref come from the preset.
+func ApplyPreset(ctx context.Context, rootDir, workspaceID, presetName string, tmpl preset.Preset, branches []string, step PresetStepFunc) error {
+	defaults, err := preset.RepoBranches(tmpl, "", workspace.BranchTemplateVars{WorkspaceID: workspaceID, Preset: presetName})
+	if err != nil {
+		return err
+	}
+	total := len(tmpl.Repos)
+	for i, entry := range tmpl.Repos {
+		branch := defaults[i]
+		if len(branches) == len(tmpl.Repos) && i < len(branches) && strings.TrimSpace(branches[i]) != "" {
 			branch = branches[i]
 		}
 		if step != nil {
-			step(repoSpec, i, total)
+			step(entry.Repo, i, total)
 		}
-		if _, err := workspace.AddWithBranch(ctx, rootDir, workspaceID, repoSpec, "", branch, "", false); err != nil {
+		if _, err := workspace.AddWithBranch(ctx, rootDir, workspaceID, entry.Repo, entry.Alias, branch, entry.BaseRef, false); err != nil {
 			return err
 		}
 	}
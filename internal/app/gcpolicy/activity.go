@@ -0,0 +1,52 @@
+package gcpolicy
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+// Activity holds the observed lifetime of a workspace.
+type Activity struct {
+	// LastActive is the newest HEAD reflog entry or worktree mtime across repos.
+	LastActive time.Time
+	// Created is the oldest HEAD reflog entry across repos (zero when unknown).
+	Created time.Time
+}
+
+// WorkspaceActivity derives activity from each repo worktree's HEAD reflog
+// (commits, checkouts, resets) and the worktree directory mtime.
+func WorkspaceActivity(ctx context.Context, rootDir, workspaceID string) (Activity, error) {
+	repos, _, err := workspace.ScanRepos(ctx, workspace.WorkspaceDir(rootDir, workspaceID))
+	if err != nil {
+		return Activity{}, err
+	}
+	var activity Activity
+	for _, repo := range repos {
+		if info, err := os.Stat(repo.WorktreePath); err == nil {
+			activity.observeActive(info.ModTime())
+		}
+		times, err := gitcmd.ReflogTimes(ctx, repo.WorktreePath, "HEAD")
+		if err != nil {
+			return Activity{}, err
+		}
+		if len(times) == 0 {
+			continue
+		}
+		activity.observeActive(times[0])
+		oldest := times[len(times)-1]
+		if activity.Created.IsZero() || oldest.Before(activity.Created) {
+			activity.Created = oldest
+		}
+	}
+	return activity, nil
+}
+
+func (a *Activity) observeActive(t time.Time) {
+	if t.After(a.LastActive) {
+		a.LastActive = t
+	}
+}
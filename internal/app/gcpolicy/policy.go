@@ -0,0 +1,147 @@
+package gcpolicy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+// Facts describes a workspace for policy evaluation. Activity and Merged are
+// evaluated lazily (at most once) because they run git/provider commands.
+type Facts struct {
+	Mode      string
+	Labels    []string
+	ExpiresAt string
+	Activity  func() (Activity, error)
+	// Merged returns the merge reason (e.g. "merged (squash)", "pr merged") when the
+	// workspace's work has landed.
+	Merged func() (string, bool, error)
+}
+
+type Match struct {
+	Rule   string
+	Action string
+	Reason string
+}
+
+// Evaluate returns the first rule of policy whose conditions all match.
+// ok is false when no rule matches (the workspace is kept).
+func Evaluate(policy manifest.GCPolicy, facts Facts, now time.Time) (Match, bool, error) {
+	var activity *Activity
+	loadActivity := func() (Activity, error) {
+		if activity != nil {
+			return *activity, nil
+		}
+		if facts.Activity == nil {
+			return Activity{}, fmt.Errorf("activity unavailable")
+		}
+		a, err := facts.Activity()
+		if err != nil {
+			return Activity{}, err
+		}
+		activity = &a
+		return a, nil
+	}
+	mergedLoaded := false
+	mergedReason := ""
+	mergedOK := false
+	loadMerged := func() (string, bool, error) {
+		if mergedLoaded || facts.Merged == nil {
+			return mergedReason, mergedOK, nil
+		}
+		reason, ok, err := facts.Merged()
+		if err != nil {
+			return "", false, err
+		}
+		mergedLoaded, mergedReason, mergedOK = true, reason, ok
+		return reason, ok, nil
+	}
+
+	for i, rule := range policy.Rules {
+		var reasons []string
+
+		if mode := strings.TrimSpace(rule.Mode); mode != "" {
+			if strings.TrimSpace(facts.Mode) != mode {
+				continue
+			}
+			reasons = append(reasons, "mode "+mode)
+		}
+		if len(rule.Labels) > 0 {
+			labels := workspace.NormalizeLabels(rule.Labels)
+			if !workspace.MatchLabels(facts.Labels, labels) {
+				continue
+			}
+			reasons = append(reasons, "labels "+strings.Join(labels, ","))
+		}
+		if rule.Expired {
+			ws := manifest.Workspace{ExpiresAt: facts.ExpiresAt}
+			if !ws.Expired(now) {
+				continue
+			}
+			reasons = append(reasons, "expired")
+		}
+		if value := strings.TrimSpace(rule.InactiveFor); value != "" {
+			threshold, err := manifest.ParseGCDuration(value)
+			if err != nil {
+				return Match{}, false, err
+			}
+			a, err := loadActivity()
+			if err != nil {
+				return Match{}, false, err
+			}
+			if a.LastActive.IsZero() {
+				continue
+			}
+			idle := now.Sub(a.LastActive)
+			if idle < threshold {
+				continue
+			}
+			reasons = append(reasons, "inactive "+FormatAge(idle))
+		}
+		if value := strings.TrimSpace(rule.OlderThan); value != "" {
+			threshold, err := manifest.ParseGCDuration(value)
+			if err != nil {
+				return Match{}, false, err
+			}
+			a, err := loadActivity()
+			if err != nil {
+				return Match{}, false, err
+			}
+			if a.Created.IsZero() {
+				continue
+			}
+			age := now.Sub(a.Created)
+			if age < threshold {
+				continue
+			}
+			reasons = append(reasons, "age "+FormatAge(age))
+		}
+		if rule.Merged {
+			reason, ok, err := loadMerged()
+			if err != nil {
+				return Match{}, false, err
+			}
+			if !ok {
+				continue
+			}
+			reasons = append(reasons, reason)
+		}
+		return Match{
+			Rule:   rule.Label(i),
+			Action: strings.TrimSpace(rule.Action),
+			Reason: strings.Join(reasons, ", "),
+		}, true, nil
+	}
+	return Match{}, false, nil
+}
+
+// FormatAge renders a duration in whole days, or hours when shorter than a day.
+func FormatAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return fmt.Sprintf("%dh", int(d/time.Hour))
+}
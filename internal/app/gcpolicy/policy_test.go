@@ -0,0 +1,103 @@
+package gcpolicy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policy := manifest.GCPolicy{Rules: []manifest.GCRule{
+		{Name: "pinned", Action: manifest.GCActionKeep, Labels: []string{"keep"}},
+		{Name: "protect-resume", Action: manifest.GCActionKeep, Mode: "resume"},
+		{Action: manifest.GCActionRemove, Mode: "review", InactiveFor: "14d"},
+		{Name: "expired", Action: manifest.GCActionRemove, Expired: true},
+		{Name: "merged-issues", Action: manifest.GCActionRemove, Mode: "issue", Merged: true},
+	}}
+	activity := func(lastActive time.Time) func() (Activity, error) {
+		return func() (Activity, error) { return Activity{LastActive: lastActive}, nil }
+	}
+	merged := func(reason string, ok bool) func() (string, bool, error) {
+		return func() (string, bool, error) { return reason, ok, nil }
+	}
+
+	cases := []struct {
+		name      string
+		facts     Facts
+		wantOK    bool
+		wantMatch Match
+	}{
+		{
+			name:      "keep rule wins",
+			facts:     Facts{Mode: "resume"},
+			wantOK:    true,
+			wantMatch: Match{Rule: "protect-resume", Action: "keep", Reason: "mode resume"},
+		},
+		{
+			name:      "stale review",
+			facts:     Facts{Mode: "review", Activity: activity(now.Add(-16 * 24 * time.Hour))},
+			wantOK:    true,
+			wantMatch: Match{Rule: "rule 3", Action: "remove", Reason: "mode review, inactive 16d"},
+		},
+		{
+			name:      "labeled stale review is kept",
+			facts:     Facts{Mode: "review", Labels: []string{"backend", "keep"}, Activity: activity(now.Add(-30 * 24 * time.Hour))},
+			wantOK:    true,
+			wantMatch: Match{Rule: "pinned", Action: "keep", Reason: "labels keep"},
+		},
+		{
+			name:  "recent review",
+			facts: Facts{Mode: "review", Activity: activity(now.Add(-2 * time.Hour))},
+		},
+		{
+			name:      "merged issue",
+			facts:     Facts{Mode: "issue", Merged: merged("merged (squash)", true)},
+			wantOK:    true,
+			wantMatch: Match{Rule: "merged-issues", Action: "remove", Reason: "mode issue, merged (squash)"},
+		},
+		{
+			name:  "unmerged issue",
+			facts: Facts{Mode: "issue", Merged: merged("", false)},
+		},
+		{
+			name:      "expired scratch",
+			facts:     Facts{Mode: "repo", ExpiresAt: "2026-02-28T00:00:00Z"},
+			wantOK:    true,
+			wantMatch: Match{Rule: "expired", Action: "remove", Reason: "expired"},
+		},
+		{
+			name:  "not yet expired",
+			facts: Facts{Mode: "repo", ExpiresAt: "2026-03-02T00:00:00Z"},
+		},
+		{
+			name:  "unmatched mode",
+			facts: Facts{Mode: "repo"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, ok, err := Evaluate(policy, tc.facts, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tc.wantOK || match != tc.wantMatch {
+				t.Fatalf("got (%+v, %v), want (%+v, %v)", match, ok, tc.wantMatch, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestEvaluate_ActivityErrorIsReturned(t *testing.T) {
+	policy := manifest.GCPolicy{Rules: []manifest.GCRule{
+		{Action: manifest.GCActionRemove, InactiveFor: "1d"},
+	}}
+	_, _, err := Evaluate(policy, Facts{Activity: func() (Activity, error) {
+		return Activity{}, errors.New("boom")
+	}}, time.Now())
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
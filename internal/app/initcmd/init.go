@@ -58,7 +58,7 @@ func Run(rootDir string) (Result, error) {
 
 func writeManifest(path string) error {
 	file := manifest.File{
-		Version:    1,
+		Version:    manifest.CurrentVersion,
 		Workspaces: map[string]manifest.Workspace{},
 		Presets:    map[string]manifest.Preset{},
 	}
@@ -30,26 +30,30 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 	if strings.TrimSpace(rootDir) == "" {
 		return manifest.File{}, nil, fmt.Errorf("root directory is required")
 	}
+	file := manifest.File{
+		Version:    manifest.CurrentVersion,
+		Workspaces: map[string]manifest.Workspace{},
+	}
+	if existing, err := manifest.Load(rootDir); err == nil {
+		// Only workspaces are rebuilt. The schema version (upgrading is left to
+		// `gion manifest migrate`), presets, gc policies and includes are kept, and
+		// imported workspaces are saved back to the files that own them.
+		file = existing
+		file.Workspaces = map[string]manifest.Workspace{}
+	}
+
 	wsRoot := paths.WorkspacesRoot(rootDir)
 	exists, err := paths.DirExists(wsRoot)
 	if err != nil {
 		return manifest.File{}, nil, err
 	}
 	if !exists {
-		return manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{}}, nil, nil
+		return file, nil, nil
 	}
 	entries, err := os.ReadDir(wsRoot)
 	if err != nil {
 		return manifest.File{}, nil, err
 	}
-
-	file := manifest.File{
-		Version:    1,
-		Workspaces: map[string]manifest.Workspace{},
-	}
-	if existing, err := manifest.Load(rootDir); err == nil {
-		file.Presets = existing.Presets
-	}
 	var warnings []error
 
 	var workspaceNames []string
@@ -62,6 +66,8 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 	workspaceIDs := coreimportplan.CollectWorkspaceIDs(workspaceNames)
 
 	snapshots := make([]coreimportplan.WorkspaceSnapshot, 0, len(workspaceIDs))
+	// Labels, expiry and pinned refs are not part of the core snapshot; they are carried over below.
+	extrasByID := map[string]workspace.Metadata{}
 
 	for _, wsID := range workspaceIDs {
 		wsDir := workspace.WorkspaceDir(rootDir, wsID)
@@ -81,8 +87,18 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 			}
 		}
 
+		pins := map[string]string{}
 		repoEntries := make([]coreimportplan.RepoSnapshot, 0, len(repos))
 		for _, repoEntry := range repos {
+			if strings.TrimSpace(repoEntry.Branch) == "" {
+				// Detached worktree: record the pinned ref (or the HEAD commit if it moved away).
+				ref, err := workspace.DetachedRef(ctx, repoEntry.WorktreePath, meta.Pins[repoEntry.Alias])
+				if err != nil {
+					warnings = append(warnings, fmt.Errorf("workspace %s repo %s: %w", wsID, repoEntry.Alias, err))
+				} else {
+					pins[strings.TrimSpace(repoEntry.Alias)] = ref
+				}
+			}
 			repoEntries = append(repoEntries, coreimportplan.RepoSnapshot{
 				Alias:   strings.TrimSpace(repoEntry.Alias),
 				RepoKey: strings.TrimSpace(repoEntry.RepoKey),
@@ -90,6 +106,10 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 			})
 		}
 
+		if labels, expiresAt := workspace.NormalizeLabels(meta.Labels), strings.TrimSpace(meta.ExpiresAt); len(labels) > 0 || expiresAt != "" || len(pins) > 0 {
+			extrasByID[wsID] = workspace.Metadata{Labels: labels, ExpiresAt: expiresAt, Pins: pins}
+		}
+
 		snapshots = append(snapshots, coreimportplan.WorkspaceSnapshot{
 			ID:          wsID,
 			Description: strings.TrimSpace(meta.Description),
@@ -101,6 +121,19 @@ func Build(ctx context.Context, rootDir string) (manifest.File, []error, error)
 		})
 	}
 	file.Workspaces = toManifestWorkspaces(coreimportplan.BuildInventory(snapshots).Workspaces)
+	for id, extra := range extrasByID {
+		if ws, ok := file.Workspaces[id]; ok {
+			ws.Labels = extra.Labels
+			ws.ExpiresAt = extra.ExpiresAt
+			for i, repoEntry := range ws.Repos {
+				if ref, ok := extra.Pins[repoEntry.Alias]; ok {
+					ws.Repos[i].Ref = ref
+					ws.Repos[i].BaseRef = ""
+				}
+			}
+			file.Workspaces[id] = ws
+		}
+	}
 
 	return file, warnings, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	coremanifestlsplan "github.com/tasuku43/gion-core/manifestlsplan"
 	coreworkspacerisk "github.com/tasuku43/gion-core/workspacerisk"
@@ -25,6 +26,8 @@ type Entry struct {
 	Drift        DriftStatus
 	Risk         workspace.WorkspaceStateKind
 	Description  string
+	Labels       []string
+	Expired      bool
 	HasWorkspace bool
 }
 
@@ -37,12 +40,15 @@ type Result struct {
 	Warnings        []error
 }
 
-func List(ctx context.Context, rootDir string) (Result, error) {
+// List classifies workspaces in gion.yaml and on disk. When labelFilter is non-empty,
+// only workspaces carrying every label are listed (and counted).
+func List(ctx context.Context, rootDir string, labelFilter []string) (Result, error) {
 	plan, err := manifestplan.Plan(ctx, rootDir)
 	if err != nil {
 		return Result{}, err
 	}
 	desired := plan.Desired
+	now := time.Now()
 
 	fsWorkspaces, fsWarnings, err := workspace.List(rootDir)
 	if err != nil {
@@ -65,6 +71,10 @@ func List(ctx context.Context, rootDir string) (Result, error) {
 
 	entries := make([]Entry, 0, len(layout.ManifestEntries))
 	for _, manifestEntry := range layout.ManifestEntries {
+		labels := workspace.NormalizeLabels(desired.Workspaces[manifestEntry.WorkspaceID].Labels)
+		if !workspace.MatchLabels(labels, labelFilter) {
+			continue
+		}
 		risk := workspace.WorkspaceStateClean
 		if manifestEntry.HasWorkspace {
 			state, warn := bestEffortWorkspaceRisk(ctx, rootDir, manifestEntry.WorkspaceID)
@@ -78,12 +88,18 @@ func List(ctx context.Context, rootDir string) (Result, error) {
 			Drift:        manifestEntry.Drift,
 			Risk:         risk,
 			Description:  manifestEntry.Description,
+			Labels:       labels,
+			Expired:      desired.Workspaces[manifestEntry.WorkspaceID].Expired(now),
 			HasWorkspace: manifestEntry.HasWorkspace,
 		})
 	}
 
 	var extras []Entry
 	for _, extra := range layout.ExtraEntries {
+		labels := workspace.NormalizeLabels(plan.Actual.Workspaces[extra.WorkspaceID].Labels)
+		if !workspace.MatchLabels(labels, labelFilter) {
+			continue
+		}
 		risk, warn := bestEffortWorkspaceRisk(ctx, rootDir, extra.WorkspaceID)
 		if warn != nil {
 			warnings = append(warnings, warn)
@@ -92,18 +108,41 @@ func List(ctx context.Context, rootDir string) (Result, error) {
 			WorkspaceID:  extra.WorkspaceID,
 			Drift:        extra.Drift,
 			Risk:         risk,
+			Labels:       labels,
+			Expired:      plan.Actual.Workspaces[extra.WorkspaceID].Expired(now),
 			HasWorkspace: true,
 		})
 	}
 
+	counts := layout.Counts
+	if len(labelFilter) > 0 {
+		counts = countEntries(entries, extras)
+	}
+
 	return Result{
 		ManifestEntries: entries,
 		ExtraEntries:    extras,
-		Counts:          layout.Counts,
+		Counts:          counts,
 		Warnings:        warnings,
 	}, nil
 }
 
+func countEntries(entries, extras []Entry) Counts {
+	var counts Counts
+	for _, entry := range entries {
+		switch entry.Drift {
+		case DriftApplied:
+			counts.Applied++
+		case DriftDrift:
+			counts.Drift++
+		case DriftMissing:
+			counts.Missing++
+		}
+	}
+	counts.Extra = len(extras)
+	return counts
+}
+
 func bestEffortWorkspaceRisk(ctx context.Context, rootDir, workspaceID string) (workspace.WorkspaceStateKind, error) {
 	state, err := workspace.State(ctx, rootDir, workspaceID)
 	if err != nil {
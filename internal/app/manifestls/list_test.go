@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
 )
 
 func TestList_ClassifiesAppliedMissingDriftExtra(t *testing.T) {
@@ -40,7 +41,7 @@ func TestList_ClassifiesAppliedMissingDriftExtra(t *testing.T) {
 		t.Fatalf("save manifest: %v", err)
 	}
 
-	result, err := List(ctx, rootDir)
+	result, err := List(ctx, rootDir, nil)
 	if err != nil {
 		t.Fatalf("List: %v", err)
 	}
@@ -67,3 +68,47 @@ func TestList_ClassifiesAppliedMissingDriftExtra(t *testing.T) {
 		t.Fatalf("unexpected extras: %+v", result.ExtraEntries)
 	}
 }
+
+func TestList_FiltersByLabels(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+
+	for _, id := range []string{"WS_BACKEND", "WS_FRONTEND", "WS_EXTRA"} {
+		if err := os.MkdirAll(filepath.Join(rootDir, "workspaces", id), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", id, err)
+		}
+	}
+	if err := workspace.SaveMetadata(filepath.Join(rootDir, "workspaces", "WS_EXTRA"), workspace.Metadata{Labels: []string{"backend"}}); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+
+	file := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS_BACKEND":  {Labels: []string{"backend", "team=payments"}},
+			"WS_FRONTEND": {Labels: []string{"frontend"}},
+			"WS_MISSING":  {Labels: []string{"backend"}},
+		},
+	}
+	if err := manifest.Save(rootDir, file); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	result, err := List(ctx, rootDir, []string{"backend"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var ids []string
+	for _, entry := range result.ManifestEntries {
+		ids = append(ids, entry.WorkspaceID)
+	}
+	if len(ids) != 2 || ids[0] != "WS_BACKEND" || ids[1] != "WS_MISSING" {
+		t.Fatalf("unexpected manifest entries: %v", ids)
+	}
+	if len(result.ExtraEntries) != 1 || result.ExtraEntries[0].WorkspaceID != "WS_EXTRA" {
+		t.Fatalf("unexpected extras: %+v", result.ExtraEntries)
+	}
+	if result.Counts.Applied != 1 || result.Counts.Missing != 1 || result.Counts.Extra != 1 || result.Counts.Drift != 0 {
+		t.Fatalf("unexpected counts: %+v", result.Counts)
+	}
+}
@@ -0,0 +1,34 @@
+package manifestplan
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+type ExpiryUpdate struct {
+	WorkspaceID string
+	From        string
+	To          string
+}
+
+func diffExpiry(desired, actual manifest.File) []ExpiryUpdate {
+	var updates []ExpiryUpdate
+	for id, ws := range desired.Workspaces {
+		current, ok := actual.Workspaces[id]
+		if !ok {
+			continue
+		}
+		from := strings.TrimSpace(current.ExpiresAt)
+		to := strings.TrimSpace(ws.ExpiresAt)
+		if from == to {
+			continue
+		}
+		updates = append(updates, ExpiryUpdate{WorkspaceID: id, From: from, To: to})
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].WorkspaceID < updates[j].WorkspaceID
+	})
+	return updates
+}
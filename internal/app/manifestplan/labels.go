@@ -0,0 +1,114 @@
+package manifestplan
+
+import (
+	"sort"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+type LabelUpdate struct {
+	WorkspaceID string
+	From        []string
+	To          []string
+}
+
+func diffLabels(desired, actual manifest.File) []LabelUpdate {
+	var updates []LabelUpdate
+	for id, ws := range desired.Workspaces {
+		current, ok := actual.Workspaces[id]
+		if !ok || workspace.EqualLabels(ws.Labels, current.Labels) {
+			continue
+		}
+		updates = append(updates, LabelUpdate{
+			WorkspaceID: id,
+			From:        workspace.NormalizeLabels(current.Labels),
+			To:          workspace.NormalizeLabels(ws.Labels),
+		})
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].WorkspaceID < updates[j].WorkspaceID
+	})
+	return updates
+}
+
+// InScope reports whether a workspace is covered by the plan's label filter.
+// A workspace matches when either its desired labels (gion.yaml) or its current
+// labels (metadata) contain every filter label, so removals and relabels are
+// selectable by the labels they had before. Both workspaces of a repo move are
+// in scope when either one is.
+func (r Result) InScope(workspaceID string) bool {
+	if len(r.LabelFilter) == 0 || r.moveScope[workspaceID] {
+		return true
+	}
+	if ws, ok := r.Desired.Workspaces[workspaceID]; ok && workspace.MatchLabels(ws.Labels, r.LabelFilter) {
+		return true
+	}
+	if ws, ok := r.Actual.Workspaces[workspaceID]; ok && workspace.MatchLabels(ws.Labels, r.LabelFilter) {
+		return true
+	}
+	return false
+}
+
+// FilterByLabels narrows the plan to workspaces carrying every label in filter.
+func FilterByLabels(result Result, filter []string) Result {
+	filter = workspace.NormalizeLabels(filter)
+	if len(filter) == 0 {
+		return result
+	}
+	result.LabelFilter = filter
+	// Actual already has moved repos at their new place, so a move cannot be split:
+	// bring the other side into scope (repeatedly, as moves can chain workspaces).
+	result.moveScope = map[string]bool{}
+	for grown := true; grown; {
+		grown = false
+		for _, move := range result.Moves {
+			if result.InScope(move.FromWorkspace) != result.InScope(move.ToWorkspace) {
+				result.moveScope[move.FromWorkspace] = true
+				result.moveScope[move.ToWorkspace] = true
+				grown = true
+			}
+		}
+	}
+
+	var changes []WorkspaceChange
+	for _, change := range result.Changes {
+		if result.InScope(change.WorkspaceID) {
+			changes = append(changes, change)
+		}
+	}
+	result.Changes = changes
+
+	var renames []WorkspaceRename
+	for _, rename := range result.Renames {
+		if result.InScope(rename.To) {
+			renames = append(renames, rename)
+		}
+	}
+	result.Renames = renames
+
+	var moves []RepoMove
+	for _, move := range result.Moves {
+		if result.InScope(move.FromWorkspace) {
+			moves = append(moves, move)
+		}
+	}
+	result.Moves = moves
+
+	var updates []LabelUpdate
+	for _, update := range result.LabelUpdates {
+		if result.InScope(update.WorkspaceID) {
+			updates = append(updates, update)
+		}
+	}
+	result.LabelUpdates = updates
+
+	var expiry []ExpiryUpdate
+	for _, update := range result.ExpiryUpdates {
+		if result.InScope(update.WorkspaceID) {
+			expiry = append(expiry, update)
+		}
+	}
+	result.ExpiryUpdates = expiry
+	return result
+}
@@ -0,0 +1,100 @@
+package manifestplan
+
+import (
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestDiffLabels(t *testing.T) {
+	desired := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-SAME":    {Labels: []string{"b", "a"}},
+		"WS-CHANGED": {Labels: []string{"backend"}},
+		"WS-NEW":     {Labels: []string{"backend"}},
+	}}
+	actual := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-SAME":    {Labels: []string{"a", "b"}},
+		"WS-CHANGED": {Labels: []string{"frontend"}},
+	}}
+	updates := diffLabels(desired, actual)
+	if len(updates) != 1 || updates[0].WorkspaceID != "WS-CHANGED" {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+	if updates[0].From[0] != "frontend" || updates[0].To[0] != "backend" {
+		t.Fatalf("unexpected update: %+v", updates[0])
+	}
+}
+
+func TestFilterByLabels(t *testing.T) {
+	result := Result{
+		Desired: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-ADD":     {Labels: []string{"backend"}},
+			"WS-OTHER":   {Labels: []string{"frontend"}},
+			"WS-RELABEL": {Labels: []string{"frontend"}},
+		}},
+		Actual: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-REMOVE":  {Labels: []string{"backend", "old"}},
+			"WS-RELABEL": {Labels: []string{"backend"}},
+		}},
+		Changes: []WorkspaceChange{
+			{Kind: WorkspaceAdd, WorkspaceID: "WS-ADD"},
+			{Kind: WorkspaceAdd, WorkspaceID: "WS-OTHER"},
+			{Kind: WorkspaceRemove, WorkspaceID: "WS-REMOVE"},
+		},
+		LabelUpdates: []LabelUpdate{{WorkspaceID: "WS-RELABEL", From: []string{"backend"}, To: []string{"frontend"}}},
+	}
+
+	filtered := FilterByLabels(result, []string{"backend"})
+	var ids []string
+	for _, change := range filtered.Changes {
+		ids = append(ids, change.WorkspaceID)
+	}
+	if len(ids) != 2 || ids[0] != "WS-ADD" || ids[1] != "WS-REMOVE" {
+		t.Fatalf("unexpected changes: %v", ids)
+	}
+	if len(filtered.LabelUpdates) != 1 {
+		t.Fatalf("expected relabel to stay in scope: %+v", filtered.LabelUpdates)
+	}
+	if filtered.InScope("WS-OTHER") {
+		t.Fatalf("WS-OTHER should be out of scope")
+	}
+	if !filtered.HasChanges() {
+		t.Fatalf("expected changes")
+	}
+
+	if none := FilterByLabels(result, []string{"missing"}); none.HasChanges() {
+		t.Fatalf("expected no changes for unmatched filter")
+	}
+}
+
+func TestFilterByLabels_MoveBringsOtherWorkspaceIntoScope(t *testing.T) {
+	result := Result{
+		Desired: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-SRC": {Labels: []string{"backend"}},
+			"WS-DST": {Labels: []string{"frontend"}},
+		}},
+		Actual: manifest.File{Workspaces: map[string]manifest.Workspace{
+			"WS-SRC": {Labels: []string{"backend"}},
+			"WS-DST": {Labels: []string{"frontend"}},
+		}},
+		Changes: []WorkspaceChange{
+			{Kind: WorkspaceUpdate, WorkspaceID: "WS-DST"},
+		},
+		Moves: []RepoMove{{FromWorkspace: "WS-SRC", FromAlias: "api", ToWorkspace: "WS-DST", ToAlias: "api", RepoKey: "example.com/org/api.git", Branch: "main"}},
+	}
+
+	filtered := FilterByLabels(result, []string{"backend"})
+	if len(filtered.Moves) != 1 {
+		t.Fatalf("expected move to stay in the plan: %+v", filtered.Moves)
+	}
+	if !filtered.InScope("WS-SRC") || !filtered.InScope("WS-DST") {
+		t.Fatalf("expected both sides of the move in scope")
+	}
+	if len(filtered.Changes) != 1 || filtered.Changes[0].WorkspaceID != "WS-DST" {
+		t.Fatalf("expected the destination's changes to stay in the plan: %+v", filtered.Changes)
+	}
+
+	if none := FilterByLabels(result, []string{"docs"}); none.HasChanges() || none.InScope("WS-DST") {
+		t.Fatalf("expected no changes for unmatched filter")
+	}
+}
@@ -0,0 +1,124 @@
+package manifestplan
+
+import (
+	"sort"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+// RepoMove moves an existing worktree to another alias and/or workspace. It is
+// detected when a repo disappears from one place in gion.yaml and the same
+// repo_key and branch (or pinned ref) appears in exactly one other place.
+type RepoMove struct {
+	FromWorkspace string
+	FromAlias     string
+	ToWorkspace   string
+	ToAlias       string
+	RepoKey       string
+	// Branch is the planner branch (see PlanBranch).
+	Branch string
+}
+
+type repoSlot struct {
+	workspaceID string
+	alias       string
+}
+
+// diffRepoMoves pairs worktrees that only changed place in gion.yaml and returns
+// actual with those repos already at their new place, so the rest of the plan
+// does not see them as a remove plus an add. A move needs an unambiguous match
+// (one source, one destination), a destination workspace that already exists,
+// and a free destination alias; anything else stays a remove plus an add.
+func diffRepoMoves(desired, actual manifest.File) ([]RepoMove, manifest.File) {
+	identity := func(repoEntry manifest.Repo) string {
+		return repoEntry.RepoKey + "\x00" + PlanBranch(repoEntry)
+	}
+	lookup := func(file manifest.File, slot repoSlot) (manifest.Repo, bool) {
+		for _, repoEntry := range file.Workspaces[slot.workspaceID].Repos {
+			if repoEntry.Alias == slot.alias {
+				return repoEntry, true
+			}
+		}
+		return manifest.Repo{}, false
+	}
+
+	sources := map[string][]repoSlot{}
+	for id, ws := range actual.Workspaces {
+		for _, repoEntry := range ws.Repos {
+			if PlanBranch(repoEntry) == "" {
+				continue
+			}
+			slot := repoSlot{workspaceID: id, alias: repoEntry.Alias}
+			if want, ok := lookup(desired, slot); ok && identity(want) == identity(repoEntry) {
+				continue
+			}
+			sources[identity(repoEntry)] = append(sources[identity(repoEntry)], slot)
+		}
+	}
+	destinations := map[string][]repoSlot{}
+	for id, ws := range desired.Workspaces {
+		if _, ok := actual.Workspaces[id]; !ok {
+			continue
+		}
+		for _, repoEntry := range ws.Repos {
+			slot := repoSlot{workspaceID: id, alias: repoEntry.Alias}
+			if _, occupied := lookup(actual, slot); occupied {
+				continue
+			}
+			destinations[identity(repoEntry)] = append(destinations[identity(repoEntry)], slot)
+		}
+	}
+
+	var moves []RepoMove
+	for key, from := range sources {
+		to := destinations[key]
+		if len(from) != 1 || len(to) != 1 {
+			continue
+		}
+		repoEntry, _ := lookup(actual, from[0])
+		moves = append(moves, RepoMove{
+			FromWorkspace: from[0].workspaceID,
+			FromAlias:     from[0].alias,
+			ToWorkspace:   to[0].workspaceID,
+			ToAlias:       to[0].alias,
+			RepoKey:       repoEntry.RepoKey,
+			Branch:        PlanBranch(repoEntry),
+		})
+	}
+	if len(moves) == 0 {
+		return nil, actual
+	}
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].ToWorkspace != moves[j].ToWorkspace {
+			return moves[i].ToWorkspace < moves[j].ToWorkspace
+		}
+		return moves[i].ToAlias < moves[j].ToAlias
+	})
+
+	moved := actual
+	moved.Workspaces = make(map[string]manifest.Workspace, len(actual.Workspaces))
+	for id, ws := range actual.Workspaces {
+		ws.Repos = append([]manifest.Repo(nil), ws.Repos...)
+		moved.Workspaces[id] = ws
+	}
+	for _, move := range moves {
+		from := moved.Workspaces[move.FromWorkspace]
+		var repoEntry manifest.Repo
+		repos := make([]manifest.Repo, 0, len(from.Repos))
+		for _, r := range from.Repos {
+			if r.Alias == move.FromAlias {
+				repoEntry = r
+				continue
+			}
+			repos = append(repos, r)
+		}
+		from.Repos = repos
+		moved.Workspaces[move.FromWorkspace] = from
+
+		to := moved.Workspaces[move.ToWorkspace]
+		repoEntry.Alias = move.ToAlias
+		to.Repos = append(to.Repos, repoEntry)
+		moved.Workspaces[move.ToWorkspace] = to
+	}
+	return moves, moved
+}
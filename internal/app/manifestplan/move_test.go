@@ -0,0 +1,78 @@
+package manifestplan
+
+import (
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestDiffRepoMoves(t *testing.T) {
+	api := manifest.Repo{Alias: "api", RepoKey: "github.com/org/api.git", Branch: "WS-1"}
+	web := manifest.Repo{Alias: "web", RepoKey: "github.com/org/web.git", Branch: "WS-1"}
+	lib := manifest.Repo{Alias: "lib", RepoKey: "github.com/org/lib.git", Ref: "v1.0.0"}
+	actual := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-1": {Repos: []manifest.Repo{api, web, lib}},
+		"WS-2": {},
+	}}
+
+	renamedAPI := api
+	renamedAPI.Alias = "backend"
+	desired := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-1": {Repos: []manifest.Repo{renamedAPI}},
+		"WS-2": {Repos: []manifest.Repo{web, lib}},
+		// WS-3 is new, so only WS-2 is a move destination for web.
+		"WS-3": {Repos: []manifest.Repo{web}},
+	}}
+
+	moves, moved := diffRepoMoves(desired, actual)
+	want := []RepoMove{
+		{FromWorkspace: "WS-1", FromAlias: "api", ToWorkspace: "WS-1", ToAlias: "backend", RepoKey: api.RepoKey, Branch: "WS-1"},
+		{FromWorkspace: "WS-1", FromAlias: "lib", ToWorkspace: "WS-2", ToAlias: "lib", RepoKey: lib.RepoKey, Branch: PlanBranch(lib)},
+		{FromWorkspace: "WS-1", FromAlias: "web", ToWorkspace: "WS-2", ToAlias: "web", RepoKey: web.RepoKey, Branch: "WS-1"},
+	}
+	if len(moves) != len(want) {
+		t.Fatalf("moves = %+v, want %+v", moves, want)
+	}
+	for i := range want {
+		if moves[i] != want[i] {
+			t.Fatalf("moves[%d] = %+v, want %+v", i, moves[i], want[i])
+		}
+	}
+	if got := moved.Workspaces["WS-1"].Repos; len(got) != 1 || got[0].Alias != "backend" {
+		t.Fatalf("unexpected moved WS-1: %+v", got)
+	}
+	if got := moved.Workspaces["WS-2"].Repos; len(got) != 2 {
+		t.Fatalf("unexpected moved WS-2: %+v", got)
+	}
+	if len(actual.Workspaces["WS-1"].Repos) != 3 {
+		t.Fatalf("actual must not be modified")
+	}
+}
+
+func TestDiffRepoMoves_AmbiguousOrOccupied(t *testing.T) {
+	api := manifest.Repo{Alias: "api", RepoKey: "github.com/org/api.git", Branch: "main"}
+	other := manifest.Repo{Alias: "api", RepoKey: "github.com/org/other.git", Branch: "main"}
+	actual := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-1": {Repos: []manifest.Repo{api}},
+		"WS-2": {Repos: []manifest.Repo{other}},
+		"WS-3": {},
+		"WS-4": {},
+	}}
+	desired := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-1": {},
+		// Occupied destination: WS-2/api is still another repo on disk.
+		"WS-2": {Repos: []manifest.Repo{api}},
+		"WS-3": {},
+		"WS-4": {},
+	}}
+	if moves, _ := diffRepoMoves(desired, actual); len(moves) != 0 {
+		t.Fatalf("expected no moves into an occupied alias, got %+v", moves)
+	}
+
+	desired.Workspaces["WS-2"] = manifest.Workspace{Repos: []manifest.Repo{other}}
+	desired.Workspaces["WS-3"] = manifest.Workspace{Repos: []manifest.Repo{api}}
+	desired.Workspaces["WS-4"] = manifest.Workspace{Repos: []manifest.Repo{api}}
+	if moves, _ := diffRepoMoves(desired, actual); len(moves) != 0 {
+		t.Fatalf("expected no moves with two destinations, got %+v", moves)
+	}
+}
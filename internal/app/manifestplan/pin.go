@@ -0,0 +1,29 @@
+package manifestplan
+
+import (
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+// pinnedBranchPrefix marks planner branches that stand for a detached worktree pinned to a ref.
+// ":" is not allowed in git branch names, so the encoded value never collides with a real branch.
+const pinnedBranchPrefix = "detached:"
+
+// PlanBranch returns the value the planner compares for a repo entry:
+// the branch, or an encoded "detached:<ref>" for repos pinned with ref.
+func PlanBranch(repoEntry manifest.Repo) string {
+	if ref := strings.TrimSpace(repoEntry.Ref); ref != "" {
+		return pinnedBranchPrefix + ref
+	}
+	return strings.TrimSpace(repoEntry.Branch)
+}
+
+// PinnedRef decodes a planner branch produced by PlanBranch for a pinned repo.
+func PinnedRef(branch string) (string, bool) {
+	branch = strings.TrimSpace(branch)
+	if !strings.HasPrefix(branch, pinnedBranchPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(branch, pinnedBranchPrefix), true
+}
@@ -0,0 +1,39 @@
+package manifestplan
+
+import (
+	"testing"
+
+	coreplanner "github.com/tasuku43/gion-core/planner"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestPlanBranch_PinnedRoundTrip(t *testing.T) {
+	if got := PlanBranch(manifest.Repo{Branch: "main"}); got != "main" {
+		t.Fatalf("PlanBranch(branch) = %q", got)
+	}
+	encoded := PlanBranch(manifest.Repo{Ref: "v2.3.1"})
+	ref, ok := PinnedRef(encoded)
+	if !ok || ref != "v2.3.1" {
+		t.Fatalf("PinnedRef(%q) = (%q, %v)", encoded, ref, ok)
+	}
+	if _, ok := PinnedRef("main"); ok {
+		t.Fatalf("branch must not decode as pinned")
+	}
+}
+
+func TestToInventory_PinChangeIsRepoUpdate(t *testing.T) {
+	desired := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"REPRO-1": {Repos: []manifest.Repo{{Alias: "app", RepoKey: "example.com/org/app.git", Ref: "v2.3.1"}}},
+	}}
+	actual := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"REPRO-1": {Repos: []manifest.Repo{{Alias: "app", RepoKey: "example.com/org/app.git", Branch: "REPRO-1"}}},
+	}}
+	changes := coreplanner.Diff(toInventory(desired), toInventory(actual))
+	if len(changes) != 1 || len(changes[0].Repos) != 1 {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+	change := changes[0].Repos[0]
+	if change.Kind != RepoUpdate || change.FromBranch != "REPRO-1" || change.ToBranch != "detached:v2.3.1" {
+		t.Fatalf("unexpected repo change: %+v", change)
+	}
+}
@@ -9,10 +9,33 @@ import (
 )
 
 type Result struct {
-	Desired  manifest.File
+	Desired manifest.File
+	// Actual is the current state with renamed workspaces already under their new ID
+	// and moved repos already at their new place.
 	Actual   manifest.File
 	Changes  []WorkspaceChange
 	Warnings []error
+	// Renames lists existing workspaces moved to a new ID (renamed_from in gion.yaml).
+	// Apply performs them before any other change.
+	Renames []WorkspaceRename
+	// Moves lists existing worktrees moved to another alias or workspace. Apply
+	// performs them right after the renames.
+	Moves []RepoMove
+	// LabelUpdates lists existing workspaces whose labels differ between gion.yaml and
+	// their metadata. The core planner does not track labels, so they are diffed here.
+	LabelUpdates []LabelUpdate
+	// ExpiryUpdates lists existing workspaces whose expires_at differs from their metadata.
+	ExpiryUpdates []ExpiryUpdate
+	// LabelFilter is set when the plan was narrowed with FilterByLabels.
+	LabelFilter []string
+	// moveScope lists workspaces brought into the filter's scope by a repo move
+	// whose other side matches it.
+	moveScope map[string]bool
+}
+
+// HasChanges reports whether applying the plan would change anything.
+func (r Result) HasChanges() bool {
+	return len(r.Renames) > 0 || len(r.Moves) > 0 || len(r.Changes) > 0 || len(r.LabelUpdates) > 0 || len(r.ExpiryUpdates) > 0
 }
 
 func Plan(ctx context.Context, rootDir string) (Result, error) {
@@ -33,13 +56,20 @@ func Plan(ctx context.Context, rootDir string) (Result, error) {
 		return Result{}, err
 	}
 
+	renames, actual, renameWarnings := diffRenames(desired, actual)
+	warnings = append(warnings, renameWarnings...)
+	moves, actual := diffRepoMoves(desired, actual)
 	changes := coreplanner.Diff(toInventory(desired), toInventory(actual))
 
 	return Result{
-		Desired:  desired,
-		Actual:   actual,
-		Changes:  changes,
-		Warnings: warnings,
+		Desired:       desired,
+		Actual:        actual,
+		Changes:       changes,
+		Renames:       renames,
+		Moves:         moves,
+		LabelUpdates:  diffLabels(desired, actual),
+		ExpiryUpdates: diffExpiry(desired, actual),
+		Warnings:      warnings,
 	}, nil
 }
 
@@ -51,7 +81,7 @@ func toInventory(file manifest.File) coreplanner.Inventory {
 			repos = append(repos, coreplanner.Repo{
 				Alias:   repoEntry.Alias,
 				RepoKey: repoEntry.RepoKey,
-				Branch:  repoEntry.Branch,
+				Branch:  PlanBranch(repoEntry),
 			})
 		}
 		workspaces[id] = coreplanner.Workspace{
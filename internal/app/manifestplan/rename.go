@@ -0,0 +1,54 @@
+package manifestplan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+// WorkspaceRename moves an existing workspace to a new ID (gion.yaml renamed_from).
+type WorkspaceRename struct {
+	From string
+	To   string
+}
+
+// diffRenames finds desired workspaces whose renamed_from names an existing
+// workspace, and returns actual with those workspaces re-keyed to their new ID
+// so the rest of the plan only diffs what changed besides the ID.
+func diffRenames(desired, actual manifest.File) ([]WorkspaceRename, manifest.File, []error) {
+	var renames []WorkspaceRename
+	var warnings []error
+	for id, ws := range desired.Workspaces {
+		from := strings.TrimSpace(ws.RenamedFrom)
+		if from == "" {
+			continue
+		}
+		if _, ok := actual.Workspaces[from]; !ok {
+			continue
+		}
+		if _, ok := actual.Workspaces[id]; ok {
+			warnings = append(warnings, fmt.Errorf("workspace %s: cannot rename from %s (both exist)", id, from))
+			continue
+		}
+		renames = append(renames, WorkspaceRename{From: from, To: id})
+	}
+	if len(renames) == 0 {
+		return nil, actual, warnings
+	}
+	sort.Slice(renames, func(i, j int) bool {
+		return renames[i].To < renames[j].To
+	})
+
+	renamed := actual
+	renamed.Workspaces = make(map[string]manifest.Workspace, len(actual.Workspaces))
+	for id, ws := range actual.Workspaces {
+		renamed.Workspaces[id] = ws
+	}
+	for _, rename := range renames {
+		renamed.Workspaces[rename.To] = renamed.Workspaces[rename.From]
+		delete(renamed.Workspaces, rename.From)
+	}
+	return renames, renamed, warnings
+}
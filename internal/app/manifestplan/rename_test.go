@@ -0,0 +1,38 @@
+package manifestplan
+
+import (
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestDiffRenames(t *testing.T) {
+	repos := []manifest.Repo{{Alias: "api", RepoKey: "github.com/org/api.git", Branch: "WS-1"}}
+	desired := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-2":    {RenamedFrom: "WS-1", Repos: repos},
+		"WS-NEW":  {RenamedFrom: "WS-GONE"},
+		"WS-BOTH": {RenamedFrom: "WS-OLD"},
+	}}
+	actual := manifest.File{Workspaces: map[string]manifest.Workspace{
+		"WS-1":    {Repos: repos, Labels: []string{"backend"}},
+		"WS-OLD":  {},
+		"WS-BOTH": {},
+	}}
+
+	renames, renamed, warnings := diffRenames(desired, actual)
+	if len(renames) != 1 || renames[0] != (WorkspaceRename{From: "WS-1", To: "WS-2"}) {
+		t.Fatalf("unexpected renames: %+v", renames)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected a warning for WS-BOTH, got %v", warnings)
+	}
+	if _, ok := renamed.Workspaces["WS-1"]; ok {
+		t.Fatalf("old id still in renamed actual")
+	}
+	if ws := renamed.Workspaces["WS-2"]; len(ws.Labels) != 1 || len(ws.Repos) != 1 {
+		t.Fatalf("unexpected renamed workspace: %+v", ws)
+	}
+	if _, ok := actual.Workspaces["WS-1"]; !ok {
+		t.Fatalf("actual must not be modified")
+	}
+}
@@ -0,0 +1,309 @@
+package rebase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+type Outcome string
+
+const (
+	OutcomeUpToDate Outcome = "up-to-date"
+	OutcomeRebased  Outcome = "rebased"
+	OutcomeConflict Outcome = "conflict"
+	// OutcomeDirty marks worktrees with uncommitted changes; they are never touched.
+	OutcomeDirty  Outcome = "skipped (dirty)"
+	OutcomePinned Outcome = "skipped (pinned)"
+	// OutcomeStopped marks repos left alone because an earlier repo conflicted.
+	OutcomeStopped Outcome = "skipped (stopped)"
+)
+
+type RepoResult struct {
+	Alias        string
+	Branch       string
+	WorktreePath string
+	// Base is the origin/<branch> ref the repo was rebased onto.
+	Base string
+	// Upstream is the previous base when only the commits after it were replayed
+	// (`git rebase --onto <Base> <Upstream>`); empty for a plain rebase.
+	Upstream string
+	Before   string
+	After    string
+	// Conflicts lists the files that conflicted; the rebase was aborted.
+	Conflicts []string
+	Outcome   Outcome
+}
+
+type Result struct {
+	WorkspaceID string
+	Repos       []RepoResult
+	// BaseBranch is the base_branch recorded in the workspace metadata (empty when
+	// not every repo reached its base, or repos use different bases).
+	BaseBranch string
+}
+
+// Conflicted reports whether the rebase stopped on a conflict.
+func (r Result) Conflicted() bool {
+	for _, repo := range r.Repos {
+		if repo.Outcome == OutcomeConflict {
+			return true
+		}
+	}
+	return false
+}
+
+type Options struct {
+	// Onto overrides the base of every repo (origin/<branch>).
+	Onto string
+	// NoFetch skips fetching the base branches from origin.
+	NoFetch bool
+}
+
+type target struct {
+	repo     workspace.Repo
+	branch   string
+	base     string
+	upstream string
+}
+
+// Rebase rebases the branch of each repo of a workspace onto its base: the
+// Onto option, the repo's base_ref, the workspace's recorded base_branch, or the
+// default branch, in that order. When the branch was created from a different
+// base (base_ref before Onto, or the recorded base_branch), only the commits
+// after it are replayed (`git rebase --onto`). Worktrees with uncommitted
+// changes and pinned repos are skipped. The first conflict aborts that repo's
+// rebase and leaves the remaining repos untouched. When every branch reaches the
+// same base, it is recorded as the workspace's base_branch.
+func Rebase(ctx context.Context, rootDir, workspaceID string, entries []manifest.Repo, opts Options) (Result, error) {
+	result := Result{WorkspaceID: workspaceID}
+	onto := strings.TrimSpace(opts.Onto)
+	if onto != "" {
+		if err := validateBase(ctx, onto); err != nil {
+			return result, err
+		}
+	}
+
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return result, err
+	}
+	status, err := workspace.Status(ctx, rootDir, workspaceID)
+	if err != nil {
+		return result, err
+	}
+	statusByAlias := make(map[string]workspace.RepoStatus, len(status.Repos))
+	for _, repoStatus := range status.Repos {
+		statusByAlias[repoStatus.Alias] = repoStatus
+	}
+	repos, _, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return result, err
+	}
+	reposByAlias := make(map[string]workspace.Repo, len(repos))
+	for _, repo := range repos {
+		reposByAlias[repo.Alias] = repo
+	}
+
+	// Check every repo before changing anything.
+	var targets []target
+	skipped := map[string]RepoResult{}
+	for _, entry := range entries {
+		alias := strings.TrimSpace(entry.Alias)
+		repo, ok := reposByAlias[alias]
+		if !ok {
+			return result, fmt.Errorf("%s: repo not found in workspace (run: gion apply)", alias)
+		}
+		if strings.TrimSpace(entry.Ref) != "" {
+			skipped[alias] = RepoResult{Alias: alias, WorktreePath: repo.WorktreePath, Outcome: OutcomePinned}
+			continue
+		}
+		branch := strings.TrimSpace(entry.Branch)
+		repoStatus, ok := statusByAlias[alias]
+		if !ok {
+			return result, fmt.Errorf("%s: status unavailable", alias)
+		}
+		if repoStatus.Error != nil {
+			return result, fmt.Errorf("%s: check status: %w", alias, repoStatus.Error)
+		}
+		if repoStatus.Dirty {
+			skipped[alias] = RepoResult{Alias: alias, Branch: branch, WorktreePath: repo.WorktreePath, Outcome: OutcomeDirty}
+			continue
+		}
+		if repoStatus.Detached || repoStatus.Branch != branch {
+			return result, fmt.Errorf("%s: worktree is not on branch %s", alias, branch)
+		}
+		if strings.TrimSpace(repo.StorePath) == "" {
+			return result, fmt.Errorf("%s: missing store path", alias)
+		}
+
+		previous := strings.TrimSpace(meta.BaseBranch)
+		base := onto
+		if base == "" {
+			base = strings.TrimSpace(entry.BaseRef)
+		} else if baseRef := strings.TrimSpace(entry.BaseRef); baseRef != "" {
+			previous = baseRef
+		}
+		if base == "" {
+			base = previous
+		}
+		if base == "" {
+			if base, err = workspace.ResolveBaseRef(ctx, repo.StorePath); err != nil {
+				return result, fmt.Errorf("%s: %w", alias, err)
+			}
+		}
+		if err := validateBase(ctx, base); err != nil {
+			return result, fmt.Errorf("%s: %w", alias, err)
+		}
+		t := target{repo: repo, branch: branch, base: base}
+		if previous != "" && previous != base {
+			if _, err := gitcmd.RevParse(ctx, repo.StorePath, "--verify", previous+"^{commit}"); err == nil {
+				t.upstream = previous
+			}
+		}
+		targets = append(targets, t)
+	}
+
+	if !opts.NoFetch {
+		fetched := map[string]bool{}
+		for _, t := range targets {
+			key := t.repo.StorePath + "\x00" + t.base
+			if fetched[key] {
+				continue
+			}
+			fetched[key] = true
+			branch := strings.TrimPrefix(t.base, "origin/")
+			refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branch, branch)
+			gitcmd.Logf("git fetch origin %s", refspec)
+			if _, err := gitcmd.Run(ctx, []string{"fetch", "origin", refspec}, gitcmd.Options{Dir: t.repo.StorePath}); err != nil {
+				return result, fmt.Errorf("%s: fetch %s: %w", t.repo.Alias, t.base, err)
+			}
+		}
+	}
+
+	stopped := false
+	complete := true
+	targetsByAlias := make(map[string]target, len(targets))
+	for _, t := range targets {
+		targetsByAlias[t.repo.Alias] = t
+	}
+	for _, entry := range entries {
+		alias := strings.TrimSpace(entry.Alias)
+		if repoResult, ok := skipped[alias]; ok {
+			if repoResult.Outcome == OutcomeDirty {
+				complete = false
+			}
+			result.Repos = append(result.Repos, repoResult)
+			continue
+		}
+		t := targetsByAlias[alias]
+		repoResult := RepoResult{
+			Alias:        alias,
+			Branch:       t.branch,
+			WorktreePath: t.repo.WorktreePath,
+			Base:         t.base,
+			Upstream:     t.upstream,
+			Outcome:      OutcomeUpToDate,
+		}
+		if stopped {
+			repoResult.Outcome = OutcomeStopped
+			result.Repos = append(result.Repos, repoResult)
+			complete = false
+			continue
+		}
+		if err := rebaseRepo(ctx, t, &repoResult); err != nil {
+			result.Repos = append(result.Repos, repoResult)
+			return result, fmt.Errorf("%s: %w", alias, err)
+		}
+		if repoResult.Outcome == OutcomeConflict {
+			stopped = true
+			complete = false
+		}
+		result.Repos = append(result.Repos, repoResult)
+	}
+
+	if complete && len(targets) > 0 {
+		base := targets[0].base
+		for _, t := range targets[1:] {
+			if t.base != base {
+				base = ""
+				break
+			}
+		}
+		if base != "" && base != meta.BaseBranch {
+			meta.BaseBranch = base
+			if err := workspace.SaveMetadata(wsDir, meta); err != nil {
+				return result, err
+			}
+		}
+		result.BaseBranch = base
+	}
+	return result, nil
+}
+
+func rebaseRepo(ctx context.Context, t target, repoResult *RepoResult) error {
+	before, err := gitcmd.RevParse(ctx, t.repo.WorktreePath, "HEAD")
+	if err != nil {
+		return err
+	}
+	repoResult.Before = strings.TrimSpace(before)
+	repoResult.After = repoResult.Before
+	if t.upstream == "" {
+		contains, err := gitcmd.IsAncestor(ctx, t.repo.WorktreePath, t.base, "HEAD")
+		if err != nil {
+			return err
+		}
+		if contains {
+			return nil
+		}
+	}
+
+	if t.upstream != "" {
+		gitcmd.Logf("git rebase --onto %s %s", t.base, t.upstream)
+	} else {
+		gitcmd.Logf("git rebase %s", t.base)
+	}
+	if rebaseErr := gitcmd.Rebase(ctx, t.repo.WorktreePath, t.base, t.upstream); rebaseErr != nil {
+		conflicts, err := gitcmd.ConflictedFiles(ctx, t.repo.WorktreePath)
+		if err != nil {
+			conflicts = nil
+		}
+		gitcmd.Logf("git rebase --abort")
+		if err := gitcmd.RebaseAbort(ctx, t.repo.WorktreePath); err != nil {
+			return errors.Join(rebaseErr, err)
+		}
+		if len(conflicts) == 0 {
+			return rebaseErr
+		}
+		repoResult.Conflicts = conflicts
+		repoResult.Outcome = OutcomeConflict
+		return nil
+	}
+
+	after, err := gitcmd.RevParse(ctx, t.repo.WorktreePath, "HEAD")
+	if err != nil {
+		return err
+	}
+	repoResult.After = strings.TrimSpace(after)
+	if repoResult.After != repoResult.Before {
+		repoResult.Outcome = OutcomeRebased
+	}
+	return nil
+}
+
+func validateBase(ctx context.Context, base string) error {
+	branch, ok := strings.CutPrefix(base, "origin/")
+	if !ok || branch == "" {
+		return fmt.Errorf("base must be in the form origin/<branch>: %s", base)
+	}
+	if err := workspace.ValidateBranchName(ctx, branch); err != nil {
+		return fmt.Errorf("invalid base %s: %w", base, err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package rebase_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/app/rebase"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+var entries = []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1"}}
+
+func TestRebase_OntoNewBaseRecordsBaseBranch(t *testing.T) {
+	ctx, rootDir, seedDir := setupWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	commitFile(t, worktreePath, "WORK.md", "work\n", "work")
+
+	runGit(t, seedDir, "checkout", "-b", "release", "main")
+	commitFile(t, seedDir, "RELEASE.md", "release\n", "release")
+	runGit(t, seedDir, "push", "origin", "release")
+	want := runGit(t, seedDir, "rev-parse", "HEAD")
+
+	result, err := rebase.Rebase(ctx, rootDir, "WS-1", entries, rebase.Options{Onto: "origin/release"})
+	if err != nil {
+		t.Fatalf("rebase: %v", err)
+	}
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != rebase.OutcomeRebased {
+		t.Fatalf("expected rebased, got %+v", result.Repos)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD^"); got != want {
+		t.Fatalf("HEAD^ = %s, want %s", got, want)
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-1"))
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta.BaseBranch != "origin/release" {
+		t.Fatalf("base_branch = %q, want origin/release", meta.BaseBranch)
+	}
+
+	result, err = rebase.Rebase(ctx, rootDir, "WS-1", entries, rebase.Options{})
+	if err != nil {
+		t.Fatalf("rebase again: %v", err)
+	}
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != rebase.OutcomeUpToDate {
+		t.Fatalf("expected up-to-date, got %+v", result.Repos)
+	}
+}
+
+func TestRebase_SkipsDirtyWorktree(t *testing.T) {
+	ctx, rootDir, seedDir := setupWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	before := runGit(t, worktreePath, "rev-parse", "HEAD")
+
+	commitFile(t, seedDir, "MAIN.md", "main v2\n", "main v2")
+	runGit(t, seedDir, "push", "origin", "main")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+
+	result, err := rebase.Rebase(ctx, rootDir, "WS-1", entries, rebase.Options{})
+	if err != nil {
+		t.Fatalf("rebase: %v", err)
+	}
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != rebase.OutcomeDirty {
+		t.Fatalf("expected skipped (dirty), got %+v", result.Repos)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != before {
+		t.Fatalf("HEAD moved: %s -> %s", before, got)
+	}
+	if result.BaseBranch != "" {
+		t.Fatalf("expected no base_branch for an incomplete rebase, got %q", result.BaseBranch)
+	}
+}
+
+func TestRebase_AbortsOnConflict(t *testing.T) {
+	ctx, rootDir, seedDir := setupWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	commitFile(t, worktreePath, "README.md", "ours\n", "ours")
+	before := runGit(t, worktreePath, "rev-parse", "HEAD")
+
+	commitFile(t, seedDir, "README.md", "theirs\n", "theirs")
+	runGit(t, seedDir, "push", "origin", "main")
+
+	result, err := rebase.Rebase(ctx, rootDir, "WS-1", entries, rebase.Options{})
+	if err != nil {
+		t.Fatalf("rebase: %v", err)
+	}
+	if !result.Conflicted() {
+		t.Fatalf("expected conflict, got %+v", result.Repos)
+	}
+	if got := result.Repos[0].Conflicts; len(got) != 1 || got[0] != "README.md" {
+		t.Fatalf("conflicts = %v, want [README.md]", got)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != before {
+		t.Fatalf("HEAD moved: %s -> %s", before, got)
+	}
+	if got := runGit(t, worktreePath, "status", "--porcelain"); got != "" {
+		t.Fatalf("expected clean worktree after abort, got:\n%s", got)
+	}
+}
+
+func setupWorkspace(t *testing.T) (context.Context, string, string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, seedDir := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	return ctx, rootDir, seedDir
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) (string, string) {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	commitFile(t, seedDir, "README.md", "hello\n", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return "https://example.com/org/repo.git", seedDir
+}
+
+func commitFile(t *testing.T, dir, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", message)
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
@@ -0,0 +1,233 @@
+package repoexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+// Target is one worktree a command runs in.
+type Target struct {
+	WorkspaceID string
+	Alias       string
+	RepoKey     string
+	// Branch is the checked-out branch; empty for a detached HEAD.
+	Branch string
+	Path   string
+}
+
+// Name is the "<workspace>/<alias>" prefix used in output.
+func (t Target) Name() string {
+	return t.WorkspaceID + "/" + t.Alias
+}
+
+type Status string
+
+const (
+	StatusOK     Status = "ok"
+	StatusFailed Status = "failed"
+	// StatusCanceled marks commands killed by --fail-fast while running.
+	StatusCanceled Status = "canceled"
+	// StatusSkipped marks commands never started because of --fail-fast.
+	StatusSkipped Status = "skipped"
+)
+
+type Result struct {
+	Target   Target
+	Status   Status
+	ExitCode int
+	// Err is set when the command could not be started or did not exit normally.
+	Err error
+}
+
+type Options struct {
+	// Parallel is the number of commands run at once (at least 1).
+	Parallel int
+	// FailFast stops starting commands, and kills running ones, after the first failure.
+	FailFast bool
+	// Output receives every line of stdout and stderr, prefixed with the target name.
+	Output io.Writer
+	// Prefix renders the padded target name at the start of each line.
+	Prefix func(name string) string
+}
+
+// Targets lists the worktrees of the given workspaces, in order. Workspaces that
+// do not exist on the filesystem are reported as warnings.
+func Targets(ctx context.Context, rootDir string, workspaceIDs []string) ([]Target, []error, error) {
+	var targets []Target
+	var warnings []error
+	for _, id := range workspaceIDs {
+		wsDir := workspace.WorkspaceDir(rootDir, id)
+		if _, err := os.Stat(wsDir); err != nil {
+			if os.IsNotExist(err) {
+				warnings = append(warnings, fmt.Errorf("%s: workspace does not exist (run: gion apply)", id))
+				continue
+			}
+			return nil, warnings, err
+		}
+		repos, scanWarnings, err := workspace.ScanRepos(ctx, wsDir)
+		if err != nil {
+			return nil, warnings, err
+		}
+		for _, warning := range scanWarnings {
+			warnings = append(warnings, fmt.Errorf("%s: %w", id, warning))
+		}
+		for _, repo := range repos {
+			targets = append(targets, Target{
+				WorkspaceID: id,
+				Alias:       repo.Alias,
+				RepoKey:     repo.RepoKey,
+				Branch:      repo.Branch,
+				Path:        repo.WorktreePath,
+			})
+		}
+	}
+	return targets, warnings, nil
+}
+
+// Run runs argv (without a shell) in each target's worktree and returns one
+// result per target, in target order. The command gets the repo-level GION_*
+// environment variables of hooks (GION_ROOT, GION_WORKSPACE_ID,
+// GION_WORKSPACE_PATH, GION_REPO_ALIAS, GION_REPO_KEY, GION_REPO_PATH and
+// GION_BRANCH); GION_HOOK and the manifest-derived GION_WORKSPACE_MODE and
+// GION_PRESET are not set.
+func Run(ctx context.Context, rootDir string, targets []Target, argv []string, opts Options) []Result {
+	results := make([]Result, len(targets))
+	for i, target := range targets {
+		results[i] = Result{Target: target, Status: StatusSkipped}
+	}
+	if len(argv) == 0 || len(targets) == 0 {
+		return results
+	}
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+	out := opts.Output
+	if out == nil {
+		out = io.Discard
+	}
+	prefix := opts.Prefix
+	if prefix == nil {
+		prefix = func(name string) string { return name }
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var outMu sync.Mutex
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				target := targets[i]
+				w := &prefixWriter{mu: &outMu, out: out, prefix: prefix(target.Name())}
+				results[i] = runOne(runCtx, rootDir, target, argv, w)
+				w.flush()
+				if results[i].Status == StatusFailed && opts.FailFast {
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := range targets {
+		if runCtx.Err() != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-runCtx.Done():
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+func runOne(ctx context.Context, rootDir string, target Target, argv []string, w io.Writer) Result {
+	result := Result{Target: target}
+	if ctx.Err() != nil {
+		result.Status = StatusSkipped
+		return result
+	}
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = target.Path
+	cmd.Env = append(os.Environ(),
+		"GION_ROOT="+rootDir,
+		"GION_WORKSPACE_ID="+target.WorkspaceID,
+		"GION_WORKSPACE_PATH="+workspace.WorkspaceDir(rootDir, target.WorkspaceID),
+		"GION_REPO_ALIAS="+target.Alias,
+		"GION_REPO_KEY="+target.RepoKey,
+		"GION_REPO_PATH="+target.Path,
+		"GION_BRANCH="+target.Branch,
+	)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	// Do not wait forever on output pipes held open by children of a killed command.
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	if err == nil {
+		result.Status = StatusOK
+		return result
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		result.Status = StatusFailed
+		result.ExitCode = exitErr.ExitCode()
+		return result
+	}
+	result.ExitCode = -1
+	result.Err = err
+	result.Status = StatusFailed
+	if ctx.Err() != nil {
+		result.Status = StatusCanceled
+	}
+	return result
+}
+
+// prefixWriter writes complete lines to out, each prefixed with prefix. Writers
+// of concurrent commands share mu so their lines do not interleave.
+type prefixWriter struct {
+	mu      *sync.Mutex
+	out     io.Writer
+	prefix  string
+	pending []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		w.emit(string(w.pending[:idx]))
+		w.pending = w.pending[idx+1:]
+	}
+	return len(p), nil
+}
+
+func (w *prefixWriter) flush() {
+	if len(w.pending) > 0 {
+		w.emit(string(w.pending))
+		w.pending = nil
+	}
+}
+
+func (w *prefixWriter) emit(line string) {
+	line = strings.TrimRight(line, "\r")
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintf(w.out, "%s %s\n", w.prefix, line)
+}
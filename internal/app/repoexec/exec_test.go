@@ -0,0 +1,59 @@
+package repoexec
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRun_PrefixesOutputAndCollectsExitCodes(t *testing.T) {
+	rootDir := t.TempDir()
+	targets := []Target{
+		{WorkspaceID: "WS-1", Alias: "api", Branch: "WS-1", Path: t.TempDir()},
+		{WorkspaceID: "WS-1", Alias: "web", Branch: "WS-1", Path: t.TempDir()},
+	}
+	var out bytes.Buffer
+	results := Run(context.Background(), rootDir, targets, []string{"sh", "-c", `echo "hello $GION_REPO_ALIAS on $GION_BRANCH"; printf partial; test "$GION_REPO_ALIAS" = api`}, Options{Parallel: 2, Output: &out})
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Status != StatusOK || results[0].ExitCode != 0 {
+		t.Fatalf("api: expected ok, got %+v", results[0])
+	}
+	if results[1].Status != StatusFailed || results[1].ExitCode != 1 {
+		t.Fatalf("web: expected exit 1, got %+v", results[1])
+	}
+	for _, want := range []string{"WS-1/api hello api on WS-1\n", "WS-1/web hello web on WS-1\n", "WS-1/api partial\n", "WS-1/web partial\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRun_FailFastSkipsRemaining(t *testing.T) {
+	targets := []Target{
+		{WorkspaceID: "WS-1", Alias: "a", Path: t.TempDir()},
+		{WorkspaceID: "WS-2", Alias: "b", Path: t.TempDir()},
+		{WorkspaceID: "WS-3", Alias: "c", Path: t.TempDir()},
+	}
+	results := Run(context.Background(), t.TempDir(), targets, []string{"sh", "-c", "exit 3"}, Options{Parallel: 1, FailFast: true})
+
+	if results[0].Status != StatusFailed || results[0].ExitCode != 3 {
+		t.Fatalf("expected first to fail with exit 3, got %+v", results[0])
+	}
+	for _, result := range results[1:] {
+		if result.Status != StatusSkipped {
+			t.Fatalf("expected %s to be skipped, got %+v", result.Target.Name(), result)
+		}
+	}
+}
+
+func TestRun_ReportsStartFailure(t *testing.T) {
+	targets := []Target{{WorkspaceID: "WS-1", Alias: "a", Path: t.TempDir()}}
+	results := Run(context.Background(), t.TempDir(), targets, []string{"gion-no-such-command"}, Options{})
+	if results[0].Status != StatusFailed || results[0].Err == nil {
+		t.Fatalf("expected start failure, got %+v", results[0])
+	}
+}
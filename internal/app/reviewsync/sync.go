@@ -0,0 +1,199 @@
+package reviewsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+// ErrDirty is returned when a workspace has uncommitted changes and cannot be moved.
+var ErrDirty = errors.New("workspace has uncommitted changes")
+
+// ErrLocalCommits is returned when a worktree has commits that are not on the PR
+// branch as last fetched (e.g. a reviewer's fixups), which a reset would discard.
+var ErrLocalCommits = errors.New("workspace has local commits not on the PR branch")
+
+type Outcome string
+
+const (
+	OutcomeUpToDate    Outcome = "up-to-date"
+	OutcomeFastForward Outcome = "fast-forward"
+	OutcomeReset       Outcome = "reset"
+)
+
+type RepoResult struct {
+	Alias   string
+	Branch  string
+	Before  string
+	After   string
+	Outcome Outcome
+}
+
+type Result struct {
+	WorkspaceID string
+	Repos       []RepoResult
+}
+
+// Moved reports whether any repo in the workspace changed its HEAD.
+func (r Result) Moved() bool {
+	for _, repo := range r.Repos {
+		if repo.Outcome != OutcomeUpToDate {
+			return true
+		}
+	}
+	return false
+}
+
+type target struct {
+	repo      workspace.Repo
+	branch    string
+	remoteRef string
+	// previous is the remote ref before the fetch; empty when it did not exist.
+	previous string
+	before   string
+	after    string
+	ff       bool
+}
+
+// Sync refetches the PR head branch for each repo of a review workspace and moves
+// the worktree to it: fast-forward when possible, otherwise hard reset.
+// Worktrees with uncommitted changes are never touched, and a reset is refused
+// when HEAD has commits that were not on the PR branch before the fetch.
+func Sync(ctx context.Context, rootDir, workspaceID string, entries []manifest.Repo) (Result, error) {
+	result := Result{WorkspaceID: workspaceID}
+
+	status, err := workspace.Status(ctx, rootDir, workspaceID)
+	if err != nil {
+		return result, err
+	}
+	statusByAlias := make(map[string]workspace.RepoStatus, len(status.Repos))
+	for _, repoStatus := range status.Repos {
+		statusByAlias[repoStatus.Alias] = repoStatus
+	}
+	repos, _, err := workspace.ScanRepos(ctx, workspace.WorkspaceDir(rootDir, workspaceID))
+	if err != nil {
+		return result, err
+	}
+	reposByAlias := make(map[string]workspace.Repo, len(repos))
+	for _, repo := range repos {
+		reposByAlias[repo.Alias] = repo
+	}
+
+	var targets []target
+	for _, entry := range entries {
+		alias := strings.TrimSpace(entry.Alias)
+		branch := strings.TrimSpace(entry.Branch)
+		if branch == "" {
+			return result, fmt.Errorf("%s: branch is required", alias)
+		}
+		repo, ok := reposByAlias[alias]
+		if !ok {
+			return result, fmt.Errorf("%s: repo not found in workspace (run: gion apply)", alias)
+		}
+		repoStatus, ok := statusByAlias[alias]
+		if !ok {
+			return result, fmt.Errorf("%s: status unavailable", alias)
+		}
+		if repoStatus.Error != nil {
+			return result, fmt.Errorf("%s: check status: %w", alias, repoStatus.Error)
+		}
+		if repoStatus.Dirty {
+			return result, fmt.Errorf("%w: %s", ErrDirty, alias)
+		}
+		if repoStatus.Detached || repoStatus.Branch != branch {
+			return result, fmt.Errorf("%s: worktree is not on branch %s", alias, branch)
+		}
+		if strings.TrimSpace(repo.StorePath) == "" {
+			return result, fmt.Errorf("%s: missing store path", alias)
+		}
+		targets = append(targets, target{
+			repo:      repo,
+			branch:    branch,
+			remoteRef: fmt.Sprintf("refs/remotes/origin/%s", branch),
+		})
+	}
+
+	for i := range targets {
+		t := &targets[i]
+		previous, _, err := gitcmd.ShowRef(ctx, t.repo.StorePath, t.remoteRef)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", t.repo.Alias, err)
+		}
+		t.previous = strings.TrimSpace(previous)
+		refspec := fmt.Sprintf("+refs/heads/%s:%s", t.branch, t.remoteRef)
+		gitcmd.Logf("git fetch origin %s", refspec)
+		if _, err := gitcmd.Run(ctx, []string{"fetch", "origin", refspec}, gitcmd.Options{Dir: t.repo.StorePath}); err != nil {
+			return result, fmt.Errorf("%s: fetch %s: %w", t.repo.Alias, t.branch, err)
+		}
+		after, ok, err := gitcmd.ShowRef(ctx, t.repo.StorePath, t.remoteRef)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", t.repo.Alias, err)
+		}
+		if !ok {
+			return result, fmt.Errorf("%s: ref not found: %s", t.repo.Alias, t.remoteRef)
+		}
+		before, err := gitcmd.RevParse(ctx, t.repo.WorktreePath, "HEAD")
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", t.repo.Alias, err)
+		}
+		t.before = before
+		t.after = after
+	}
+
+	// Check every reset before moving anything.
+	for i := range targets {
+		t := &targets[i]
+		if t.before == t.after {
+			continue
+		}
+		ff, err := gitcmd.IsAncestor(ctx, t.repo.WorktreePath, t.before, t.after)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", t.repo.Alias, err)
+		}
+		t.ff = ff
+		if ff {
+			continue
+		}
+		pushed := false
+		if t.previous != "" {
+			if pushed, err = gitcmd.IsAncestor(ctx, t.repo.WorktreePath, t.before, t.previous); err != nil {
+				return result, fmt.Errorf("%s: %w", t.repo.Alias, err)
+			}
+		}
+		if !pushed {
+			return result, fmt.Errorf("%w: %s", ErrLocalCommits, t.repo.Alias)
+		}
+	}
+
+	for _, t := range targets {
+		repoResult := RepoResult{
+			Alias:   t.repo.Alias,
+			Branch:  t.branch,
+			Before:  t.before,
+			After:   t.after,
+			Outcome: OutcomeUpToDate,
+		}
+		if t.before != t.after {
+			if t.ff {
+				gitcmd.Logf("git merge --ff-only %s", t.remoteRef)
+				if err := gitcmd.MergeFastForwardOnly(ctx, t.repo.WorktreePath, t.remoteRef); err != nil {
+					return result, fmt.Errorf("%s: %w", t.repo.Alias, err)
+				}
+				repoResult.Outcome = OutcomeFastForward
+			} else {
+				gitcmd.Logf("git reset --hard %s", t.remoteRef)
+				if err := gitcmd.ResetHard(ctx, t.repo.WorktreePath, t.remoteRef); err != nil {
+					return result, fmt.Errorf("%s: %w", t.repo.Alias, err)
+				}
+				repoResult.Outcome = OutcomeReset
+			}
+		}
+		result.Repos = append(result.Repos, repoResult)
+	}
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package reviewsync_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/app/reviewsync"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestSync_FastForwardsAndResetsAfterForcePush(t *testing.T) {
+	ctx, rootDir, seedDir := setupReviewWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	entries := []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "feature"}}
+
+	result, err := reviewsync.Sync(ctx, rootDir, "WS-1", entries)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if result.Moved() {
+		t.Fatalf("expected up-to-date, got %+v", result.Repos)
+	}
+
+	if err := os.WriteFile(filepath.Join(seedDir, "FEATURE.md"), []byte("v2\n"), 0o644); err != nil {
+		t.Fatalf("write feature file: %v", err)
+	}
+	runGit(t, seedDir, "commit", "-am", "feature v2")
+	runGit(t, seedDir, "push", "origin", "feature")
+	want := runGit(t, seedDir, "rev-parse", "HEAD")
+
+	result, err = reviewsync.Sync(ctx, rootDir, "WS-1", entries)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != reviewsync.OutcomeFastForward {
+		t.Fatalf("expected fast-forward, got %+v", result.Repos)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("HEAD = %s, want %s", got, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(seedDir, "FEATURE.md"), []byte("v3\n"), 0o644); err != nil {
+		t.Fatalf("write feature file: %v", err)
+	}
+	runGit(t, seedDir, "commit", "-a", "--amend", "-m", "feature v3")
+	runGit(t, seedDir, "push", "--force", "origin", "feature")
+	want = runGit(t, seedDir, "rev-parse", "HEAD")
+
+	result, err = reviewsync.Sync(ctx, rootDir, "WS-1", entries)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != reviewsync.OutcomeReset {
+		t.Fatalf("expected reset, got %+v", result.Repos)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("HEAD = %s, want %s", got, want)
+	}
+}
+
+func TestSync_RefusesDirtyWorktree(t *testing.T) {
+	ctx, rootDir, seedDir := setupReviewWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	entries := []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "feature"}}
+	before := runGit(t, worktreePath, "rev-parse", "HEAD")
+
+	runGit(t, seedDir, "commit", "--amend", "-m", "feature rewritten")
+	runGit(t, seedDir, "push", "--force", "origin", "feature")
+	if err := os.WriteFile(filepath.Join(worktreePath, "DIRTY.txt"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+
+	_, err := reviewsync.Sync(ctx, rootDir, "WS-1", entries)
+	if !errors.Is(err, reviewsync.ErrDirty) {
+		t.Fatalf("expected ErrDirty, got: %v", err)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != before {
+		t.Fatalf("HEAD moved: %s -> %s", before, got)
+	}
+}
+
+func TestSync_RefusesResetOverLocalCommits(t *testing.T) {
+	ctx, rootDir, seedDir := setupReviewWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	entries := []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "feature"}}
+
+	if err := os.WriteFile(filepath.Join(worktreePath, "FIXUP.md"), []byte("fixup\n"), 0o644); err != nil {
+		t.Fatalf("write fixup file: %v", err)
+	}
+	runGit(t, worktreePath, "add", "FIXUP.md")
+	runGit(t, worktreePath, "commit", "-m", "reviewer fixup")
+	before := runGit(t, worktreePath, "rev-parse", "HEAD")
+
+	runGit(t, seedDir, "commit", "--amend", "-m", "feature rewritten")
+	runGit(t, seedDir, "push", "--force", "origin", "feature")
+
+	_, err := reviewsync.Sync(ctx, rootDir, "WS-1", entries)
+	if !errors.Is(err, reviewsync.ErrLocalCommits) {
+		t.Fatalf("expected ErrLocalCommits, got: %v", err)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != before {
+		t.Fatalf("HEAD moved: %s -> %s", before, got)
+	}
+}
+
+func setupReviewWorkspace(t *testing.T) (context.Context, string, string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, seedDir := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeReview}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithTrackingBranch(ctx, rootDir, "WS-1", repoSpec, "", "feature", "refs/remotes/origin/feature", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	return ctx, rootDir, seedDir
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) (string, string) {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, seedDir, "checkout", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(seedDir, "FEATURE.md"), []byte("v1\n"), 0o644); err != nil {
+		t.Fatalf("write feature file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "feature v1")
+	runGit(t, seedDir, "push", "origin", "feature")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return "https://example.com/org/repo.git", seedDir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
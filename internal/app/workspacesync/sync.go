@@ -0,0 +1,276 @@
+package workspacesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+type Strategy string
+
+const (
+	// StrategyFastForward only fast-forwards branches to their upstream.
+	StrategyFastForward Strategy = "ff-only"
+	StrategyMerge       Strategy = "merge"
+	StrategyRebase      Strategy = "rebase"
+)
+
+// ParseStrategy parses a --strategy value; empty means merge.
+func ParseStrategy(value string) (Strategy, error) {
+	switch Strategy(strings.TrimSpace(value)) {
+	case "", StrategyMerge:
+		return StrategyMerge, nil
+	case StrategyRebase:
+		return StrategyRebase, nil
+	case StrategyFastForward:
+		return StrategyFastForward, nil
+	}
+	return "", fmt.Errorf("unknown sync strategy: %s (use ff-only, merge or rebase)", value)
+}
+
+type Outcome string
+
+const (
+	OutcomeUpToDate    Outcome = "up to date"
+	OutcomeFastForward Outcome = "fast-forwarded"
+	OutcomeMerged      Outcome = "merged"
+	OutcomeRebased     Outcome = "rebased"
+	OutcomeConflict    Outcome = "conflict"
+	// OutcomeDirty marks worktrees with uncommitted changes; they are never touched.
+	OutcomeDirty  Outcome = "skipped (dirty)"
+	OutcomePinned Outcome = "skipped (pinned)"
+	// OutcomeDetached marks worktrees that are not on their branch.
+	OutcomeDetached Outcome = "skipped (detached)"
+	// OutcomeDiverged marks branches whose upstream (other than the base) has
+	// commits the branch lacks while the branch has commits it lacks.
+	OutcomeDiverged Outcome = "skipped (diverged)"
+	// OutcomeFailed marks repos that could not be synced; RepoResult.Err says why.
+	OutcomeFailed Outcome = "failed"
+)
+
+type RepoResult struct {
+	Alias        string
+	Branch       string
+	WorktreePath string
+	Upstream     string
+	// Base is the origin/<branch> ref merged or rebased into the branch.
+	Base   string
+	Before string
+	After  string
+	// Conflicts lists the files that conflicted; the merge or rebase was aborted.
+	Conflicts []string
+	Outcome   Outcome
+	// Err is set when Outcome is OutcomeFailed.
+	Err error
+}
+
+type Result struct {
+	WorkspaceID string
+	Repos       []RepoResult
+}
+
+// Failed returns the repos that could not be synced.
+func (r Result) Failed() []RepoResult {
+	var failed []RepoResult
+	for _, repo := range r.Repos {
+		if repo.Outcome == OutcomeFailed {
+			failed = append(failed, repo)
+		}
+	}
+	return failed
+}
+
+// Conflicted reports whether any repo stopped on a conflict.
+func (r Result) Conflicted() bool {
+	for _, repo := range r.Repos {
+		if repo.Outcome == OutcomeConflict {
+			return true
+		}
+	}
+	return false
+}
+
+type Options struct {
+	Strategy Strategy
+}
+
+// Sync brings each clean repo of a workspace up to date, assuming the stores were
+// fetched beforehand. A branch behind its upstream is fast-forwarded first; then
+// unless the strategy is ff-only, its base (the repo's base_ref, the workspace's
+// base_branch, or the default branch) is merged or rebased in. Conflicts are
+// aborted and reported, leaving the worktree as it was, and do not stop the
+// other repos; neither do other per-repo errors, which are recorded as
+// OutcomeFailed. Dirty worktrees, pinned repos and detached worktrees are skipped.
+func Sync(ctx context.Context, rootDir, workspaceID string, entries []manifest.Repo, opts Options) (Result, error) {
+	result := Result{WorkspaceID: workspaceID}
+	strategy, err := ParseStrategy(string(opts.Strategy))
+	if err != nil {
+		return result, err
+	}
+
+	wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
+	meta, err := workspace.LoadMetadata(wsDir)
+	if err != nil {
+		return result, err
+	}
+	status, err := workspace.Status(ctx, rootDir, workspaceID)
+	if err != nil {
+		return result, err
+	}
+	statusByAlias := make(map[string]workspace.RepoStatus, len(status.Repos))
+	for _, repoStatus := range status.Repos {
+		statusByAlias[repoStatus.Alias] = repoStatus
+	}
+	repos, _, err := workspace.ScanRepos(ctx, wsDir)
+	if err != nil {
+		return result, err
+	}
+	reposByAlias := make(map[string]workspace.Repo, len(repos))
+	for _, repo := range repos {
+		reposByAlias[repo.Alias] = repo
+	}
+
+	for _, entry := range entries {
+		alias := strings.TrimSpace(entry.Alias)
+		repoResult := RepoResult{
+			Alias:   alias,
+			Branch:  strings.TrimSpace(entry.Branch),
+			Outcome: OutcomeUpToDate,
+		}
+		if err := syncEntry(ctx, entry, meta, reposByAlias, statusByAlias, strategy, &repoResult); err != nil {
+			repoResult.Outcome = OutcomeFailed
+			repoResult.Err = err
+		}
+		result.Repos = append(result.Repos, repoResult)
+	}
+	return result, nil
+}
+
+// syncEntry syncs one repo of the workspace into repoResult.
+func syncEntry(ctx context.Context, entry manifest.Repo, meta workspace.Metadata, reposByAlias map[string]workspace.Repo, statusByAlias map[string]workspace.RepoStatus, strategy Strategy, repoResult *RepoResult) error {
+	alias := repoResult.Alias
+	repo, ok := reposByAlias[alias]
+	if !ok {
+		return fmt.Errorf("repo not found in workspace (run: gion apply)")
+	}
+	repoResult.WorktreePath = repo.WorktreePath
+	if strings.TrimSpace(entry.Ref) != "" {
+		repoResult.Outcome = OutcomePinned
+		return nil
+	}
+	repoStatus, ok := statusByAlias[alias]
+	if !ok {
+		return fmt.Errorf("status unavailable")
+	}
+	if repoStatus.Error != nil {
+		return fmt.Errorf("check status: %w", repoStatus.Error)
+	}
+	repoResult.Upstream = repoStatus.Upstream
+	switch {
+	case repoStatus.Dirty:
+		repoResult.Outcome = OutcomeDirty
+		return nil
+	case repoStatus.Detached || repoStatus.Branch != repoResult.Branch:
+		repoResult.Outcome = OutcomeDetached
+		return nil
+	}
+	if strings.TrimSpace(repo.StorePath) == "" {
+		return fmt.Errorf("missing store path")
+	}
+
+	base := strings.TrimSpace(entry.BaseRef)
+	if base == "" {
+		base = strings.TrimSpace(meta.BaseBranch)
+	}
+	if base == "" {
+		var err error
+		if base, err = workspace.ResolveBaseRef(ctx, repo.StorePath); err != nil {
+			return err
+		}
+	}
+	repoResult.Base = base
+	return syncRepo(ctx, repoStatus, strategy, repoResult)
+}
+
+func syncRepo(ctx context.Context, repoStatus workspace.RepoStatus, strategy Strategy, repoResult *RepoResult) error {
+	dir := repoResult.WorktreePath
+	before, err := gitcmd.RevParse(ctx, dir, "HEAD")
+	if err != nil {
+		return err
+	}
+	repoResult.Before = strings.TrimSpace(before)
+	repoResult.After = repoResult.Before
+
+	if upstream := repoStatus.Upstream; upstream != "" && repoStatus.BehindCount > 0 {
+		if repoStatus.AheadCount == 0 {
+			gitcmd.Logf("git merge --ff-only %s", upstream)
+			if err := gitcmd.MergeFastForwardOnly(ctx, dir, upstream); err != nil {
+				return err
+			}
+			repoResult.Outcome = OutcomeFastForward
+		} else if upstream != repoResult.Base {
+			// Pulling in the base would bury the divergence from the pushed branch.
+			repoResult.Outcome = OutcomeDiverged
+			return nil
+		}
+	}
+	if strategy == StrategyFastForward {
+		return setAfter(ctx, repoResult)
+	}
+
+	if _, err := gitcmd.RevParse(ctx, dir, "--verify", repoResult.Base+"^{commit}"); err != nil {
+		return fmt.Errorf("base not found: %s", repoResult.Base)
+	}
+	contains, err := gitcmd.IsAncestor(ctx, dir, repoResult.Base, "HEAD")
+	if err != nil {
+		return err
+	}
+	if contains {
+		return setAfter(ctx, repoResult)
+	}
+
+	var syncErr error
+	abort := gitcmd.MergeAbort
+	outcome := OutcomeMerged
+	if strategy == StrategyRebase {
+		gitcmd.Logf("git rebase %s", repoResult.Base)
+		syncErr = gitcmd.Rebase(ctx, dir, repoResult.Base, "")
+		abort = gitcmd.RebaseAbort
+		outcome = OutcomeRebased
+	} else {
+		gitcmd.Logf("git merge --no-edit %s", repoResult.Base)
+		syncErr = gitcmd.Merge(ctx, dir, repoResult.Base)
+	}
+	if syncErr != nil {
+		conflicts, err := gitcmd.ConflictedFiles(ctx, dir)
+		if err != nil {
+			conflicts = nil
+		}
+		gitcmd.Logf("git %s --abort", strategy)
+		if err := abort(ctx, dir); err != nil {
+			return errors.Join(syncErr, err)
+		}
+		if len(conflicts) == 0 {
+			return syncErr
+		}
+		repoResult.Conflicts = conflicts
+		repoResult.Outcome = OutcomeConflict
+		return setAfter(ctx, repoResult)
+	}
+	repoResult.Outcome = outcome
+	return setAfter(ctx, repoResult)
+}
+
+func setAfter(ctx context.Context, repoResult *RepoResult) error {
+	after, err := gitcmd.RevParse(ctx, repoResult.WorktreePath, "HEAD")
+	if err != nil {
+		return err
+	}
+	repoResult.After = strings.TrimSpace(after)
+	return nil
+}
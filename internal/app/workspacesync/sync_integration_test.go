@@ -0,0 +1,236 @@
+package workspacesync_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/create"
+	"github.com/tasuku43/gion/internal/app/workspacesync"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+const repoSpec = "https://example.com/org/repo.git"
+
+var entries = []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1"}}
+
+func TestSync_FastForwardsCleanBranch(t *testing.T) {
+	ctx, rootDir, seedDir := setupWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+
+	commitFile(t, seedDir, "MAIN.md", "main v2\n", "main v2")
+	runGit(t, seedDir, "push", "origin", "main")
+	want := runGit(t, seedDir, "rev-parse", "HEAD")
+
+	result := syncWorkspace(t, ctx, rootDir, workspacesync.StrategyMerge)
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != workspacesync.OutcomeFastForward {
+		t.Fatalf("expected fast-forwarded, got %+v", result.Repos)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != want {
+		t.Fatalf("HEAD = %s, want %s", got, want)
+	}
+
+	result = syncWorkspace(t, ctx, rootDir, workspacesync.StrategyMerge)
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != workspacesync.OutcomeUpToDate {
+		t.Fatalf("expected up to date, got %+v", result.Repos)
+	}
+}
+
+func TestSync_MergesOrRebasesBase(t *testing.T) {
+	cases := []struct {
+		strategy workspacesync.Strategy
+		outcome  workspacesync.Outcome
+		parents  int
+	}{
+		{strategy: workspacesync.StrategyMerge, outcome: workspacesync.OutcomeMerged, parents: 2},
+		{strategy: workspacesync.StrategyRebase, outcome: workspacesync.OutcomeRebased, parents: 1},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.strategy), func(t *testing.T) {
+			ctx, rootDir, seedDir := setupWorkspace(t)
+			worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+			commitFile(t, worktreePath, "WORK.md", "work\n", "work")
+
+			commitFile(t, seedDir, "MAIN.md", "main v2\n", "main v2")
+			runGit(t, seedDir, "push", "origin", "main")
+			base := runGit(t, seedDir, "rev-parse", "HEAD")
+
+			result := syncWorkspace(t, ctx, rootDir, tc.strategy)
+			if len(result.Repos) != 1 || result.Repos[0].Outcome != tc.outcome {
+				t.Fatalf("expected %s, got %+v", tc.outcome, result.Repos)
+			}
+			runGit(t, worktreePath, "merge-base", "--is-ancestor", base, "HEAD")
+			parents := strings.Fields(runGit(t, worktreePath, "rev-list", "--parents", "-n", "1", "HEAD"))
+			if len(parents)-1 != tc.parents {
+				t.Fatalf("HEAD has %d parent(s), want %d", len(parents)-1, tc.parents)
+			}
+		})
+	}
+}
+
+func TestSync_SkipsDirtyAndAbortsConflict(t *testing.T) {
+	ctx, rootDir, seedDir := setupWorkspace(t)
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	commitFile(t, worktreePath, "README.md", "ours\n", "ours")
+	before := runGit(t, worktreePath, "rev-parse", "HEAD")
+
+	commitFile(t, seedDir, "README.md", "theirs\n", "theirs")
+	runGit(t, seedDir, "push", "origin", "main")
+
+	if err := os.WriteFile(filepath.Join(worktreePath, "DIRTY.txt"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+	result := syncWorkspace(t, ctx, rootDir, workspacesync.StrategyMerge)
+	if len(result.Repos) != 1 || result.Repos[0].Outcome != workspacesync.OutcomeDirty {
+		t.Fatalf("expected skipped (dirty), got %+v", result.Repos)
+	}
+	if err := os.Remove(filepath.Join(worktreePath, "DIRTY.txt")); err != nil {
+		t.Fatalf("remove dirty file: %v", err)
+	}
+
+	result = syncWorkspace(t, ctx, rootDir, workspacesync.StrategyMerge)
+	if !result.Conflicted() {
+		t.Fatalf("expected conflict, got %+v", result.Repos)
+	}
+	if got := result.Repos[0].Conflicts; len(got) != 1 || got[0] != "README.md" {
+		t.Fatalf("conflicts = %v, want [README.md]", got)
+	}
+	if got := runGit(t, worktreePath, "rev-parse", "HEAD"); got != before {
+		t.Fatalf("HEAD moved: %s -> %s", before, got)
+	}
+	if got := runGit(t, worktreePath, "status", "--porcelain"); got != "" {
+		t.Fatalf("expected clean worktree after abort, got:\n%s", got)
+	}
+}
+
+func TestSync_RecordsRepoFailuresAndContinues(t *testing.T) {
+	ctx, rootDir, seedDir := setupWorkspace(t)
+	commitFile(t, seedDir, "MAIN.md", "main v2\n", "main v2")
+	runGit(t, seedDir, "push", "origin", "main")
+	if err := repo.Prefetch(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("prefetch: %v", err)
+	}
+
+	withMissing := []manifest.Repo{{Alias: "gone", RepoKey: "example.com/org/gone", Branch: "WS-1"}, entries[0]}
+	result, err := workspacesync.Sync(ctx, rootDir, "WS-1", withMissing, workspacesync.Options{Strategy: workspacesync.StrategyMerge})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(result.Repos) != 2 || result.Repos[0].Outcome != workspacesync.OutcomeFailed || result.Repos[0].Err == nil {
+		t.Fatalf("expected the missing repo to fail, got %+v", result.Repos)
+	}
+	if result.Repos[1].Outcome != workspacesync.OutcomeFastForward {
+		t.Fatalf("expected the remaining repo to be synced, got %+v", result.Repos[1])
+	}
+
+	missingBase := []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1", BaseRef: "origin/missing"}}
+	result, err = workspacesync.Sync(ctx, rootDir, "WS-1", missingBase, workspacesync.Options{Strategy: workspacesync.StrategyMerge})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if failed := result.Failed(); len(failed) != 1 || !strings.Contains(failed[0].Err.Error(), "base not found") {
+		t.Fatalf("expected missing base to be recorded as failed, got %+v", result.Repos)
+	}
+}
+
+func syncWorkspace(t *testing.T, ctx context.Context, rootDir string, strategy workspacesync.Strategy) workspacesync.Result {
+	t.Helper()
+	if err := repo.Prefetch(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("prefetch: %v", err)
+	}
+	result, err := workspacesync.Sync(ctx, rootDir, "WS-1", entries, workspacesync.Options{Strategy: strategy})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	return result
+}
+
+func setupWorkspace(t *testing.T) (context.Context, string, string) {
+	t.Helper()
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+	t.Setenv("GION_FETCH_GRACE_SECONDS", "0")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	seedDir := setupLocalRemoteRepo(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	return ctx, rootDir, seedDir
+}
+
+func setupLocalRemoteRepo(t *testing.T, tmp string) string {
+	t.Helper()
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "example.com", "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	commitFile(t, seedDir, "README.md", "hello\n", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/example.com/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	return seedDir
+}
+
+func commitFile(t *testing.T, dir, name, content, message string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", message)
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("git %s failed: %v\nstderr:\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String())
+}
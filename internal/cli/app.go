@@ -96,6 +96,16 @@ func Run() error {
 		return runImport(ctx, rootDir, args[1:], noPrompt)
 	case "apply":
 		return runApply(ctx, rootDir, args[1:], noPrompt)
+	case "review":
+		return runReview(ctx, rootDir, args[1:])
+	case "workspace", "ws":
+		return runWorkspace(ctx, rootDir, args[1:], noPrompt)
+	case "sync":
+		return runSync(ctx, rootDir, args[1:])
+	case "exec":
+		return runExec(ctx, rootDir, args[1:])
+	case "status":
+		return runStatus(ctx, rootDir, args[1:])
 	case "completion":
 		return runCompletion(args[1:])
 	default:
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/tasuku43/gion/internal/app/manifestplan"
 	"github.com/tasuku43/gion/internal/domain/manifest"
 	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
 	"github.com/tasuku43/gion/internal/infra/output"
 	"github.com/tasuku43/gion/internal/infra/prefetcher"
 	"github.com/tasuku43/gion/internal/ui"
@@ -24,8 +26,25 @@ func runApply(ctx context.Context, rootDir string, args []string, noPrompt bool)
 		printApplyHelp(os.Stdout)
 		return nil
 	}
-	if len(args) != 0 {
-		return fmt.Errorf("usage: gion apply")
+	applyFlags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	var labelFlags stringSliceFlag
+	applyFlags.Var(&labelFlags, "label", "only apply workspaces with this label (repeatable)")
+	applyFlags.SetOutput(os.Stdout)
+	applyFlags.Usage = func() {
+		printApplyHelp(os.Stdout)
+	}
+	if err := applyFlags.Parse(normalizeArgsFlagsFirst(args, map[string]struct{}{"--label": {}, "-label": {}})); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if applyFlags.NArg() != 0 {
+		return fmt.Errorf("usage: gion apply [--label <label>]...")
+	}
+	labelFilter, err := workspace.ParseLabelFilter(labelFlags)
+	if err != nil {
+		return err
 	}
 	plan, err := manifestplan.Plan(ctx, rootDir)
 	if err != nil {
@@ -39,6 +58,7 @@ func runApply(ctx context.Context, rootDir string, args []string, noPrompt bool)
 		}
 		return err
 	}
+	plan = manifestplan.FilterByLabels(plan, labelFilter)
 	_, err = runApplyInternalWithPlan(ctx, rootDir, nil, noPrompt, plan)
 	return err
 }
@@ -74,7 +94,7 @@ func runApplyInternalWithPlan(ctx context.Context, rootDir string, renderer *ui.
 	}
 
 	renderer.Section("Plan")
-	if len(plan.Changes) == 0 {
+	if !plan.HasChanges() {
 		renderer.Bullet("no changes")
 		return applyInternalResult{HadChanges: false, Confirmed: false, Applied: false}, nil
 	}
@@ -128,17 +148,31 @@ func runApplyInternalWithPlan(ctx context.Context, rootDir string, renderer *ui.
 		PrefetchTimeout:  defaultPrefetchTimeout,
 		PrefetchOK:       prefetchOK,
 		Step:             output.Step,
+		Warn:             renderer.BulletWarn,
 	}); err != nil {
 		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: false}, err
 	}
-	if err := rebuildManifest(ctx, rootDir); err != nil {
+	if err := rebuildManifestAfterApply(ctx, rootDir, plan); err != nil {
 		return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: false}, err
 	}
 
 	renderer.Blank()
 	renderer.Section("Result")
 	adds, updates, removes := coreapplyplan.CountWorkspaceChanges(plan.Changes)
-	renderer.BulletSuccess(fmt.Sprintf("applied: add=%d update=%d remove=%d", adds, updates, removes))
+	line := fmt.Sprintf("applied: add=%d update=%d remove=%d", adds, updates, removes)
+	if len(plan.Renames) > 0 {
+		line += fmt.Sprintf(" rename=%d", len(plan.Renames))
+	}
+	if len(plan.Moves) > 0 {
+		line += fmt.Sprintf(" move=%d", len(plan.Moves))
+	}
+	if len(plan.LabelUpdates) > 0 {
+		line += fmt.Sprintf(" labels=%d", len(plan.LabelUpdates))
+	}
+	if len(plan.ExpiryUpdates) > 0 {
+		line += fmt.Sprintf(" expiry=%d", len(plan.ExpiryUpdates))
+	}
+	renderer.BulletSuccess(line)
 	renderer.Bullet(fmt.Sprintf("%s rewritten", manifest.FileName))
 	return applyInternalResult{HadChanges: true, Confirmed: confirmed, Applied: true}, nil
 }
@@ -164,7 +198,7 @@ func toPlannerInventory(file manifest.File) coreplanner.Inventory {
 			repos = append(repos, coreplanner.Repo{
 				Alias:   repoEntry.Alias,
 				RepoKey: repoEntry.RepoKey,
-				Branch:  repoEntry.Branch,
+				Branch:  manifestplan.PlanBranch(repoEntry),
 			})
 		}
 		workspaces[id] = coreplanner.Workspace{
@@ -5,14 +5,19 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/tasuku43/gion/internal/app/archive"
 	"github.com/tasuku43/gion/internal/app/create"
 	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/app/rm"
 	"github.com/tasuku43/gion/internal/domain/manifest"
 	"github.com/tasuku43/gion/internal/domain/repo"
 	"github.com/tasuku43/gion/internal/domain/workspace"
 	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
 	"github.com/tasuku43/gion/internal/ui"
 )
 
@@ -107,3 +112,553 @@ func TestApply_BranchRenameInPlace_SucceedsWithDirtyWorktree(t *testing.T) {
 		t.Fatalf("rewritten branch: got %q, want %q", ws.Repos[0].Branch, "WS-2")
 	}
 }
+
+func TestApply_WorkspaceRename_MovesDirtyWorktreeAndRenamesBranch(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo, Description: "login fix"}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "feature/WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	runGit(t, workspace.WorktreePath(rootDir, "WS-1", "repo"), "remote", "set-url", "origin", repoSpec)
+	if err := os.WriteFile(filepath.Join(workspace.WorktreePath(rootDir, "WS-1", "repo"), "DIRTY.txt"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+
+	desired := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS-1": {
+				Description: "login fix",
+				Mode:        workspace.MetadataModeRepo,
+				Repos:       []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "feature/WS-1"}},
+			},
+		},
+	}
+	if err := desired.Rename("WS-1", "WS-2"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if renamed := renameWorkspaceBranches(&desired, "WS-2", "WS-1"); len(renamed) != 1 {
+		t.Fatalf("renamed branches: %v", renamed)
+	}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(plan.Renames) != 1 || plan.Renames[0] != (manifestplan.WorkspaceRename{From: "WS-1", To: "WS-2"}) {
+		t.Fatalf("unexpected renames: %+v", plan.Renames)
+	}
+	if planHasDestructiveChanges(plan) {
+		t.Fatalf("expected non-destructive plan, got changes: %+v", plan.Changes)
+	}
+
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	got, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+	if err != nil {
+		t.Fatalf("apply: %v\n%s", err, buf.String())
+	}
+	if !got.Applied {
+		t.Fatalf("expected applied, got %+v\n%s", got, buf.String())
+	}
+
+	if _, err := os.Stat(workspace.WorkspaceDir(rootDir, "WS-1")); !os.IsNotExist(err) {
+		t.Fatalf("old workspace dir still exists: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-2", "repo")
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "DIRTY.txt")); err != nil || string(data) != "dirty\n" {
+		t.Fatalf("dirty file not preserved: %q, %v", data, err)
+	}
+	branch, err := gitcmd.RevParse(ctx, worktreePath, "--abbrev-ref", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+	if branch != "feature/WS-2" {
+		t.Fatalf("branch: got %q, want %q", branch, "feature/WS-2")
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-2"))
+	if err != nil || meta.Description != "login fix" {
+		t.Fatalf("metadata not moved: %+v, %v", meta, err)
+	}
+
+	rewritten, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	ws, ok := rewritten.Workspaces["WS-2"]
+	if !ok || ws.RenamedFrom != "" || len(rewritten.Workspaces) != 1 {
+		t.Fatalf("unexpected rewritten manifest: %+v", rewritten.Workspaces)
+	}
+}
+
+func TestApply_WorkspaceRename_AppliesLabelAndExpiryChanges(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo, Labels: []string{"backend"}}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+
+	desired := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS-1": {
+				Mode:   workspace.MetadataModeRepo,
+				Labels: []string{"backend"},
+				Repos:  []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1"}},
+			},
+		},
+	}
+	if err := desired.Rename("WS-1", "WS-2"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	ws := desired.Workspaces["WS-2"]
+	ws.Labels = []string{"frontend"}
+	ws.ExpiresAt = "2030-01-02T00:00:00Z"
+	desired.Workspaces["WS-2"] = ws
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	if _, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan); err != nil {
+		t.Fatalf("apply: %v\n%s", err, buf.String())
+	}
+
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-2"))
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if len(meta.Labels) != 1 || meta.Labels[0] != "frontend" || meta.ExpiresAt != "2030-01-02T00:00:00Z" {
+		t.Fatalf("labels/expiry not applied: %+v", meta)
+	}
+	plan, err = manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan after apply: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Fatalf("expected no changes after apply, got %+v", plan)
+	}
+}
+
+func TestApply_WorkspaceCopy_BranchesFromSourceHeadWithChanges(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "feature/WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	sourcePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	runGit(t, sourcePath, "remote", "set-url", "origin", repoSpec)
+	if err := os.WriteFile(filepath.Join(sourcePath, "feature.txt"), []byte("v1\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, sourcePath, "add", "feature.txt")
+	runGit(t, sourcePath, "commit", "-m", "local work")
+	if err := os.WriteFile(filepath.Join(sourcePath, "feature.txt"), []byte("v2\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sourcePath, "notes.txt"), []byte("untracked\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	source := manifest.Workspace{
+		Mode:  workspace.MetadataModeRepo,
+		Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "feature/WS-1", BaseRef: "origin/main"}},
+	}
+	copied := copyWorkspaceEntry(source, "WS-1", "WS-2", "")
+	copied.CopyChanges = true
+	if copied.Repos[0].Branch != "feature/WS-2" || copied.Repos[0].BaseRef != "origin/main" {
+		t.Fatalf("unexpected copied repo: %+v", copied.Repos[0])
+	}
+	desired := manifest.File{
+		Version:    1,
+		Workspaces: map[string]manifest.Workspace{"WS-1": source, "WS-2": copied},
+	}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Kind != manifestplan.WorkspaceAdd || plan.Changes[0].WorkspaceID != "WS-2" {
+		t.Fatalf("unexpected changes: %+v", plan.Changes)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	got, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+	if err != nil {
+		t.Fatalf("apply: %v\n%s", err, buf.String())
+	}
+	if !got.Applied {
+		t.Fatalf("expected applied, got %+v\n%s", got, buf.String())
+	}
+
+	copyPath := workspace.WorktreePath(rootDir, "WS-2", "repo")
+	if runGit(t, copyPath, "rev-parse", "HEAD") != runGit(t, sourcePath, "rev-parse", "HEAD") {
+		t.Fatalf("copy is not at the source HEAD")
+	}
+	if branch := runGit(t, copyPath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "feature/WS-2" {
+		t.Fatalf("branch: got %q", branch)
+	}
+	for name, want := range map[string]string{"feature.txt": "v2\n", "notes.txt": "untracked\n"} {
+		if data, err := os.ReadFile(filepath.Join(copyPath, name)); err != nil || string(data) != want {
+			t.Fatalf("%s: got %q, %v", name, data, err)
+		}
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-2"))
+	if err != nil || meta.BaseBranch != "origin/main" {
+		t.Fatalf("base branch not recorded: %+v, %v", meta, err)
+	}
+
+	rewritten, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	if ws := rewritten.Workspaces["WS-2"]; ws.CopiedFrom != "" || ws.CopyChanges {
+		t.Fatalf("copy fields not dropped: %+v", ws)
+	}
+}
+
+func TestApply_WorkspaceCopy_FailsWhenBranchExistsOnOrigin(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, remotePath := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "feature/WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	sourcePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	if err := os.WriteFile(filepath.Join(sourcePath, "notes.txt"), []byte("untracked\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	// Someone else already pushed the branch the copy would create.
+	runGit(t, "", "--git-dir", remotePath, "branch", "feature/WS-2", "main")
+
+	source := manifest.Workspace{
+		Mode:  workspace.MetadataModeRepo,
+		Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "feature/WS-1"}},
+	}
+	copied := copyWorkspaceEntry(source, "WS-1", "WS-2", "")
+	copied.CopyChanges = true
+	desired := manifest.File{
+		Version:    1,
+		Workspaces: map[string]manifest.Workspace{"WS-1": source, "WS-2": copied},
+	}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	_, err = runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+	if err == nil || !strings.Contains(err.Error(), "branch feature/WS-2 already exists") {
+		t.Fatalf("expected existing branch error, got %v\n%s", err, buf.String())
+	}
+	if _, err := os.Stat(filepath.Join(workspace.WorktreePath(rootDir, "WS-2", "repo"), "notes.txt")); !os.IsNotExist(err) {
+		t.Fatalf("changes were copied onto the existing branch: %v", err)
+	}
+}
+
+func TestApply_WorkspaceRestore_RecoversUnpushedCommitsAndChanges(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	store, err := repo.Get(ctx, rootDir, repoSpec)
+	if err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo, Description: "archived work"}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "feature/WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	if err := workspace.SaveMetadata(workspace.WorkspaceDir(rootDir, "WS-1"), workspace.Metadata{Mode: workspace.MetadataModeRepo, Description: "archived work", BaseBranch: "origin/main"}); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("v1\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	runGit(t, worktreePath, "add", "feature.txt")
+	runGit(t, worktreePath, "commit", "-m", "unpushed work")
+	head := runGit(t, worktreePath, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("v2\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "notes.txt"), []byte("untracked\n"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	entry := manifest.Workspace{
+		Mode:        workspace.MetadataModeRepo,
+		Description: "archived work",
+		Repos:       []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "feature/WS-1", BaseRef: "origin/main"}},
+	}
+	archived, err := archive.Create(ctx, rootDir, "WS-1", entry, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+	if len(archived.Repos) != 1 || archived.Repos[0].Bundle == "" || archived.Repos[0].Patch == "" || archived.Repos[0].Head != head {
+		t.Fatalf("unexpected archive: %+v", archived.Repos)
+	}
+
+	// Lose the workspace and its branch entirely; only the archive remains.
+	if err := rm.Remove(ctx, rootDir, "WS-1", true); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	runGit(t, store.StorePath, "branch", "-D", "feature/WS-1")
+
+	restored := archived.Entry()
+	restored.RestoreFrom = archived.Name()
+	if err := manifest.Save(rootDir, manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{"WS-1": restored}}); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	got, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+	if err != nil {
+		t.Fatalf("apply: %v\n%s", err, buf.String())
+	}
+	if !got.Applied {
+		t.Fatalf("expected applied, got %+v\n%s", got, buf.String())
+	}
+
+	if runGit(t, worktreePath, "rev-parse", "HEAD") != head {
+		t.Fatalf("restored worktree is not at the archived HEAD")
+	}
+	if branch := runGit(t, worktreePath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "feature/WS-1" {
+		t.Fatalf("branch: got %q", branch)
+	}
+	for name, want := range map[string]string{"feature.txt": "v2\n", "notes.txt": "untracked\n"} {
+		if data, err := os.ReadFile(filepath.Join(worktreePath, name)); err != nil || string(data) != want {
+			t.Fatalf("%s: got %q, %v", name, data, err)
+		}
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-1"))
+	if err != nil || meta.BaseBranch != "origin/main" || meta.Description != "archived work" {
+		t.Fatalf("metadata not restored: %+v, %v", meta, err)
+	}
+	rewritten, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	if ws := rewritten.Workspaces["WS-1"]; ws.RestoreFrom != "" {
+		t.Fatalf("restore_from not dropped: %+v", ws)
+	}
+}
+
+func TestApply_RepoMove_MovesDirtyWorktreeToAnotherWorkspaceAndAlias(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	for _, id := range []string{"WS-1", "WS-2"} {
+		if _, err := create.CreateWorkspace(ctx, rootDir, id, workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+			t.Fatalf("create workspace: %v", err)
+		}
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "feature/WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workspace.WorktreePath(rootDir, "WS-1", "repo"), "DIRTY.txt"), []byte("dirty\n"), 0o644); err != nil {
+		t.Fatalf("write dirty file: %v", err)
+	}
+
+	desired := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS-1": {Mode: workspace.MetadataModeRepo},
+			"WS-2": {
+				Mode:  workspace.MetadataModeRepo,
+				Repos: []manifest.Repo{{Alias: "app", RepoKey: "example.com/org/repo", Branch: "feature/WS-1"}},
+			},
+		},
+	}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	want := manifestplan.RepoMove{FromWorkspace: "WS-1", FromAlias: "repo", ToWorkspace: "WS-2", ToAlias: "app", RepoKey: "example.com/org/repo", Branch: "feature/WS-1"}
+	if len(plan.Moves) != 1 || plan.Moves[0] != want {
+		t.Fatalf("unexpected moves: %+v", plan.Moves)
+	}
+	if len(plan.Changes) != 0 {
+		t.Fatalf("expected no other changes, got %+v", plan.Changes)
+	}
+
+	var buf bytes.Buffer
+	renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+	got, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+	if err != nil {
+		t.Fatalf("apply: %v\n%s", err, buf.String())
+	}
+	if !got.Applied {
+		t.Fatalf("expected applied, got %+v\n%s", got, buf.String())
+	}
+
+	if _, err := os.Stat(workspace.WorktreePath(rootDir, "WS-1", "repo")); !os.IsNotExist(err) {
+		t.Fatalf("old worktree still exists: %v", err)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-2", "app")
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "DIRTY.txt")); err != nil || string(data) != "dirty\n" {
+		t.Fatalf("dirty file not preserved: %q, %v", data, err)
+	}
+	if branch := runGit(t, worktreePath, "rev-parse", "--abbrev-ref", "HEAD"); branch != "feature/WS-1" {
+		t.Fatalf("branch: got %q", branch)
+	}
+	rewritten, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	if repos := rewritten.Workspaces["WS-2"].Repos; len(repos) != 1 || repos[0].Alias != "app" {
+		t.Fatalf("unexpected WS-2 repos after apply: %+v", repos)
+	}
+	if repos := rewritten.Workspaces["WS-1"].Repos; len(repos) != 0 {
+		t.Fatalf("unexpected WS-1 repos after apply: %+v", repos)
+	}
+}
+
+func TestWorkspaceArchive_NoPromptRestoresManifestAndRemovesArchive(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := create.CreateWorkspace(ctx, rootDir, "WS-1", workspace.Metadata{Mode: workspace.MetadataModeRepo}); err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "WS-1", "", true); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	if err := manifest.Save(rootDir, manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{
+		"WS-1": {Mode: workspace.MetadataModeRepo, Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Branch: "WS-1"}}},
+	}}); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	before, err := os.ReadFile(manifest.Path(rootDir))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+
+	err = runWorkspaceArchive(ctx, rootDir, []string{"WS-1", "--no-prompt"}, false)
+	if err == nil || !strings.Contains(err.Error(), "destructive changes require confirmation") {
+		t.Fatalf("expected destructive confirmation error, got %v", err)
+	}
+	after, err := os.ReadFile(manifest.Path(rootDir))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("expected %s to be restored, got:\n%s", manifest.FileName, after)
+	}
+	entries, err := os.ReadDir(paths.ArchiveRoot(rootDir))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("read archive root: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no archives left, got %d", len(entries))
+	}
+	if _, err := os.Stat(workspace.WorktreePath(rootDir, "WS-1", "repo")); err != nil {
+		t.Fatalf("expected worktree to be kept: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/preset"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+// branchNamer suggests branch names for `gion manifest add` from the
+// branches.templates of gion.yaml. Without templates it keeps the built-in
+// defaults: the workspace ID, or issue/<number> in issue mode.
+type branchNamer struct {
+	ctx       context.Context
+	templates manifest.BranchTemplates
+	date      string
+	user      string
+	userErr   error
+	userDone  bool
+}
+
+func newBranchNamer(ctx context.Context, file manifest.File) *branchNamer {
+	return &branchNamer{
+		ctx:       ctx,
+		templates: file.Branches.Templates,
+		date:      time.Now().Format("2006-01-02"),
+	}
+}
+
+// vars returns the template values shared by every mode. git's user.name is
+// only read when one of templates refers to {user}.
+func (n *branchNamer) vars(workspaceID string, templates ...string) (workspace.BranchTemplateVars, error) {
+	vars := workspace.BranchTemplateVars{WorkspaceID: workspaceID, Date: n.date}
+	for _, tmpl := range templates {
+		if !workspace.BranchTemplateUses(tmpl, workspace.BranchVarUser) {
+			continue
+		}
+		user, err := n.gitUser()
+		if err != nil {
+			return workspace.BranchTemplateVars{}, fmt.Errorf("branch template %q: %w", tmpl, err)
+		}
+		vars.User = user
+		break
+	}
+	return vars, nil
+}
+
+func (n *branchNamer) gitUser() (string, error) {
+	if !n.userDone {
+		n.userDone = true
+		name, ok, err := gitcmd.ConfigGet(n.ctx, "", "user.name")
+		switch {
+		case err != nil:
+			n.userErr = err
+		case !ok || workspace.BranchSlug(name) == "":
+			n.userErr = fmt.Errorf("{user} needs git config user.name")
+		default:
+			n.user = workspace.BranchSlug(name)
+		}
+	}
+	return n.user, n.userErr
+}
+
+// repoBranch returns the default branch for repo mode.
+func (n *branchNamer) repoBranch(workspaceID string) (string, error) {
+	vars, err := n.vars(workspaceID, n.templates.Repo)
+	if err != nil {
+		return "", err
+	}
+	return workspace.ExpandBranchTemplate(n.templates.Repo, vars)
+}
+
+// issueBranch returns the default branch for an issue workspace.
+func (n *branchNamer) issueBranch(workspaceID string, number int, title string) (string, error) {
+	if strings.TrimSpace(n.templates.Issue) == "" {
+		return fmt.Sprintf("issue/%d", number), nil
+	}
+	vars, err := n.vars(workspaceID, n.templates.Issue)
+	if err != nil {
+		return "", err
+	}
+	vars.Issue = strconv.Itoa(number)
+	vars.Slug = workspace.BranchSlug(title)
+	return workspace.ExpandBranchTemplate(n.templates.Issue, vars)
+}
+
+// presetBranches returns the default branch of every repo of a preset. A repo's
+// own branch template wins over branches.templates.preset.
+func (n *branchNamer) presetBranches(tmpl preset.Preset, presetName, workspaceID string) ([]string, error) {
+	templates := []string{n.templates.Preset}
+	for _, entry := range tmpl.Repos {
+		templates = append(templates, entry.Branch)
+	}
+	vars, err := n.vars(workspaceID, templates...)
+	if err != nil {
+		return nil, err
+	}
+	vars.Preset = presetName
+	return preset.RepoBranches(tmpl, n.templates.Preset, vars)
+}
+
+// checkBranchPattern rejects new workspaces whose branches do not match
+// branches.pattern before gion.yaml is written (`gion manifest validate` would
+// report them afterwards).
+func checkBranchPattern(file manifest.File, workspaceIDs []string) error {
+	for _, id := range workspaceIDs {
+		ws, ok := file.Workspaces[id]
+		if !ok || ws.Mode == workspace.MetadataModeReview {
+			continue
+		}
+		for _, r := range ws.Repos {
+			if strings.TrimSpace(r.Branch) == "" {
+				continue
+			}
+			if err := file.Branches.MatchBranch(r.Branch); err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/preset"
+)
+
+func TestBranchNamer_Defaults(t *testing.T) {
+	namer := newBranchNamer(context.Background(), manifest.File{})
+	if got, err := namer.repoBranch("PROJ-1"); err != nil || got != "PROJ-1" {
+		t.Fatalf("repoBranch = %q, %v", got, err)
+	}
+	if got, err := namer.issueBranch("ORG-API-ISSUE-7", 7, "Fix login"); err != nil || got != "issue/7" {
+		t.Fatalf("issueBranch = %q, %v", got, err)
+	}
+}
+
+func TestBranchNamer_Templates(t *testing.T) {
+	file := manifest.File{Branches: manifest.Branches{Templates: manifest.BranchTemplates{
+		Repo:   "feature/{user}/{id}",
+		Issue:  "feature/{user}/{issue}-{slug}",
+		Preset: "{preset}/{date}-{id}",
+	}}}
+	namer := newBranchNamer(context.Background(), file)
+	namer.date = "2026-01-02"
+	namer.user, namer.userDone = "alice", true
+
+	if got, err := namer.repoBranch("PROJ-1"); err != nil || got != "feature/alice/PROJ-1" {
+		t.Fatalf("repoBranch = %q, %v", got, err)
+	}
+	if got, err := namer.issueBranch("ORG-API-ISSUE-7", 7, "Fix login: 500 on Safari"); err != nil || got != "feature/alice/7-fix-login-500-on-safari" {
+		t.Fatalf("issueBranch = %q, %v", got, err)
+	}
+	if _, err := namer.issueBranch("ORG-API-ISSUE-8", 8, "日本語"); err == nil || !strings.Contains(err.Error(), "{slug}") {
+		t.Fatalf("expected slug error, got %v", err)
+	}
+	tmpl := preset.Preset{Repos: []preset.Repo{
+		{Repo: "git@github.com:org/app.git"},
+		{Repo: "git@github.com:org/infra.git", Branch: "infra/{id}"},
+	}}
+	branches, err := namer.presetBranches(tmpl, "webapp", "PROJ-1")
+	if err != nil {
+		t.Fatalf("presetBranches: %v", err)
+	}
+	if strings.Join(branches, ",") != "webapp/2026-01-02-PROJ-1,infra/PROJ-1" {
+		t.Fatalf("unexpected preset branches: %v", branches)
+	}
+}
+
+func TestCheckBranchPattern(t *testing.T) {
+	file := manifest.File{
+		Branches: manifest.Branches{Pattern: "^feature/"},
+		Workspaces: map[string]manifest.Workspace{
+			"OK":     {Repos: []manifest.Repo{{Alias: "app", Branch: "feature/OK"}}},
+			"BAD":    {Repos: []manifest.Repo{{Alias: "app", Branch: "BAD"}}},
+			"REVIEW": {Mode: "review", Repos: []manifest.Repo{{Alias: "app", Branch: "someone/topic"}}},
+		},
+	}
+	if err := checkBranchPattern(file, []string{"OK", "REVIEW"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checkBranchPattern(file, []string{"BAD"}); err == nil || !strings.Contains(err.Error(), "does not match branches.pattern") {
+		t.Fatalf("expected pattern error, got %v", err)
+	}
+}
@@ -31,15 +31,17 @@ _gion_completion() {
   local cur prev words cword
   _init_completion || return
 
-  local commands="init doctor repo manifest plan import apply version help completion"
-  local manifest_subcmds="ls add rm gc validate preset"
+  local commands="init doctor repo review workspace status sync exec manifest plan import apply version help completion"
+  local manifest_subcmds="ls add rm mv cp gc validate schema migrate preset"
   local manifest_aliases="man m"
-  local preset_subcmds="ls add rm validate"
+  local preset_subcmds="ls add rm validate subscribe sync"
   local preset_aliases="pre p"
   local repo_subcmds="get ls rm"
+  local review_subcmds="sync"
+  local workspace_subcmds="archive restore rebase"
 
   if [[ ${cword} -eq 1 ]]; then
-    COMPREPLY=($(compgen -W "${commands} ${manifest_aliases}" -- "${cur}"))
+    COMPREPLY=($(compgen -W "${commands} ${manifest_aliases} ws" -- "${cur}"))
     return
   fi
 
@@ -64,18 +66,42 @@ _gion_completion() {
               COMPREPLY=($(compgen -W "--no-prompt" -- "${cur}"))
               return
             ;;
+            subscribe)
+              COMPREPLY=($(compgen -W "--ref --path" -- "${cur}"))
+              return
+            ;;
+            sync)
+              COMPREPLY=($(compgen -W "--dry-run" -- "${cur}"))
+              return
+            ;;
           esac
         ;;
         add)
-          COMPREPLY=($(compgen -W "--preset --review --issue --repo --branch --base --no-apply --no-prompt" -- "${cur}"))
+          COMPREPLY=($(compgen -W "--preset --review --review-query --issue --issue-query --repo --branch --base --ttl --host --no-apply --no-prompt" -- "${cur}"))
           return
         ;;
         rm)
           COMPREPLY=($(compgen -W "--no-apply --no-prompt" -- "${cur}"))
           return
         ;;
+        mv)
+          COMPREPLY=($(compgen -W "--rename-branches --no-apply --no-prompt" -- "${cur}"))
+          return
+        ;;
+        cp)
+          COMPREPLY=($(compgen -W "--with-changes --no-apply --no-prompt" -- "${cur}"))
+          return
+        ;;
+        ls)
+          COMPREPLY=($(compgen -W "--label --no-prompt" -- "${cur}"))
+          return
+        ;;
         gc)
-          COMPREPLY=($(compgen -W "--no-apply --no-fetch --no-prompt" -- "${cur}"))
+          COMPREPLY=($(compgen -W "--policy --expired --label --archive-dirty --dry-run --no-apply --no-fetch --no-provider --no-prompt" -- "${cur}"))
+          return
+        ;;
+        migrate)
+          COMPREPLY=($(compgen -W "--dry-run --no-prompt" -- "${cur}"))
           return
         ;;
       esac
@@ -92,6 +118,44 @@ _gion_completion() {
         ;;
       esac
     ;;
+    review)
+      if [[ ${cword} -eq 2 ]]; then
+        COMPREPLY=($(compgen -W "${review_subcmds}" -- "${cur}"))
+        return
+      fi
+    ;;
+    workspace|ws)
+      if [[ ${cword} -eq 2 ]]; then
+        COMPREPLY=($(compgen -W "${workspace_subcmds}" -- "${cur}"))
+        return
+      fi
+      case ${words[2]} in
+        archive|restore)
+          COMPREPLY=($(compgen -W "--no-apply --no-prompt" -- "${cur}"))
+          return
+        ;;
+        rebase)
+          COMPREPLY=($(compgen -W "--onto --no-fetch" -- "${cur}"))
+          return
+        ;;
+      esac
+    ;;
+    sync)
+      COMPREPLY=($(compgen -W "--strategy --no-fetch" -- "${cur}"))
+      return
+    ;;
+    status)
+      COMPREPLY=($(compgen -W "--all --json" -- "${cur}"))
+      return
+    ;;
+    exec)
+      COMPREPLY=($(compgen -W "--workspace --all --label --parallel --fail-fast" -- "${cur}"))
+      return
+    ;;
+    plan|apply)
+      COMPREPLY=($(compgen -W "--label" -- "${cur}"))
+      return
+    ;;
     doctor)
       COMPREPLY=($(compgen -W "--fix --self" -- "${cur}"))
       return
@@ -121,6 +185,11 @@ _gion() {
     'init:initialize root layout'
     'doctor:check workspace/repo health'
     'repo:repo commands'
+    'review:review workspace commands'
+    'workspace:workspace commands (archive/restore/rebase)'
+    'status:show per-repo status of a workspace'
+    'sync:update worktrees from their upstream and base'
+    'exec:run a command in every worktree'
     'manifest:manifest inventory commands'
     'plan:show manifest diff'
     'import:rebuild manifest from filesystem'
@@ -130,6 +199,7 @@ _gion() {
     'completion:generate shell completion'
     'man:alias for manifest'
     'm:alias for manifest'
+    'ws:alias for workspace'
   )
 
   local -a repo_subcmds
@@ -139,13 +209,29 @@ _gion() {
     'rm:remove bare repo stores'
   )
 
+  local -a review_subcmds
+  review_subcmds=(
+    'sync:refetch PR heads for review workspaces'
+  )
+
+  local -a workspace_subcmds
+  workspace_subcmds=(
+    'archive:archive a workspace then remove it'
+    'restore:recreate an archived workspace'
+    'rebase:rebase clean repos onto their base'
+  )
+
   local -a manifest_subcmds
   manifest_subcmds=(
     'ls:list workspace inventory'
     'add:add workspace to manifest'
     'rm:remove workspace entries'
+    'mv:rename a workspace'
+    'cp:copy a workspace onto new branches'
     'gc:garbage collect safe workspaces'
     'validate:validate manifest inventory'
+    'schema:print JSON Schema for gion.yaml'
+    'migrate:upgrade manifest schema version'
     'preset:preset inventory commands'
     'pre:alias for preset'
     'p:alias for preset'
@@ -157,6 +243,8 @@ _gion() {
     'add:add a preset entry'
     'rm:remove preset entries'
     'validate:validate presets'
+    'subscribe:subscribe to a preset catalog repository'
+    'sync:sync preset catalogs'
   )
 
   local -a global_flags
@@ -186,6 +274,12 @@ _gion() {
                 rm)
                   _arguments '--no-prompt[disable interactive prompt]'
                 ;;
+                subscribe)
+                  _arguments '--ref[branch, tag or commit]:ref' '--path[presets file in the repository]:path'
+                ;;
+                sync)
+                  _arguments '--dry-run[show changes only]'
+                ;;
                 *)
                   _describe 'preset subcommand' preset_subcmds
                 ;;
@@ -195,18 +289,34 @@ _gion() {
               _arguments \
                 '--preset[preset name]:name' \
                 '--review[add review workspace from PR]:url' \
+                '--review-query[add review workspaces from a PR search query]:query' \
                 '--issue[add issue workspace from issue]:url' \
+                '--issue-query[add issue workspaces from an issue search query]:query' \
                 '--repo[add workspace from repo]:repo' \
                 '--branch[override branch name]:name' \
                 '--base[override base ref]:ref' \
+                '--ttl[expire the workspace after a duration]:duration' \
+                '--host[GitHub host to search]:host' \
                 '--no-apply[update manifest only]' \
                 '--no-prompt[disable interactive prompt]'
             ;;
             rm)
               _arguments '--no-apply[update manifest only]' '--no-prompt[disable interactive prompt]'
             ;;
+            mv)
+              _arguments '--rename-branches[rename branches derived from the old id]' '--no-apply[update manifest only]' '--no-prompt[disable interactive prompt]'
+            ;;
+            cp)
+              _arguments '--with-changes[bring over uncommitted changes]' '--no-apply[update manifest only]' '--no-prompt[disable interactive prompt]'
+            ;;
+            ls)
+              _arguments '*--label[only list workspaces with this label]:label' '--no-prompt[disable interactive prompt]'
+            ;;
             gc)
-              _arguments '--no-apply[update manifest only]' '--no-fetch[disable git fetch]' '--no-prompt[disable interactive prompt]'
+              _arguments '--policy[gc policy name]:name' '--expired[remove expired workspaces]' '*--label[only consider workspaces with this label]:label' '--archive-dirty[archive dirty candidates before removal]' '--dry-run[list candidates only]' '--no-apply[update manifest only]' '--no-fetch[disable git fetch]' '--no-provider[disable PR/issue state lookups]' '--no-prompt[disable interactive prompt]'
+            ;;
+            migrate)
+              _arguments '--dry-run[show the diff only]' '--no-prompt[disable interactive prompt]'
             ;;
             *)
               _describe 'manifest subcommand' manifest_subcmds
@@ -227,6 +337,40 @@ _gion() {
             ;;
           esac
         ;;
+        review)
+          case ${words[2]} in
+            sync)
+            ;;
+            *)
+              _describe 'review subcommand' review_subcmds
+            ;;
+          esac
+        ;;
+        workspace|ws)
+          case ${words[2]} in
+            archive|restore)
+              _arguments '--no-apply[update manifest only]' '--no-prompt[disable interactive prompt]'
+            ;;
+            rebase)
+              _arguments '--onto[new base ref]:ref' '--no-fetch[do not fetch base branches]'
+            ;;
+            *)
+              _describe 'workspace subcommand' workspace_subcmds
+            ;;
+          esac
+        ;;
+        status)
+          _arguments '--all[summarize every workspace]' '--json[print JSON]'
+        ;;
+        exec)
+          _arguments '*--workspace[workspace to run in]:id' '--all[run in every workspace]' '*--label[only run in workspaces with this label]:label' '--parallel[number of commands to run at once]:n' '--fail-fast[stop after the first failure]'
+        ;;
+        sync)
+          _arguments '--strategy[how to bring in the base]:strategy:(ff-only merge rebase)' '--no-fetch[do not fetch repo stores]'
+        ;;
+        plan|apply)
+          _arguments '*--label[only include workspaces with this label]:label'
+        ;;
         doctor)
           _arguments '--fix[list issues and planned fixes]' '--self[run self-diagnostics]'
         ;;
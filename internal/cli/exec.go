@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/repoexec"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runExec(ctx context.Context, rootDir string, args []string) error {
+	execFlags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	var workspaceFlags stringSliceFlag
+	var labelFlags stringSliceFlag
+	var allFlag bool
+	var parallel int
+	var failFast bool
+	var helpFlag bool
+	execFlags.Var(&workspaceFlags, "workspace", "workspace to run in (repeatable)")
+	execFlags.Var(&labelFlags, "label", "only run in workspaces with this label (repeatable)")
+	execFlags.BoolVar(&allFlag, "all", false, "run in every workspace")
+	execFlags.IntVar(&parallel, "parallel", 1, "number of commands to run at once")
+	execFlags.BoolVar(&failFast, "fail-fast", false, "stop after the first failure")
+	execFlags.BoolVar(&helpFlag, "help", false, "show help")
+	execFlags.BoolVar(&helpFlag, "h", false, "show help")
+	execFlags.SetOutput(os.Stdout)
+	execFlags.Usage = func() {
+		printExecHelp(os.Stdout)
+	}
+	// Flags must come before the command; everything from the first non-flag
+	// argument (or after `--`) is the command and its own flags.
+	if err := execFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printExecHelp(os.Stdout)
+		return nil
+	}
+	argv := execFlags.Args()
+	if len(argv) == 0 {
+		return fmt.Errorf("usage: gion exec [--workspace <id>]... [--all] [--label <label>]... [--parallel <n>] [--fail-fast] -- <command> [args...]")
+	}
+	if parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1: %d", parallel)
+	}
+	if allFlag && len(workspaceFlags) > 0 {
+		return fmt.Errorf("--all cannot be combined with --workspace")
+	}
+	labelFilter, err := workspace.ParseLabelFilter(labelFlags)
+	if err != nil {
+		return err
+	}
+	if !allFlag && len(workspaceFlags) == 0 && len(labelFilter) == 0 {
+		return fmt.Errorf("specify --workspace <id>, --all or --label <label>")
+	}
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	ids, err := execTargets(desired, workspaceFlags, labelFilter)
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+
+	targets, warnings, err := repoexec.Targets(ctx, rootDir, ids)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		renderer.BulletWarn(compactError(warning))
+	}
+	if len(targets) == 0 {
+		renderer.Section("Result")
+		renderer.Bullet("no repos")
+		return nil
+	}
+
+	width := 0
+	for _, target := range targets {
+		width = max(width, len(target.Name()))
+	}
+	results := repoexec.Run(ctx, rootDir, targets, argv, repoexec.Options{
+		Parallel: parallel,
+		FailFast: failFast,
+		Output:   os.Stdout,
+		Prefix: func(name string) string {
+			return renderer.MutedText(fmt.Sprintf("%-*s |", width, name))
+		},
+	})
+
+	renderer.Blank()
+	renderer.Section("Result")
+	renderExecResult(renderer, results, width)
+
+	failed := 0
+	for _, result := range results {
+		if result.Status != repoexec.StatusOK {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("exec failed in %d of %d repo(s)", failed, len(results))
+	}
+	return nil
+}
+
+// execTargets resolves the workspaces to run in: the given IDs (which must exist
+// in the manifest), or every workspace, narrowed down by the label filter.
+func execTargets(desired manifest.File, workspaceIDs, labelFilter []string) ([]string, error) {
+	var ids []string
+	if len(workspaceIDs) == 0 {
+		for id := range desired.Workspaces {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+	} else {
+		seen := make(map[string]bool, len(workspaceIDs))
+		for _, value := range workspaceIDs {
+			id := strings.TrimSpace(value)
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			if _, ok := desired.Workspaces[id]; !ok {
+				return nil, fmt.Errorf("workspace not found in %s: %s", manifest.FileName, id)
+			}
+			ids = append(ids, id)
+		}
+	}
+	filtered := ids[:0]
+	for _, id := range ids {
+		if workspace.MatchLabels(desired.Workspaces[id].Labels, labelFilter) {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered, nil
+}
+
+func renderExecResult(r *ui.Renderer, results []repoexec.Result, width int) {
+	counts := map[repoexec.Status]int{}
+	for _, result := range results {
+		counts[result.Status]++
+		name := fmt.Sprintf("%-*s", width, result.Target.Name())
+		switch result.Status {
+		case repoexec.StatusOK:
+			r.BulletSuccess(fmt.Sprintf("%s  exit 0", name))
+		case repoexec.StatusFailed:
+			line := fmt.Sprintf("%s  exit %d", name, result.ExitCode)
+			if result.Err != nil {
+				line = fmt.Sprintf("%s  %s", name, compactError(result.Err))
+			}
+			r.BulletError(line)
+		default:
+			r.BulletWarn(fmt.Sprintf("%s  %s", name, result.Status))
+		}
+	}
+	var summary []string
+	for _, status := range []repoexec.Status{repoexec.StatusOK, repoexec.StatusFailed, repoexec.StatusCanceled, repoexec.StatusSkipped} {
+		if counts[status] > 0 {
+			summary = append(summary, fmt.Sprintf("%s: %d", status, counts[status]))
+		}
+	}
+	r.Bullet(strings.Join(summary, ", "))
+}
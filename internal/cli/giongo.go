@@ -30,7 +30,9 @@ func RunGiongo() error {
 	var printFlag bool
 	var helpFlag bool
 	var versionFlag bool
+	var labelFlags stringSliceFlag
 	fs.StringVar(&rootFlag, "root", "", "override root")
+	fs.Var(&labelFlags, "label", "only show workspaces with this label (repeatable)")
 	fs.BoolVar(&printFlag, "print", false, "print selected path")
 	fs.BoolVar(&helpFlag, "help", false, "show help")
 	fs.BoolVar(&helpFlag, "h", false, "show help")
@@ -56,6 +58,10 @@ func RunGiongo() error {
 	if len(fs.Args()) > 0 {
 		return fmt.Errorf("unknown argument: %s", fs.Args()[0])
 	}
+	labelFilter, err := workspace.ParseLabelFilter(labelFlags)
+	if err != nil {
+		return err
+	}
 	if !isTerminal(os.Stdin.Fd()) {
 		return fmt.Errorf("interactive selection requires a TTY")
 	}
@@ -70,6 +76,10 @@ func RunGiongo() error {
 	if err != nil {
 		return err
 	}
+	entries = filterGiongoEntries(entries, labelFilter)
+	if len(entries) == 0 && len(labelFilter) > 0 {
+		return fmt.Errorf("no workspaces with label: %s", strings.Join(labelFilter, ", "))
+	}
 	choices, err := buildGiongoWorkspaceChoices(ctx, entries)
 	if err != nil {
 		return err
@@ -188,6 +198,19 @@ func giongoInitScriptFor(rcFile string) string {
 	return strings.Join(lines, "\n")
 }
 
+func filterGiongoEntries(entries []workspace.Entry, labelFilter []string) []workspace.Entry {
+	if len(labelFilter) == 0 {
+		return entries
+	}
+	var filtered []workspace.Entry
+	for _, entry := range entries {
+		if workspace.MatchLabels(entry.Labels, labelFilter) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
 func buildGiongoWorkspaceChoices(ctx context.Context, entries []workspace.Entry) ([]ui.WorkspaceChoice, error) {
 	sort.Slice(entries, func(i, j int) bool {
 		return entries[i].WorkspaceID < entries[j].WorkspaceID
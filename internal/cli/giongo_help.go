@@ -9,12 +9,13 @@ func printGiongoHelp(w io.Writer) {
 	fmt.Fprintln(w, `giongo - interactive workspace/worktree picker
 
 Usage:
-  giongo [--print] [--root <path>]
+  giongo [--print] [--root <path>] [--label <label>]...
   giongo init
 
 Options:
-  --print         print the selected absolute path
-  --root <path>   override root directory
-  -h, --help      show help
-  --version       print version`)
+  --print           print the selected absolute path
+  --root <path>     override root directory
+  --label <label>   only show workspaces with this label (repeatable; all must match)
+  -h, --help        show help
+  --version         print version`)
 }
@@ -4,6 +4,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
 )
 
 func TestRunGiongoRequiresTTY(t *testing.T) {
@@ -45,3 +47,18 @@ func TestRunGiongoPrintUsesStderr(t *testing.T) {
 		t.Fatalf("expected TTY error, got %q", err.Error())
 	}
 }
+
+func TestFilterGiongoEntries(t *testing.T) {
+	entries := []workspace.Entry{
+		{WorkspaceID: "WS-1", Labels: []string{"backend", "team=payments"}},
+		{WorkspaceID: "WS-2", Labels: []string{"frontend"}},
+		{WorkspaceID: "WS-3"},
+	}
+	if got := filterGiongoEntries(entries, nil); len(got) != 3 {
+		t.Fatalf("expected no filtering, got %d entries", len(got))
+	}
+	got := filterGiongoEntries(entries, []string{"team=payments"})
+	if len(got) != 1 || got[0].WorkspaceID != "WS-1" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+}
@@ -30,6 +30,11 @@ func printGlobalHelp(w io.Writer) {
 	fmt.Fprintln(w, helpCommand(theme, useColor, "plan", fmt.Sprintf("show %s diff (no changes)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "import", fmt.Sprintf("rebuild %s from filesystem", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "apply", fmt.Sprintf("apply %s to filesystem", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "review <subcommand>", "review workspace commands (sync)"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "workspace <subcommand>", "workspace commands (archive/restore/rebase) (alias: ws)"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "status [<WORKSPACE_ID> | --all]", "show per-repo branch, upstream, ahead/behind and changed files"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "sync [<WORKSPACE_ID> ...]", "update worktrees from their upstream and base (merge/rebase)"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "exec [target flags] -- <cmd>", "run a command in every worktree of the selected workspaces"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "repo <subcommand>", "repo commands (get/ls/rm)"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "doctor [--fix | --self]", "check workspace/repo health"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "completion [shell]", fmt.Sprintf("generate shell completion (%s)", SupportedShells)))
@@ -50,6 +55,16 @@ func printCommandHelp(cmd string, w io.Writer) bool {
 		printRepoHelp(w)
 	case "manifest", "man", "m":
 		printManifestHelp(w)
+	case "review":
+		printReviewHelp(w)
+	case "workspace", "ws":
+		printWorkspaceHelp(w)
+	case "sync":
+		printSyncHelp(w)
+	case "exec":
+		printExecHelp(w)
+	case "status":
+		printStatusHelp(w)
 	case "doctor":
 		printDoctorHelp(w)
 	case "plan":
@@ -104,14 +119,19 @@ func printManifestHelp(w io.Writer) {
 	fmt.Fprintln(w, helpCommand(theme, useColor, "ls", "list workspace inventory with drift tags"))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "add [mode flags] [args]", fmt.Sprintf("add workspace to %s then apply (default)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "rm [<WORKSPACE_ID> ...]", fmt.Sprintf("remove workspace entries from %s then apply (default)", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "mv <OLD_ID> <NEW_ID>", fmt.Sprintf("rename a workspace in %s then apply (default)", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "cp <SOURCE_ID> <NEW_ID>", fmt.Sprintf("copy a workspace onto new branches in %s then apply (default)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "gc", fmt.Sprintf("conservatively remove safe workspaces from %s then apply (default)", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "validate", fmt.Sprintf("validate %s inventory", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "schema", fmt.Sprintf("print a JSON Schema for %s (for editors)", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "migrate", fmt.Sprintf("upgrade %s to the current schema version", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "preset <subcommand>", "preset inventory commands (aliases: pre, p)"))
 }
 
 func printManifestLsHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
-	fmt.Fprintln(w, "Usage: gion manifest ls [--no-prompt]")
+	fmt.Fprintln(w, "Usage: gion manifest ls [--label <label>]... [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--label <label>", "only list workspaces with this label (repeatable; all must match)"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "accepted for compatibility (no effect)"))
 	fmt.Fprintln(w, "")
 	fmt.Fprintln(w, helpSectionTitle(theme, useColor, "Statuses:"))
@@ -126,13 +146,17 @@ func printManifestLsHelp(w io.Writer) {
 
 func printManifestAddHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
-	fmt.Fprintln(w, "Usage: gion manifest add [--preset <name> | --review [<PR URL>] | --issue <ISSUE_URL> | --repo <repo>] [<WORKSPACE_ID>] [--branch <name>] [--base <ref>] [--no-apply] [--no-prompt]")
+	fmt.Fprintln(w, "Usage: gion manifest add [--preset <name> | --review [<PR URL>] | --review-query <query> | --issue <ISSUE_URL> | --issue-query <query> | --repo <repo>] [<WORKSPACE_ID>] [--branch <name>] [--base <ref>] [--ttl <duration>] [--host <host>] [--no-apply] [--no-prompt]")
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--preset <name>", "preset name"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--review [<PR URL>]", "add review workspace from PR (GitHub only)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--review-query <query>", "add review workspaces for every PR matching a GitHub search query"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--issue <ISSUE_URL>", "add issue workspace from issue (GitHub only)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--issue-query <query>", "add issue workspaces for every issue matching a GitHub search query"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--repo <repo>", "add workspace from a repo"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--branch <name>", "override branch name (repo/issue modes only)"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--base <ref>", "override base ref (issue mode; applies to all repos in no-prompt)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--ttl <duration>", "set expires_at to now + duration (e.g. 72h, 3d, 2w)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--host <host>", "GitHub host to search (query modes only; default: github.com)"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (do not run gion apply)", manifest.FileName)))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
 }
@@ -144,11 +168,37 @@ func printManifestRmHelp(w io.Writer) {
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
 }
 
+func printManifestMvHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest mv <OLD_ID> <NEW_ID> [--rename-branches] [--no-apply] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--rename-branches", "also rename branches containing <OLD_ID> (e.g. feature/<OLD_ID>)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (do not run gion apply)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Apply moves the workspace directory and its worktrees (git worktree move); uncommitted changes are kept.")
+}
+
+func printManifestCpHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest cp <SOURCE_ID> <NEW_ID> [--with-changes] [--no-apply] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--with-changes", "bring over uncommitted and untracked changes of the source workspace"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (do not run gion apply)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Apply branches each repo from the source worktree's current HEAD (branches containing <SOURCE_ID> get <NEW_ID> instead).")
+}
+
 func printManifestGcHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
-	fmt.Fprintln(w, "Usage: gion manifest gc [--no-apply] [--no-fetch] [--no-prompt]")
+	fmt.Fprintln(w, "Usage: gion manifest gc [--policy <name> | --expired] [--label <label>]... [--archive-dirty] [--dry-run] [--no-apply] [--no-fetch] [--no-provider] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--policy <name>", fmt.Sprintf("select candidates with a gc policy from %s (gc.policies)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--expired", "remove clean workspaces past their expires_at (instead of the merge rules)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--label <label>", "only consider workspaces with this label (repeatable; all must match)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--archive-dirty", "evaluate dirty/unpushed workspaces too and archive them before removal (see gion workspace archive)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--dry-run", fmt.Sprintf("list candidates and matched rules without changing %s", manifest.FileName)))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (do not run gion apply)", manifest.FileName)))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-fetch", "disable git fetch for repo stores"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-provider", "do not query PR/issue state for review/issue workspaces"))
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
 }
 
@@ -158,6 +208,25 @@ func printManifestValidateHelp(w io.Writer) {
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "accepted for compatibility (no effect)"))
 }
 
+func printManifestSchemaHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest schema [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "accepted for compatibility (no effect)"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, helpSectionTitle(theme, useColor, "Tips:"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "gion manifest schema > gion.schema.json", "save the schema next to gion.yaml"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "# yaml-language-server: $schema=./gion.schema.json", "first line of gion.yaml for editor completion"))
+}
+
+func printManifestMigrateHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest migrate [--dry-run] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--dry-run", fmt.Sprintf("show the diff without changing %s", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "Upgrades %s to version %d. The original is kept as %s.v<N>.bak.\n", manifest.FileName, manifest.CurrentVersion, manifest.FileName)
+}
+
 func printManifestPresetHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
 	fmt.Fprintln(w, "Usage: gion manifest preset <subcommand>")
@@ -169,6 +238,8 @@ func printManifestPresetHelp(w io.Writer) {
 	fmt.Fprintln(w, helpCommand(theme, useColor, "add [<name>]", fmt.Sprintf("add a preset entry to %s", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "rm [<name> ...]", fmt.Sprintf("remove preset entries from %s", manifest.FileName)))
 	fmt.Fprintln(w, helpCommand(theme, useColor, "validate", fmt.Sprintf("validate presets in %s", manifest.FileName)))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "subscribe <repo>", "subscribe to a preset catalog stored in a git repository"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "sync [<repo> ...]", "fetch preset catalogs and update their read-only presets"))
 }
 
 func printManifestPresetLsHelp(w io.Writer) {
@@ -196,6 +267,102 @@ func printManifestPresetValidateHelp(w io.Writer) {
 	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "accepted for compatibility (no effect)"))
 }
 
+func printManifestPresetSubscribeHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest preset subscribe <repo> [--ref <ref>] [--path <file>]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "<repo>", "repo spec of the catalog repository"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--ref <ref>", "branch, tag or commit to read (default: default branch)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--path <file>", fmt.Sprintf("presets file in the repository (default: %s)", manifest.DefaultCatalogPath)))
+}
+
+func printManifestPresetSyncHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion manifest preset sync [<repo> ...] [--dry-run]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "<repo>", "catalogs to sync (default: all subscribed catalogs)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--dry-run", "show changed presets without updating them"))
+}
+
+func printReviewHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion review <subcommand>")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, helpSectionTitle(theme, useColor, "Subcommands:"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "sync [<WORKSPACE_ID> ...]", "refetch PR heads and move clean review worktrees to them"))
+}
+
+func printReviewSyncHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion review sync [<WORKSPACE_ID> ...]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "WORKSPACE_ID", "review workspaces to sync (default: all review workspaces)"))
+}
+
+func printWorkspaceHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion workspace <subcommand>")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Aliases: gion ws")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, helpSectionTitle(theme, useColor, "Subcommands:"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "archive <WORKSPACE_ID>", "save unpushed commits and local changes under GION_ROOT/archive, then remove the workspace"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "restore <ARCHIVE>", "recreate an archived workspace"))
+	fmt.Fprintln(w, helpCommand(theme, useColor, "rebase <WORKSPACE_ID>", "rebase clean repos onto their base_ref (or --onto) and record it"))
+}
+
+func printWorkspaceArchiveHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion workspace archive <WORKSPACE_ID> [--no-apply] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "WORKSPACE_ID", fmt.Sprintf("workspace to archive (must be in %s)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only (the archive is still written)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
+}
+
+func printWorkspaceRestoreHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion workspace restore <ARCHIVE> [--no-apply] [--no-prompt]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "ARCHIVE", "archive name under GION_ROOT/archive, or a path to it"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-apply", fmt.Sprintf("update %s only", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-prompt", "disable interactive prompt"))
+}
+
+func printWorkspaceRebaseHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion workspace rebase <WORKSPACE_ID> [--onto <ref>] [--no-fetch]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "WORKSPACE_ID", fmt.Sprintf("workspace to rebase (must be in %s)", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--onto <ref>", fmt.Sprintf("new base for every repo (origin/<branch>); also written to base_ref in %s", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-fetch", "do not fetch base branches from origin"))
+}
+
+func printSyncHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion sync [<WORKSPACE_ID> ...] [--strategy <ff-only|merge|rebase>] [--no-fetch]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "WORKSPACE_ID", "workspaces to sync (default: all non-review workspaces)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--strategy <name>", "fast-forward to the upstream only (ff-only), or also merge (default) or rebase onto the base ref"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--no-fetch", "do not fetch repo stores"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Dirty worktrees are skipped; conflicts are aborted and reported.")
+}
+
+func printStatusHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion status [<WORKSPACE_ID> | --all] [--json]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "WORKSPACE_ID", "workspace to show (default: the workspace containing the current directory)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--all", "one summary row per workspace"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--json", "print JSON instead of text"))
+}
+
+func printExecHelp(w io.Writer) {
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion exec [--workspace <id>]... [--all] [--label <label>]... [--parallel <n>] [--fail-fast] -- <command> [args...]")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--workspace <id>", "workspace to run in (repeatable)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--all", fmt.Sprintf("run in every workspace in %s", manifest.FileName)))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--label <label>", "only run in workspaces with this label (repeatable; all must match)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--parallel <n>", "number of commands to run at once (default: 1)"))
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--fail-fast", "stop starting commands, and kill running ones, after the first failure"))
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "The command runs without a shell (use: -- sh -c '...'). Output lines are prefixed with <workspace>/<alias>.")
+	fmt.Fprintln(w, "GION_WORKSPACE_ID, GION_REPO_ALIAS, GION_REPO_KEY and GION_REPO_PATH are set for the command.")
+}
+
 func printDoctorHelp(w io.Writer) {
 	theme, useColor := helpTheme(w)
 	fmt.Fprintln(w, "Usage: gion doctor [--fix | --self]")
@@ -228,11 +395,15 @@ func printImportHelp(w io.Writer) {
 }
 
 func printPlanHelp(w io.Writer) {
-	fmt.Fprintln(w, "Usage: gion plan")
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion plan [--label <label>]...")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--label <label>", "only plan workspaces with this label (repeatable; all must match)"))
 }
 
 func printApplyHelp(w io.Writer) {
-	fmt.Fprintln(w, "Usage: gion apply")
+	theme, useColor := helpTheme(w)
+	fmt.Fprintln(w, "Usage: gion apply [--label <label>]...")
+	fmt.Fprintln(w, helpFlag(theme, useColor, "--label <label>", "only apply workspaces with this label (repeatable; all must match)"))
 }
 
 func helpTheme(w io.Writer) (ui.Theme, bool) {
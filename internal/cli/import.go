@@ -95,7 +95,7 @@ func loadManifestOrEmpty(rootDir string) (manifest.File, error) {
 		return file, nil
 	}
 	if errors.Is(err, os.ErrNotExist) {
-		return manifest.File{Version: 1, Workspaces: map[string]manifest.Workspace{}}, nil
+		return manifest.File{Version: manifest.CurrentVersion, Workspaces: map[string]manifest.Workspace{}}, nil
 	}
 	return manifest.File{}, err
 }
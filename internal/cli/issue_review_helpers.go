@@ -27,6 +27,8 @@ type issueRepoChoice struct {
 type issueSummary struct {
 	Number int
 	Title  string
+	// State is the provider state ("open" or "closed"); empty when unknown.
+	State string
 }
 
 func buildIssueRepoChoices(rootDir string) ([]issueRepoChoice, error) {
@@ -103,6 +105,9 @@ type prSummary struct {
 	BaseRef  string
 	HeadRepo string
 	BaseRepo string
+	// State is the provider state ("open" or "closed"); empty when unknown.
+	State  string
+	Merged bool
 }
 
 func buildReviewRepoChoices(rootDir string) ([]reviewRepoChoice, error) {
@@ -172,6 +177,7 @@ func isGitHubHost(host string) bool {
 type githubIssueItem struct {
 	Number      int             `json:"number"`
 	Title       string          `json:"title"`
+	State       string          `json:"state"`
 	PullRequest json.RawMessage `json:"pull_request"`
 }
 
@@ -242,6 +248,7 @@ func fetchGitHubIssue(ctx context.Context, host, owner, repoName string, number
 	return issueSummary{
 		Number: item.Number,
 		Title:  strings.TrimSpace(item.Title),
+		State:  strings.ToLower(strings.TrimSpace(item.State)),
 	}, nil
 }
 
@@ -261,15 +268,180 @@ func parseGitHubIssues(data []byte) ([]issueSummary, error) {
 		issues = append(issues, issueSummary{
 			Number: item.Number,
 			Title:  strings.TrimSpace(item.Title),
+			State:  strings.ToLower(strings.TrimSpace(item.State)),
 		})
 	}
 	return issues, nil
 }
 
+type searchResult struct {
+	Owner  string
+	Repo   string
+	Number int
+	Title  string
+}
+
+type githubSearchItem struct {
+	Number        int             `json:"number"`
+	Title         string          `json:"title"`
+	RepositoryURL string          `json:"repository_url"`
+	PullRequest   json.RawMessage `json:"pull_request"`
+}
+
+type githubSearchResponse struct {
+	TotalCount int                `json:"total_count"`
+	Items      []githubSearchItem `json:"items"`
+}
+
+// searchResults is what a search returned, filtered to the requested kind.
+type searchResults struct {
+	Items []searchResult
+	// Fetched is the number of items the search API returned, before filtering.
+	Fetched int
+	// Total is the number of matches the search API reported. It exceeds Fetched
+	// when the API caps the results it returns (1000 per query on GitHub).
+	Total int
+}
+
+// Truncated reports whether the search matched more items than were fetched.
+func (r searchResults) Truncated() bool {
+	return r.Total > r.Fetched
+}
+
+const (
+	githubSearchPageSize = 100
+	// githubSearchMaxResults is the number of results GitHub search returns at most.
+	githubSearchMaxResults = 1000
+)
+
+func searchGitHub(ctx context.Context, host, query string, pullRequests bool) (searchResults, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return searchResults{}, fmt.Errorf("search query is required")
+	}
+	return collectGitHubSearchPages(func(page int) ([]byte, error) {
+		args := []string{"api", "-X", "GET", "search/issues", "-f", "q=" + query, "-f", "sort=updated", "-f", "order=desc", "-f", fmt.Sprintf("per_page=%d", githubSearchPageSize), "-f", fmt.Sprintf("page=%d", page)}
+		if host != "" && !strings.EqualFold(host, "github.com") {
+			args = append([]string{"api", "--hostname", host}, args[1:]...)
+		}
+		stdout, stderr, err := runExternalCommand(ctx, "gh", args)
+		if err != nil {
+			msg := strings.TrimSpace(stderr)
+			if msg != "" {
+				return nil, fmt.Errorf("gh api failed: %s", msg)
+			}
+			return nil, fmt.Errorf("gh api failed: %w", err)
+		}
+		return []byte(stdout), nil
+	}, pullRequests)
+}
+
+// collectGitHubSearchPages fetches search result pages (1-based) until a short
+// page, every reported match, or the API's result cap is reached.
+func collectGitHubSearchPages(fetchPage func(page int) ([]byte, error), pullRequests bool) (searchResults, error) {
+	var out searchResults
+	for page := 1; ; page++ {
+		data, err := fetchPage(page)
+		if err != nil {
+			return searchResults{}, err
+		}
+		var raw githubSearchResponse
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return searchResults{}, fmt.Errorf("parse gh api response: %w", err)
+		}
+		out.Items = append(out.Items, filterGitHubSearchItems(raw.Items, pullRequests)...)
+		out.Fetched += len(raw.Items)
+		out.Total = raw.TotalCount
+		if len(raw.Items) < githubSearchPageSize || out.Fetched >= out.Total || out.Fetched >= githubSearchMaxResults {
+			break
+		}
+	}
+	if out.Total < out.Fetched {
+		out.Total = out.Fetched
+	}
+	return out, nil
+}
+
+func filterGitHubSearchItems(items []githubSearchItem, pullRequests bool) []searchResult {
+	var results []searchResult
+	for _, item := range items {
+		if item.Number == 0 {
+			continue
+		}
+		if isPR := len(item.PullRequest) != 0; isPR != pullRequests {
+			continue
+		}
+		owner, repoName, ok := repoFromGitHubAPIURL(item.RepositoryURL)
+		if !ok {
+			continue
+		}
+		results = append(results, searchResult{
+			Owner:  owner,
+			Repo:   repoName,
+			Number: item.Number,
+			Title:  strings.TrimSpace(item.Title),
+		})
+	}
+	return results
+}
+
+// repoFromGitHubAPIURL extracts owner/repo from API URLs such as
+// https://api.github.com/repos/owner/repo or https://ghe.example.com/api/v3/repos/owner/repo.
+func repoFromGitHubAPIURL(raw string) (string, string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+2 < len(parts); i++ {
+		if parts[i] != "repos" {
+			continue
+		}
+		owner := strings.TrimSpace(parts[i+1])
+		repoName := strings.TrimSpace(parts[i+2])
+		if owner == "" || repoName == "" {
+			return "", "", false
+		}
+		return owner, repoName, true
+	}
+	return "", "", false
+}
+
+// normalizeSearchQuery adds the type qualifier (is:pr / is:issue) and an open-state
+// qualifier when the query does not specify them, so that a bare query like
+// "review-requested:@me" only matches open pull requests.
+func normalizeSearchQuery(query string, pullRequests bool) string {
+	fields := strings.Fields(query)
+	hasType := false
+	hasState := false
+	for _, field := range fields {
+		switch strings.ToLower(field) {
+		case "is:pr", "is:issue", "type:pr", "type:issue":
+			hasType = true
+		case "is:open", "is:closed", "is:merged", "is:unmerged", "state:open", "state:closed":
+			hasState = true
+		}
+	}
+	var prefix []string
+	if !hasType {
+		if pullRequests {
+			prefix = append(prefix, "is:pr")
+		} else {
+			prefix = append(prefix, "is:issue")
+		}
+	}
+	if !hasState {
+		prefix = append(prefix, "is:open")
+	}
+	return strings.Join(append(prefix, fields...), " ")
+}
+
 type githubPRItem struct {
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	Head   struct {
+	Number   int    `json:"number"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	MergedAt string `json:"merged_at"`
+	Head     struct {
 		Ref  string `json:"ref"`
 		Repo struct {
 			FullName string `json:"full_name"`
@@ -350,6 +522,8 @@ func normalizeGitHubPR(item githubPRItem) prSummary {
 		BaseRef:  strings.TrimSpace(item.Base.Ref),
 		HeadRepo: strings.TrimSpace(item.Head.Repo.FullName),
 		BaseRepo: strings.TrimSpace(item.Base.Repo.FullName),
+		State:    strings.ToLower(strings.TrimSpace(item.State)),
+		Merged:   strings.TrimSpace(item.MergedAt) != "",
 	}
 }
 
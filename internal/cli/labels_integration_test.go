@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func TestApply_LabelsRoundTripAndFilter(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+
+	desired := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS-A": {Mode: workspace.MetadataModeRepo, Labels: []string{"backend"}},
+			"WS-B": {Mode: workspace.MetadataModeRepo, Labels: []string{"frontend"}},
+		},
+	}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+
+	applyPlan := func(filter []string) manifestplan.Result {
+		t.Helper()
+		plan, err := manifestplan.Plan(ctx, rootDir)
+		if err != nil {
+			t.Fatalf("plan: %v", err)
+		}
+		plan = manifestplan.FilterByLabels(plan, filter)
+		var buf bytes.Buffer
+		renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+		got, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+		if err != nil {
+			t.Fatalf("apply: %v\n%s", err, buf.String())
+		}
+		if !got.Applied {
+			t.Fatalf("expected applied, got %+v\n%s", got, buf.String())
+		}
+		return plan
+	}
+
+	// Only the backend workspace is created; the frontend one stays pending in gion.yaml.
+	plan := applyPlan([]string{"backend"})
+	if len(plan.Changes) != 1 || plan.Changes[0].WorkspaceID != "WS-A" {
+		t.Fatalf("unexpected filtered changes: %+v", plan.Changes)
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-A"))
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if strings.Join(meta.Labels, ",") != "backend" {
+		t.Fatalf("expected labels in metadata, got %+v", meta)
+	}
+	rebuilt, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	if _, ok := rebuilt.Workspaces["WS-B"]; !ok {
+		t.Fatalf("expected out-of-scope workspace to stay in %s", manifest.FileName)
+	}
+	if strings.Join(rebuilt.Workspaces["WS-A"].Labels, ",") != "backend" {
+		t.Fatalf("expected labels to round-trip, got %+v", rebuilt.Workspaces["WS-A"])
+	}
+
+	// A label-only edit is planned and applied to metadata.
+	ws := rebuilt.Workspaces["WS-A"]
+	ws.Labels = []string{"backend", "keep"}
+	rebuilt.Workspaces["WS-A"] = ws
+	delete(rebuilt.Workspaces, "WS-B")
+	if err := manifest.Save(rootDir, rebuilt); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	plan = applyPlan(nil)
+	if len(plan.Changes) != 0 || len(plan.LabelUpdates) != 1 {
+		t.Fatalf("expected a label-only plan, got changes=%+v labels=%+v", plan.Changes, plan.LabelUpdates)
+	}
+	meta, err = workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-A"))
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if strings.Join(meta.Labels, ",") != "backend,keep" {
+		t.Fatalf("expected updated labels in metadata, got %+v", meta)
+	}
+	rebuilt, err = manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	if strings.Join(rebuilt.Workspaces["WS-A"].Labels, ",") != "backend,keep" {
+		t.Fatalf("expected updated labels in %s, got %+v", manifest.FileName, rebuilt.Workspaces["WS-A"])
+	}
+}
@@ -26,10 +26,18 @@ func runManifest(ctx context.Context, rootDir string, args []string, noPrompt bo
 		return runManifestAdd(ctx, rootDir, args[1:], noPrompt)
 	case "rm":
 		return runManifestRm(ctx, rootDir, args[1:], noPrompt)
+	case "mv":
+		return runManifestMv(ctx, rootDir, args[1:], noPrompt)
+	case "cp":
+		return runManifestCp(ctx, rootDir, args[1:], noPrompt)
 	case "gc":
 		return runManifestGc(ctx, rootDir, args[1:], noPrompt)
 	case "validate":
 		return runManifestValidate(ctx, rootDir, args[1:])
+	case "schema":
+		return runManifestSchema(args[1:])
+	case "migrate":
+		return runManifestMigrate(ctx, rootDir, args[1:], noPrompt)
 	case "preset", "pre", "p":
 		return runManifestPreset(ctx, rootDir, args[1:], noPrompt)
 	default:
@@ -42,13 +50,15 @@ func runManifestLs(ctx context.Context, rootDir string, args []string) error {
 	lsFlags.SetOutput(os.Stdout)
 	var helpFlag bool
 	var noPrompt bool
+	var labelFlags stringSliceFlag
 	lsFlags.BoolVar(&helpFlag, "help", false, "show help")
+	lsFlags.Var(&labelFlags, "label", "only list workspaces with this label (repeatable)")
 	lsFlags.BoolVar(&helpFlag, "h", false, "show help")
 	lsFlags.BoolVar(&noPrompt, "no-prompt", false, "disable interactive prompt (no effect)")
 	lsFlags.Usage = func() {
 		printManifestLsHelp(os.Stdout)
 	}
-	if err := lsFlags.Parse(args); err != nil {
+	if err := lsFlags.Parse(normalizeArgsFlagsFirst(args, map[string]struct{}{"--label": {}, "-label": {}})); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
@@ -60,14 +70,18 @@ func runManifestLs(ctx context.Context, rootDir string, args []string) error {
 		return nil
 	}
 	if lsFlags.NArg() != 0 {
-		return fmt.Errorf("usage: gion manifest ls [--no-prompt]")
+		return fmt.Errorf("usage: gion manifest ls [--label <label>]... [--no-prompt]")
+	}
+	labelFilter, err := workspace.ParseLabelFilter(labelFlags)
+	if err != nil {
+		return err
 	}
 
 	theme := ui.DefaultTheme()
 	useColor := isatty.IsTerminal(os.Stdout.Fd())
 	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
 
-	result, err := manifestls.List(ctx, rootDir)
+	result, err := manifestls.List(ctx, rootDir, labelFilter)
 	if err != nil {
 		return err
 	}
@@ -93,15 +107,15 @@ func runManifestLs(ctx context.Context, rootDir string, args []string) error {
 
 	renderer.Section("Result")
 	for _, entry := range result.ManifestEntries {
-		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, entry.Description, false))
+		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, entry.Expired, entry.Labels, entry.Description, false))
 	}
 	for _, entry := range result.ExtraEntries {
-		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, "", true))
+		renderer.Bullet(formatManifestLsLine(renderer, entry.WorkspaceID, entry.Drift, entry.Risk, entry.HasWorkspace, entry.Expired, entry.Labels, "", true))
 	}
 	return nil
 }
 
-func formatManifestLsLine(r *ui.Renderer, workspaceID string, drift manifestls.DriftStatus, riskKind workspace.WorkspaceStateKind, hasWorkspace bool, description string, isExtra bool) string {
+func formatManifestLsLine(r *ui.Renderer, workspaceID string, drift manifestls.DriftStatus, riskKind workspace.WorkspaceStateKind, hasWorkspace bool, expired bool, labels []string, description string, isExtra bool) string {
 	line := strings.TrimSpace(workspaceID)
 	if line == "" {
 		line = "<unknown>"
@@ -111,12 +125,25 @@ func formatManifestLsLine(r *ui.Renderer, workspaceID string, drift manifestls.D
 	} else {
 		line += fmt.Sprintf(" (%s)", drift)
 	}
+	if expired {
+		badge := "[expired]"
+		if r != nil {
+			badge = r.WarnText(badge)
+		}
+		line += " " + badge
+	}
 	if hasWorkspace {
 		tag := strings.TrimSpace(formatRiskTag(r, riskKind))
 		if tag != "" {
 			line += " " + tag
 		}
 	}
+	if tags := formatLabelTags(labels); tags != "" {
+		if r != nil {
+			tags = r.MutedText(tags)
+		}
+		line += " " + tags
+	}
 	desc := strings.TrimSpace(description)
 	if !isExtra && desc != "" {
 		line += " - " + desc
@@ -124,6 +151,17 @@ func formatManifestLsLine(r *ui.Renderer, workspaceID string, drift manifestls.D
 	return line
 }
 
+// formatLabelTags renders labels as "#a #b".
+func formatLabelTags(labels []string) string {
+	tags := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != "" {
+			tags = append(tags, "#"+label)
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
 func formatRiskTag(r *ui.Renderer, kind workspace.WorkspaceStateKind) string {
 	if r == nil {
 		return ""
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/tasuku43/gion/internal/app/manifestplan"
@@ -25,8 +26,12 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 	var reviewFlag boolFlag
 	var issueFlag boolFlag
 	var repoFlag stringFlag
+	var reviewQuery stringFlag
+	var issueQuery stringFlag
 	var branch string
 	var baseRef string
+	var ttl string
+	var host string
 	var helpFlag bool
 	var noApply bool
 	var noPromptFlag bool
@@ -35,9 +40,13 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 	addFlags.Var(&reviewFlag, "review", "add review workspace from PR")
 	addFlags.Var(&issueFlag, "issue", "add issue workspace from issue")
 	addFlags.Var(&repoFlag, "repo", "add workspace from a repo")
+	addFlags.Var(&reviewQuery, "review-query", "add review workspaces for PRs matching a search query")
+	addFlags.Var(&issueQuery, "issue-query", "add issue workspaces for issues matching a search query")
 	addFlags.Var(&workspaceIDFlag, "workspace-id", "not supported (use positional WORKSPACE_ID)")
 	addFlags.StringVar(&branch, "branch", "", "branch name")
 	addFlags.StringVar(&baseRef, "base", "", "base ref")
+	addFlags.StringVar(&ttl, "ttl", "", "expire the workspace after this duration (e.g. 72h, 3d)")
+	addFlags.StringVar(&host, "host", "", "GitHub host searched by --review-query/--issue-query (default: github.com)")
 	addFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
 	addFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
 	addFlags.BoolVar(&helpFlag, "help", false, "show help")
@@ -70,6 +79,8 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 	reviewMode := reviewFlag.value
 	issueMode := issueFlag.value
 	repoMode := repoFlag.set
+	reviewQueryMode := reviewQuery.set
+	issueQueryMode := issueQuery.set
 	modeCount := 0
 	if presetMode {
 		modeCount++
@@ -83,24 +94,52 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 	if repoMode {
 		modeCount++
 	}
+	if reviewQueryMode {
+		modeCount++
+	}
+	if issueQueryMode {
+		modeCount++
+	}
 	if modeCount > 1 {
-		return fmt.Errorf("specify exactly one mode: --preset, --review, --issue, or --repo")
+		return fmt.Errorf("specify exactly one mode: --preset, --review, --review-query, --issue, --issue-query, or --repo")
+	}
+	host = strings.TrimSpace(host)
+	if host != "" && !reviewQueryMode && !issueQueryMode {
+		return fmt.Errorf("--host is only valid with --review-query or --issue-query")
+	}
+	if host == "" {
+		host = defaultSearchHost
+	}
+
+	expiresAt, err := manifestAddExpiresAt(ttl, time.Now())
+	if err != nil {
+		return err
 	}
 
 	theme := ui.DefaultTheme()
 	useColor := isatty.IsTerminal(os.Stdout.Fd())
 
-	manifestPath := manifest.Path(rootDir)
-	originalBytes, err := os.ReadFile(manifestPath)
+	original, err := manifest.TakeSnapshot(rootDir)
 	if err != nil {
-		return fmt.Errorf("read %s: %w", manifest.FileName, err)
+		return err
 	}
 
 	apply := func(updated manifest.File, showInputs func(*ui.Renderer), addedWorkspaceIDs []string) error {
+		if expiresAt != "" {
+			for _, id := range addedWorkspaceIDs {
+				if ws, ok := updated.Workspaces[id]; ok {
+					ws.ExpiresAt = expiresAt
+					updated.Workspaces[id] = ws
+				}
+			}
+		}
+		if err := checkBranchPattern(updated, addedWorkspaceIDs); err != nil {
+			return err
+		}
 		return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
-			NoApply:       noApply,
-			NoPrompt:      noPrompt,
-			OriginalBytes: originalBytes,
+			NoApply:  noApply,
+			NoPrompt: noPrompt,
+			Original: original,
 			Hooks: manifestMutationHooks{
 				ShowPrelude: showInputs,
 				RenderNoApply: func(r *ui.Renderer) {
@@ -179,18 +218,38 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 			if err != nil {
 				return nil, err
 			}
-			return buildIssueChoices(issues), nil
+			file, err := manifest.Load(rootDir)
+			if err != nil {
+				return nil, err
+			}
+			choices := buildIssueChoices(issues)
+			namer := newBranchNamer(ctx, file)
+			for i, issue := range issues {
+				// A template that cannot be expanded for an issue leaves the branch to the prompt.
+				branch, err := namer.issueBranch(formatIssueWorkspaceID(selected.Owner, selected.Repo, issue.Number), issue.Number, issue.Title)
+				if err == nil {
+					choices[i].Branch = branch
+				}
+			}
+			return choices, nil
 		}
-		loadPresetRepos := func(name string) ([]string, error) {
+		loadPresetRepos := func(name, workspaceID string) ([]string, []string, error) {
 			file, err := preset.Load(rootDir)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
-			tmpl, ok := file.Presets[name]
-			if !ok {
-				return nil, fmt.Errorf("preset not found: %s", name)
+			tmpl, err := file.ResolvePreset(name)
+			if err != nil {
+				return nil, nil, err
 			}
-			return append([]string(nil), tmpl.Repos...), nil
+			branches, err := newBranchNamer(ctx, file).presetBranches(tmpl, name, workspaceID)
+			if err != nil {
+				return nil, nil, err
+			}
+			return tmpl.RepoSpecs(), branches, nil
+		}
+		loadRepoBranch := func(workspaceID string) (string, error) {
+			return defaultRepoBranch(ctx, rootDir, workspaceID)
 		}
 		validateBranch := func(v string) error {
 			return workspace.ValidateBranchName(ctx, v)
@@ -213,6 +272,7 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 			loadReview,
 			loadIssue,
 			loadPresetRepos,
+			loadRepoBranch,
 			nil,
 			validateBranch,
 			validateWorkspaceID,
@@ -226,13 +286,13 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 
 		switch mode {
 		case "preset":
-			return manifestAddPreset(ctx, rootDir, tmplName, tmplWorkspaceID, tmplDesc, tmplBranches, baseRef, apply, originalBytes)
+			return manifestAddPreset(ctx, rootDir, tmplName, tmplWorkspaceID, tmplDesc, tmplBranches, baseRef, apply, original)
 		case "repo":
-			return manifestAddRepo(ctx, rootDir, repoSelected, tmplWorkspaceID, tmplDesc, tmplBranches, baseRef, apply, originalBytes)
+			return manifestAddRepo(ctx, rootDir, repoSelected, tmplWorkspaceID, tmplDesc, tmplBranches, baseRef, apply, original)
 		case "review":
-			return manifestAddReviewSelected(ctx, rootDir, reviewRepo, reviewPRs, apply, originalBytes)
+			return manifestAddReviewSelected(ctx, rootDir, reviewRepo, reviewPRs, apply, original)
 		case "issue":
-			return manifestAddIssueSelected(ctx, rootDir, issueRepo, issueSelections, baseRef, apply, originalBytes)
+			return manifestAddIssueSelected(ctx, rootDir, issueRepo, issueSelections, baseRef, apply, original)
 		default:
 			return fmt.Errorf("unknown mode: %s", mode)
 		}
@@ -260,13 +320,13 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 		if err != nil {
 			return err
 		}
-		tmpl, ok := file.Presets[presetName.value]
-		if !ok {
-			return fmt.Errorf("preset not found: %s", presetName.value)
+		tmpl, err := file.ResolvePreset(presetName.value)
+		if err != nil {
+			return err
 		}
-		branches := make([]string, len(tmpl.Repos))
-		for i := range branches {
-			branches[i] = workspaceID
+		branches, err := newBranchNamer(ctx, file).presetBranches(tmpl, presetName.value, workspaceID)
+		if err != nil {
+			return err
 		}
 
 		renderInputs := func(r *ui.Renderer) {
@@ -276,8 +336,12 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 			r.Bullet(fmt.Sprintf("workspace id: %s", workspaceID))
 			r.Bullet("branches")
 			var branchLines []string
-			for i, repoSpec := range tmpl.Repos {
-				branchLines = append(branchLines, fmt.Sprintf("%s: %s", displayRepoName(repoSpec), branches[i]))
+			for i, entry := range tmpl.Repos {
+				line := fmt.Sprintf("%s: %s", displayRepoName(entry.Repo), branches[i])
+				if entryBase := presetRepoBaseRef(entry, baseRef); entryBase != "" {
+					line += fmt.Sprintf(" (base: %s)", entryBase)
+				}
+				branchLines = append(branchLines, line)
 			}
 			renderTreeLines(r, branchLines, treeLineNormal)
 		}
@@ -311,7 +375,9 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 		}
 		branchValue := strings.TrimSpace(branch)
 		if branchValue == "" {
-			branchValue = workspaceID
+			if branchValue, err = defaultRepoBranch(ctx, rootDir, workspaceID); err != nil {
+				return err
+			}
 		}
 		if err := workspace.ValidateBranchName(ctx, branchValue); err != nil {
 			return err
@@ -366,6 +432,34 @@ func runManifestAdd(ctx context.Context, rootDir string, args []string, globalNo
 		return manifestAddIssueURL(ctx, rootDir, issueURL, branch, baseRef, noPrompt, apply)
 	}
 
+	if reviewQueryMode {
+		if len(remaining) != 0 {
+			return fmt.Errorf("usage: gion manifest add --review-query <query>")
+		}
+		if branch != "" || baseRef != "" {
+			return fmt.Errorf("--branch and --base are not valid with --review-query")
+		}
+		query := strings.TrimSpace(reviewQuery.value)
+		if query == "" {
+			return fmt.Errorf("--review-query requires a search query")
+		}
+		return manifestAddReviewQuery(ctx, rootDir, host, query, apply)
+	}
+
+	if issueQueryMode {
+		if len(remaining) != 0 {
+			return fmt.Errorf("usage: gion manifest add --issue-query <query> [--base <ref>]")
+		}
+		if branch != "" {
+			return fmt.Errorf("--branch is not valid with --issue-query")
+		}
+		query := strings.TrimSpace(issueQuery.value)
+		if query == "" {
+			return fmt.Errorf("--issue-query requires a search query")
+		}
+		return manifestAddIssueQuery(ctx, rootDir, host, query, baseRef, apply)
+	}
+
 	return fmt.Errorf("mode is required")
 }
 
@@ -377,14 +471,14 @@ func normalizeRepoSpec(raw string) (string, error) {
 	return trimmed, nil
 }
 
-func manifestAddPreset(ctx context.Context, rootDir, presetName, workspaceID, description string, branches []string, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ []byte) error {
+func manifestAddPreset(ctx context.Context, rootDir, presetName, workspaceID, description string, branches []string, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ manifest.Snapshot) error {
 	file, err := preset.Load(rootDir)
 	if err != nil {
 		return err
 	}
-	tmpl, ok := file.Presets[presetName]
-	if !ok {
-		return fmt.Errorf("preset not found: %s", presetName)
+	tmpl, err := file.ResolvePreset(presetName)
+	if err != nil {
+		return err
 	}
 	return manifestAddPresetWithFile(ctx, rootDir, presetName, workspaceID, description, tmpl, branches, baseRef, apply, nil)
 }
@@ -408,24 +502,37 @@ func manifestAddPresetWithFile(ctx context.Context, rootDir, presetName, workspa
 		return fmt.Errorf("workspace exists on filesystem but missing in %s: %s (suggest: gion import)", manifest.FileName, workspaceID)
 	}
 
+	var defaults []string
 	var repos []manifest.Repo
-	for i, repoSpec := range tmpl.Repos {
-		spec, _, err := repo.Normalize(repoSpec)
+	for i, entry := range tmpl.Repos {
+		spec, _, err := repo.Normalize(entry.Repo)
 		if err != nil {
 			return err
 		}
-		branchValue := workspaceID
-		if len(branches) == len(tmpl.Repos) && i < len(branches) && strings.TrimSpace(branches[i]) != "" {
+		branchValue := ""
+		if len(branches) == len(tmpl.Repos) {
 			branchValue = strings.TrimSpace(branches[i])
 		}
+		if branchValue == "" {
+			if defaults == nil {
+				if defaults, err = newBranchNamer(ctx, desired).presetBranches(tmpl, presetName, workspaceID); err != nil {
+					return err
+				}
+			}
+			branchValue = defaults[i]
+		}
 		if err := workspace.ValidateBranchName(ctx, branchValue); err != nil {
 			return err
 		}
+		alias := strings.TrimSpace(entry.Alias)
+		if alias == "" {
+			alias = strings.TrimSpace(spec.Repo)
+		}
 		repos = append(repos, manifest.Repo{
-			Alias:   strings.TrimSpace(spec.Repo),
+			Alias:   alias,
 			RepoKey: strings.TrimSpace(spec.RepoKey),
 			Branch:  branchValue,
-			BaseRef: strings.TrimSpace(baseRef),
+			BaseRef: presetRepoBaseRef(entry, baseRef),
 		})
 	}
 
@@ -438,21 +545,44 @@ func manifestAddPresetWithFile(ctx context.Context, rootDir, presetName, workspa
 	return apply(desired, showInputs, []string{workspaceID})
 }
 
-func manifestAddRepo(ctx context.Context, rootDir, repoSpec, workspaceID, description string, branches []string, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ []byte) error {
+// presetRepoBaseRef returns the base ref of a preset repo: --base wins over the preset default.
+func presetRepoBaseRef(entry preset.Repo, baseRef string) string {
+	if trimmed := strings.TrimSpace(baseRef); trimmed != "" {
+		return trimmed
+	}
+	return strings.TrimSpace(entry.BaseRef)
+}
+
+func manifestAddRepo(ctx context.Context, rootDir, repoSpec, workspaceID, description string, branches []string, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ manifest.Snapshot) error {
 	repoSpecNorm, err := normalizeRepoSpec(repoSpec)
 	if err != nil {
 		return err
 	}
-	branchValue := workspaceID
-	if len(branches) == 1 && strings.TrimSpace(branches[0]) != "" {
+	branchValue := ""
+	if len(branches) == 1 {
 		branchValue = strings.TrimSpace(branches[0])
 	}
+	if branchValue == "" {
+		if branchValue, err = defaultRepoBranch(ctx, rootDir, workspaceID); err != nil {
+			return err
+		}
+	}
 	if err := workspace.ValidateBranchName(ctx, branchValue); err != nil {
 		return err
 	}
 	return manifestAddRepoWithSpec(ctx, rootDir, repoSpecNorm, workspaceID, description, branchValue, baseRef, apply, nil)
 }
 
+// defaultRepoBranch returns the repo mode branch when --branch is not given:
+// branches.templates.repo, or the workspace ID.
+func defaultRepoBranch(ctx context.Context, rootDir, workspaceID string) (string, error) {
+	file, err := manifest.Load(rootDir)
+	if err != nil {
+		return "", err
+	}
+	return newBranchNamer(ctx, file).repoBranch(workspaceID)
+}
+
 func manifestAddRepoWithSpec(ctx context.Context, rootDir, repoSpec, workspaceID, description, branch, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error, showInputs func(*ui.Renderer)) error {
 	if err := workspace.ValidateWorkspaceID(ctx, workspaceID); err != nil {
 		return err
@@ -568,7 +698,7 @@ func manifestAddReviewURL(ctx context.Context, rootDir, prURL string, apply func
 	return apply(desired, renderInputs, []string{workspaceID})
 }
 
-func manifestAddReviewSelected(ctx context.Context, rootDir string, repoSpec string, selectedPRs []string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ []byte) error {
+func manifestAddReviewSelected(ctx context.Context, rootDir string, repoSpec string, selectedPRs []string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ manifest.Snapshot) error {
 	repoSpec = strings.TrimSpace(repoSpec)
 	spec, _, err := repo.Normalize(repoSpec)
 	if err != nil {
@@ -579,20 +709,50 @@ func manifestAddReviewSelected(ctx context.Context, rootDir string, repoSpec str
 		return fmt.Errorf("host is required")
 	}
 
+	prs := make([]prSummary, 0, len(selectedPRs))
+	for _, raw := range selectedPRs {
+		pr, err := decodeReviewSelection(raw)
+		if err != nil {
+			return err
+		}
+		prs = append(prs, pr)
+	}
+
 	desired, err := manifest.Load(rootDir)
 	if err != nil {
 		return err
 	}
 	updated := desired
+	addedWorkspaceIDs, warnings, err := addReviewWorkspaces(ctx, rootDir, host, prs, updated)
+	if err != nil {
+		return err
+	}
+
+	if len(addedWorkspaceIDs) == 0 {
+		if len(warnings) > 0 {
+			return fmt.Errorf("%s", warnings[0])
+		}
+		return fmt.Errorf("no selections")
+	}
+
+	showInputs := func(r *ui.Renderer) {
+		if len(warnings) == 0 {
+			return
+		}
+		renderWarningsSection(r, "warnings", warnings, false)
+		r.Blank()
+	}
+
+	return apply(updated, showInputs, addedWorkspaceIDs)
+}
+
+// addReviewWorkspaces adds one review workspace per PR to updated.Workspaces.
+// PRs that cannot be added (forks, existing workspaces, invalid ids) are reported as warnings.
+func addReviewWorkspaces(ctx context.Context, rootDir, host string, prs []prSummary, updated manifest.File) ([]string, []string, error) {
 	var warnings []string
 	var addedWorkspaceIDs []string
-	added := 0
 
-	for _, raw := range selectedPRs {
-		pr, err := decodeReviewSelection(raw)
-		if err != nil {
-			return err
-		}
+	for _, pr := range prs {
 		if !strings.EqualFold(strings.TrimSpace(pr.HeadRepo), strings.TrimSpace(pr.BaseRepo)) {
 			warnings = append(warnings, fmt.Sprintf("skipped PR #%d: fork PRs are not supported", pr.Number))
 			continue
@@ -614,22 +774,22 @@ func manifestAddReviewSelected(ctx context.Context, rootDir string, repoSpec str
 		}
 		wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
 		if exists, err := paths.DirExists(wsDir); err != nil {
-			return err
+			return nil, nil, err
 		} else if exists {
 			warnings = append(warnings, fmt.Sprintf("skipped: workspace exists on filesystem but missing in %s: %s (suggest: gion import)", manifest.FileName, workspaceID))
 			continue
 		}
 		if err := workspace.ValidateBranchName(ctx, pr.HeadRef); err != nil {
-			return err
+			return nil, nil, err
 		}
 		repoURL := buildRepoURLFromParts(host, baseOwner, baseRepo)
 		repoSpecNorm, err := normalizeRepoSpec(repoURL)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		repoNorm, _, err := repo.Normalize(repoSpecNorm)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		updated.Workspaces[workspaceID] = manifest.Workspace{
 			Description: strings.TrimSpace(pr.Title),
@@ -644,27 +804,85 @@ func manifestAddReviewSelected(ctx context.Context, rootDir string, repoSpec str
 				},
 			},
 		}
-		added++
 		addedWorkspaceIDs = append(addedWorkspaceIDs, workspaceID)
 	}
+	return addedWorkspaceIDs, warnings, nil
+}
 
-	if added == 0 {
-		if len(warnings) > 0 {
-			return fmt.Errorf("%s", warnings[0])
+func manifestAddReviewQuery(ctx context.Context, rootDir, host, query string, apply func(manifest.File, func(*ui.Renderer), []string) error) error {
+	provider, err := providerByName(providerNameForHost(host))
+	if err != nil {
+		return err
+	}
+	query = normalizeSearchQuery(query, true)
+	results, err := provider.SearchPRs(ctx, host, query)
+	if err != nil {
+		return err
+	}
+
+	var warnings []string
+	if results.Truncated() {
+		warnings = append(warnings, searchTruncatedWarning(results))
+	}
+	prs := make([]prSummary, 0, len(results.Items))
+	for _, result := range results.Items {
+		pr, err := provider.FetchPR(ctx, host, result.Owner, result.Repo, result.Number)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipped PR %s/%s#%d: %s", result.Owner, result.Repo, result.Number, compactError(err)))
+			continue
 		}
-		return fmt.Errorf("no selections")
+		prs = append(prs, pr)
 	}
 
-	showInputs := func(r *ui.Renderer) {
-		if len(warnings) == 0 {
-			return
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	updated := desired
+	addedWorkspaceIDs, addWarnings, err := addReviewWorkspaces(ctx, rootDir, host, prs, updated)
+	if err != nil {
+		return err
+	}
+	warnings = append(warnings, addWarnings...)
+
+	renderInputs := func(r *ui.Renderer) {
+		r.Section("Inputs")
+		r.Bullet("mode: review-query")
+		r.Bullet(fmt.Sprintf("query: %s", query))
+		if !strings.EqualFold(host, defaultSearchHost) {
+			r.Bullet(fmt.Sprintf("host: %s", host))
+		}
+		r.Bullet(fmt.Sprintf("matched: %d pull request(s)", len(results.Items)))
+		if len(warnings) > 0 {
+			r.Blank()
+			renderWarningsSection(r, "warnings", warnings, false)
 		}
-		renderWarningsSection(r, "warnings", warnings, false)
-		r.Blank()
 	}
+	if len(addedWorkspaceIDs) == 0 {
+		renderManifestAddQueryNoop(renderInputs)
+		return nil
+	}
+	return apply(updated, renderInputs, addedWorkspaceIDs)
+}
 
-	_ = spec
-	return apply(updated, showInputs, addedWorkspaceIDs)
+// defaultSearchHost is the host --review-query and --issue-query search unless
+// --host is given.
+const defaultSearchHost = "github.com"
+
+func searchTruncatedWarning(results searchResults) string {
+	return fmt.Sprintf("search matched %d item(s) but only %d were returned; narrow the query to cover the rest", results.Total, results.Fetched)
+}
+
+// renderManifestAddQueryNoop reports a query run that added nothing.
+// It is not an error so that scheduled runs stay idempotent.
+func renderManifestAddQueryNoop(renderInputs func(*ui.Renderer)) {
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	renderInputs(renderer)
+	renderer.Blank()
+	renderer.Section("Result")
+	renderer.Bullet("no new workspaces")
 }
 
 func manifestAddIssueURL(ctx context.Context, rootDir, issueURL, branch, baseRef string, noPrompt bool, apply func(manifest.File, func(*ui.Renderer), []string) error) error {
@@ -689,9 +907,15 @@ func manifestAddIssueURL(ctx context.Context, rootDir, issueURL, branch, baseRef
 	if err := workspace.ValidateWorkspaceID(ctx, workspaceID); err != nil {
 		return err
 	}
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
 	branchValue := strings.TrimSpace(branch)
 	if branchValue == "" {
-		branchValue = fmt.Sprintf("issue/%d", req.Number)
+		if branchValue, err = newBranchNamer(ctx, desired).issueBranch(workspaceID, req.Number, issue.Title); err != nil {
+			return err
+		}
 	}
 	if err := workspace.ValidateBranchName(ctx, branchValue); err != nil {
 		return err
@@ -715,10 +939,6 @@ func manifestAddIssueURL(ctx context.Context, rootDir, issueURL, branch, baseRef
 		}
 	}
 
-	desired, err := manifest.Load(rootDir)
-	if err != nil {
-		return err
-	}
 	if _, exists := desired.Workspaces[workspaceID]; exists {
 		return fmt.Errorf("workspace already exists in %s: %s", manifest.FileName, workspaceID)
 	}
@@ -747,7 +967,15 @@ func manifestAddIssueURL(ctx context.Context, rootDir, issueURL, branch, baseRef
 	return apply(desired, renderInputs, []string{workspaceID})
 }
 
-func manifestAddIssueSelected(ctx context.Context, rootDir string, repoSpec string, selections []ui.IssueSelection, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ []byte) error {
+type issueWorkspaceInput struct {
+	Owner  string
+	Repo   string
+	Number int
+	Title  string
+	Branch string
+}
+
+func manifestAddIssueSelected(ctx context.Context, rootDir string, repoSpec string, selections []ui.IssueSelection, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error, _ manifest.Snapshot) error {
 	repoSpec = strings.TrimSpace(repoSpec)
 	spec, _, err := repo.Normalize(repoSpec)
 	if err != nil {
@@ -760,22 +988,62 @@ func manifestAddIssueSelected(ctx context.Context, rootDir string, repoSpec stri
 	owner := strings.TrimSpace(spec.Owner)
 	repoName := strings.TrimSpace(spec.Repo)
 
+	var warnings []string
+	inputs := make([]issueWorkspaceInput, 0, len(selections))
+	for _, sel := range selections {
+		num, err := strconv.Atoi(strings.TrimSpace(sel.Value))
+		if err != nil || num <= 0 {
+			warnings = append(warnings, fmt.Sprintf("skipped issue: invalid number: %s", sel.Value))
+			continue
+		}
+		inputs = append(inputs, issueWorkspaceInput{
+			Owner:  owner,
+			Repo:   repoName,
+			Number: num,
+			Title:  issueTitleFromLabel(sel.Label, num),
+			Branch: sel.Branch,
+		})
+	}
+
 	desired, err := manifest.Load(rootDir)
 	if err != nil {
 		return err
 	}
 	updated := desired
+	addedWorkspaceIDs, addWarnings, err := addIssueWorkspaces(ctx, rootDir, host, inputs, baseRef, updated)
+	if err != nil {
+		return err
+	}
+	warnings = append(warnings, addWarnings...)
+
+	if len(addedWorkspaceIDs) == 0 {
+		if len(warnings) > 0 {
+			return fmt.Errorf("%s", warnings[0])
+		}
+		return fmt.Errorf("no selections")
+	}
+
+	showInputs := func(r *ui.Renderer) {
+		if len(warnings) == 0 {
+			return
+		}
+		renderWarningsSection(r, "warnings", warnings, false)
+		r.Blank()
+	}
+
+	return apply(updated, showInputs, addedWorkspaceIDs)
+}
+
+// addIssueWorkspaces adds one issue workspace per input to updated.Workspaces.
+// Issues that cannot be added (existing workspaces, invalid ids) are reported as warnings.
+func addIssueWorkspaces(ctx context.Context, rootDir, host string, inputs []issueWorkspaceInput, baseRef string, updated manifest.File) ([]string, []string, error) {
 	var warnings []string
 	var addedWorkspaceIDs []string
-	added := 0
+	namer := newBranchNamer(ctx, updated)
 
-	for _, sel := range selections {
-		num, err := strconv.Atoi(strings.TrimSpace(sel.Value))
-		if err != nil || num <= 0 {
-			warnings = append(warnings, fmt.Sprintf("skipped issue: invalid number: %s", sel.Value))
-			continue
-		}
-		workspaceID := formatIssueWorkspaceID(owner, repoName, num)
+	for _, input := range inputs {
+		num := input.Number
+		workspaceID := formatIssueWorkspaceID(input.Owner, input.Repo, num)
 		if err := workspace.ValidateWorkspaceID(ctx, workspaceID); err != nil {
 			warnings = append(warnings, fmt.Sprintf("skipped issue #%d: invalid workspace id: %s", num, err.Error()))
 			continue
@@ -786,25 +1054,32 @@ func manifestAddIssueSelected(ctx context.Context, rootDir string, repoSpec stri
 		}
 		wsDir := workspace.WorkspaceDir(rootDir, workspaceID)
 		if exists, err := paths.DirExists(wsDir); err != nil {
-			return err
+			return nil, nil, err
 		} else if exists {
 			warnings = append(warnings, fmt.Sprintf("skipped: workspace exists on filesystem but missing in %s: %s (suggest: gion import)", manifest.FileName, workspaceID))
 			continue
 		}
 
-		branchValue := strings.TrimSpace(sel.Branch)
+		branchValue := strings.TrimSpace(input.Branch)
 		if branchValue == "" {
-			branchValue = fmt.Sprintf("issue/%d", num)
+			var err error
+			if branchValue, err = namer.issueBranch(workspaceID, num, input.Title); err != nil {
+				warnings = append(warnings, fmt.Sprintf("skipped issue #%d: %s", num, err.Error()))
+				continue
+			}
 		}
 		if err := workspace.ValidateBranchName(ctx, branchValue); err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		title := issueTitleFromLabel(sel.Label, num)
+		spec, _, err := repo.Normalize(buildRepoURLFromParts(host, input.Owner, input.Repo))
+		if err != nil {
+			return nil, nil, err
+		}
 		updated.Workspaces[workspaceID] = manifest.Workspace{
-			Description: strings.TrimSpace(title),
+			Description: strings.TrimSpace(input.Title),
 			Mode:        workspace.MetadataModeIssue,
-			SourceURL:   buildIssueURLFromParts(host, owner, repoName, num),
+			SourceURL:   buildIssueURLFromParts(host, input.Owner, input.Repo, num),
 			Repos: []manifest.Repo{
 				{
 					Alias:   strings.TrimSpace(spec.Repo),
@@ -814,24 +1089,63 @@ func manifestAddIssueSelected(ctx context.Context, rootDir string, repoSpec stri
 				},
 			},
 		}
-		added++
 		addedWorkspaceIDs = append(addedWorkspaceIDs, workspaceID)
 	}
+	return addedWorkspaceIDs, warnings, nil
+}
 
-	if added == 0 {
-		if len(warnings) > 0 {
-			return fmt.Errorf("%s", warnings[0])
-		}
-		return fmt.Errorf("no selections")
+func manifestAddIssueQuery(ctx context.Context, rootDir, host, query, baseRef string, apply func(manifest.File, func(*ui.Renderer), []string) error) error {
+	provider, err := providerByName(providerNameForHost(host))
+	if err != nil {
+		return err
+	}
+	query = normalizeSearchQuery(query, false)
+	results, err := provider.SearchIssues(ctx, host, query)
+	if err != nil {
+		return err
+	}
+	inputs := make([]issueWorkspaceInput, 0, len(results.Items))
+	for _, result := range results.Items {
+		inputs = append(inputs, issueWorkspaceInput{
+			Owner:  result.Owner,
+			Repo:   result.Repo,
+			Number: result.Number,
+			Title:  result.Title,
+		})
 	}
 
-	showInputs := func(r *ui.Renderer) {
-		if len(warnings) == 0 {
-			return
-		}
-		renderWarningsSection(r, "warnings", warnings, false)
-		r.Blank()
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	updated := desired
+	addedWorkspaceIDs, warnings, err := addIssueWorkspaces(ctx, rootDir, host, inputs, baseRef, updated)
+	if err != nil {
+		return err
+	}
+	if results.Truncated() {
+		warnings = append([]string{searchTruncatedWarning(results)}, warnings...)
 	}
 
-	return apply(updated, showInputs, addedWorkspaceIDs)
+	renderInputs := func(r *ui.Renderer) {
+		r.Section("Inputs")
+		r.Bullet("mode: issue-query")
+		r.Bullet(fmt.Sprintf("query: %s", query))
+		if !strings.EqualFold(host, defaultSearchHost) {
+			r.Bullet(fmt.Sprintf("host: %s", host))
+		}
+		r.Bullet(fmt.Sprintf("matched: %d issue(s)", len(results.Items)))
+		if strings.TrimSpace(baseRef) != "" {
+			r.Bullet(fmt.Sprintf("base: %s", strings.TrimSpace(baseRef)))
+		}
+		if len(warnings) > 0 {
+			r.Blank()
+			renderWarningsSection(r, "warnings", warnings, false)
+		}
+	}
+	if len(addedWorkspaceIDs) == 0 {
+		renderManifestAddQueryNoop(renderInputs)
+		return nil
+	}
+	return apply(updated, renderInputs, addedWorkspaceIDs)
 }
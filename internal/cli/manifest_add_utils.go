@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/tasuku43/gion/internal/domain/manifest"
 	"github.com/tasuku43/gion/internal/domain/preset"
@@ -23,10 +24,18 @@ func normalizeManifestAddArgs(args []string) []string {
 		"-preset":        {},
 		"--repo":         {},
 		"-repo":          {},
+		"--review-query": {},
+		"-review-query":  {},
+		"--issue-query":  {},
+		"-issue-query":   {},
 		"--branch":       {},
 		"-branch":        {},
 		"--base":         {},
 		"-base":          {},
+		"--ttl":          {},
+		"-ttl":           {},
+		"--host":         {},
+		"-host":          {},
 		"--workspace-id": {},
 		"-workspace-id":  {},
 	})
@@ -99,3 +108,19 @@ func loadPresetNames(rootDir string) ([]string, error) {
 	}
 	return names, nil
 }
+
+// manifestAddExpiresAt turns --ttl into an absolute expires_at (empty when ttl is unset).
+func manifestAddExpiresAt(ttl string, now time.Time) (string, error) {
+	ttl = strings.TrimSpace(ttl)
+	if ttl == "" {
+		return "", nil
+	}
+	d, err := manifest.ParseGCDuration(ttl)
+	if err != nil {
+		return "", fmt.Errorf("--ttl: %w", err)
+	}
+	if d <= 0 {
+		return "", fmt.Errorf("--ttl must be positive: %s", ttl)
+	}
+	return manifest.FormatExpiresAt(now.Add(d)), nil
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNormalizeManifestAddArgs_ReordersFlagsAfterPositionals(t *testing.T) {
@@ -71,3 +72,32 @@ func TestNormalizeArgsFlagsFirst_ReordersNoPromptForRmStyleArgs(t *testing.T) {
 		t.Fatalf("normalizeArgsFlagsFirst() = %#v; want %#v", got, want)
 	}
 }
+
+func TestManifestAddExpiresAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		ttl     string
+		want    string
+		wantErr bool
+	}{
+		{ttl: "", want: ""},
+		{ttl: "72h", want: "2026-03-04T12:00:00Z"},
+		{ttl: "1w", want: "2026-03-08T12:00:00Z"},
+		{ttl: "0d", wantErr: true},
+		{ttl: "soon", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := manifestAddExpiresAt(tc.ttl, now)
+		if tc.wantErr {
+			if err == nil {
+				t.Fatalf("manifestAddExpiresAt(%q): expected error", tc.ttl)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Fatalf("manifestAddExpiresAt(%q) = %q, %v; want %q", tc.ttl, got, err, tc.want)
+		}
+	}
+}
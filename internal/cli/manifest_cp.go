@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runManifestCp(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	cpFlags := flag.NewFlagSet("manifest cp", flag.ContinueOnError)
+	var withChanges bool
+	var noApply bool
+	var noPromptFlag bool
+	var helpFlag bool
+	cpFlags.BoolVar(&withChanges, "with-changes", false, "bring over uncommitted changes of the source workspace")
+	cpFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
+	cpFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	cpFlags.BoolVar(&helpFlag, "help", false, "show help")
+	cpFlags.BoolVar(&helpFlag, "h", false, "show help")
+	cpFlags.SetOutput(os.Stdout)
+	cpFlags.Usage = func() {
+		printManifestCpHelp(os.Stdout)
+	}
+	if err := cpFlags.Parse(normalizeArgsFlagsFirst(args, nil)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printManifestCpHelp(os.Stdout)
+		return nil
+	}
+	if cpFlags.NArg() != 2 {
+		return fmt.Errorf("usage: gion manifest cp <SOURCE_ID> <NEW_ID> [--with-changes] [--no-apply] [--no-prompt]")
+	}
+	sourceID := strings.TrimSpace(cpFlags.Arg(0))
+	newID := strings.TrimSpace(cpFlags.Arg(1))
+	if err := workspace.ValidateWorkspaceID(ctx, newID); err != nil {
+		return err
+	}
+
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	source, ok := desired.Workspaces[sourceID]
+	if !ok {
+		return fmt.Errorf("workspace not found in %s: %s", manifest.FileName, sourceID)
+	}
+	if _, exists := desired.Workspaces[newID]; exists {
+		return fmt.Errorf("workspace already exists in %s: %s", manifest.FileName, newID)
+	}
+	sourceDir := workspace.WorkspaceDir(rootDir, sourceID)
+	if exists, err := paths.DirExists(sourceDir); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("workspace %s does not exist on the filesystem (run gion apply first)", sourceID)
+	}
+	meta, err := workspace.LoadMetadata(sourceDir)
+	if err != nil {
+		return err
+	}
+	original, err := manifest.TakeSnapshot(rootDir)
+	if err != nil {
+		return err
+	}
+
+	entry := copyWorkspaceEntry(source, sourceID, newID, meta.BaseBranch)
+	entry.CopyChanges = withChanges
+	updated := desired
+	updated.Workspaces = make(map[string]manifest.Workspace, len(desired.Workspaces)+1)
+	for id, ws := range desired.Workspaces {
+		updated.Workspaces[id] = ws
+	}
+	updated.Workspaces[newID] = entry
+
+	summary := fmt.Sprintf("copied %s -> %s", sourceID, newID)
+	if withChanges {
+		summary += " with changes"
+	}
+
+	return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
+		NoApply:  noApply,
+		NoPrompt: noPrompt,
+		Original: original,
+		Hooks: manifestMutationHooks{
+			ShowPrelude: func(r *ui.Renderer) {
+				r.Section("Inputs")
+				r.Bullet(fmt.Sprintf("workspace: %s -> %s", sourceID, newID))
+				for _, repoEntry := range entry.Repos {
+					if ref := strings.TrimSpace(repoEntry.Ref); ref != "" {
+						r.Bullet(fmt.Sprintf("repo: %s (ref: %s)", repoEntry.Alias, ref))
+						continue
+					}
+					r.Bullet(fmt.Sprintf("repo: %s (branch: %s)", repoEntry.Alias, repoEntry.Branch))
+				}
+			},
+			RenderNoApply: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("updated %s (%s)", manifest.FileName, summary))
+				r.Blank()
+				r.Section("Suggestion")
+				r.Bullet("gion apply")
+			},
+			RenderNoChanges: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("updated %s (%s)", manifest.FileName, summary))
+				r.Bullet("no changes")
+			},
+			RenderInfoBeforeApply: func(r *ui.Renderer, _ manifestplan.Result, _ bool) {
+				r.Section("Info")
+				r.Bullet(fmt.Sprintf("manifest: updated %s (%s)", manifest.FileName, summary))
+				r.Bullet("apply: reconciling entire root (destructive removals require confirmation)")
+			},
+		},
+	})
+}
+
+// copyWorkspaceEntry builds the gion.yaml entry of a copy of source. Branches
+// containing the source ID get the new ID instead (feature/OLD -> feature/NEW);
+// other branches are replaced by the new ID. Pinned repos keep their ref, and
+// base_ref falls back to the source's recorded base branch. A review workspace
+// is copied as a repo workspace, since the copy no longer tracks the PR head.
+func copyWorkspaceEntry(source manifest.Workspace, sourceID, newID, baseBranch string) manifest.Workspace {
+	mode := source.Mode
+	if strings.EqualFold(strings.TrimSpace(mode), workspace.MetadataModeReview) {
+		mode = workspace.MetadataModeRepo
+	}
+	entry := manifest.Workspace{
+		Description: source.Description,
+		Mode:        mode,
+		PresetName:  source.PresetName,
+		SourceURL:   source.SourceURL,
+		Labels:      append([]string(nil), source.Labels...),
+		CopiedFrom:  sourceID,
+	}
+	baseBranch = strings.TrimSpace(baseBranch)
+	if !strings.HasPrefix(baseBranch, "origin/") {
+		baseBranch = ""
+	}
+	for _, repoEntry := range source.Repos {
+		if strings.TrimSpace(repoEntry.Ref) != "" {
+			entry.Repos = append(entry.Repos, repoEntry)
+			continue
+		}
+		branch := strings.TrimSpace(repoEntry.Branch)
+		if strings.Contains(branch, sourceID) {
+			branch = strings.ReplaceAll(branch, sourceID, newID)
+		} else {
+			branch = newID
+		}
+		baseRef := strings.TrimSpace(repoEntry.BaseRef)
+		if baseRef == "" {
+			baseRef = baseBranch
+		}
+		entry.Repos = append(entry.Repos, manifest.Repo{
+			Alias:   repoEntry.Alias,
+			RepoKey: repoEntry.RepoKey,
+			Branch:  branch,
+			BaseRef: baseRef,
+		})
+	}
+	return entry
+}
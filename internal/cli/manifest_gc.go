@@ -8,11 +8,14 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	coregcplan "github.com/tasuku43/gion-core/gcplan"
 	coregitparse "github.com/tasuku43/gion-core/gitparse"
 	coregitref "github.com/tasuku43/gion-core/gitref"
+	"github.com/tasuku43/gion/internal/app/archive"
+	"github.com/tasuku43/gion/internal/app/gcpolicy"
 	"github.com/tasuku43/gion/internal/app/manifestplan"
 	"github.com/tasuku43/gion/internal/domain/manifest"
 	"github.com/tasuku43/gion/internal/domain/repo"
@@ -25,6 +28,11 @@ type manifestGcCandidate struct {
 	WorkspaceID string
 	Targets     []string
 	Reason      string
+	// Rule is the matched policy rule (empty for the default rules).
+	Rule string
+	// Archive is the state of a non-clean workspace that --archive-dirty
+	// archives before removal (empty for clean workspaces).
+	Archive workspace.WorkspaceStateKind
 }
 
 type manifestGcFetchResult struct {
@@ -37,10 +45,22 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 	gcFlags := flag.NewFlagSet("manifest gc", flag.ContinueOnError)
 	var noApply bool
 	var noFetch bool
+	var noProvider bool
+	var dryRun bool
+	var expiredMode bool
+	var archiveDirty bool
+	var policyName string
+	var labelFlags stringSliceFlag
 	var noPromptFlag bool
 	var helpFlag bool
 	gcFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
 	gcFlags.BoolVar(&noFetch, "no-fetch", false, "disable git fetch for repo stores")
+	gcFlags.BoolVar(&noProvider, "no-provider", false, "disable PR/issue state lookups")
+	gcFlags.BoolVar(&dryRun, "dry-run", false, "list candidates without changing gion.yaml")
+	gcFlags.StringVar(&policyName, "policy", "", "gc policy name from gion.yaml")
+	gcFlags.BoolVar(&expiredMode, "expired", false, "remove workspaces past their expires_at")
+	gcFlags.Var(&labelFlags, "label", "only consider workspaces with this label (repeatable)")
+	gcFlags.BoolVar(&archiveDirty, "archive-dirty", false, "archive dirty candidates before removal instead of skipping them")
 	gcFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
 	gcFlags.BoolVar(&helpFlag, "help", false, "show help")
 	gcFlags.BoolVar(&helpFlag, "h", false, "show help")
@@ -48,7 +68,7 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 	gcFlags.Usage = func() {
 		printManifestGcHelp(os.Stdout)
 	}
-	if err := gcFlags.Parse(args); err != nil {
+	if err := gcFlags.Parse(normalizeArgsFlagsFirst(args, map[string]struct{}{"--policy": {}, "-policy": {}, "--label": {}, "-label": {}})); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
@@ -59,7 +79,14 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 		return nil
 	}
 	if gcFlags.NArg() != 0 {
-		return fmt.Errorf("usage: gion manifest gc [--no-apply] [--no-fetch] [--no-prompt]")
+		return fmt.Errorf("usage: gion manifest gc [--policy <name> | --expired] [--label <label>]... [--archive-dirty] [--dry-run] [--no-apply] [--no-fetch] [--no-provider] [--no-prompt]")
+	}
+	labelFilter, err := workspace.ParseLabelFilter(labelFlags)
+	if err != nil {
+		return err
+	}
+	if expiredMode && strings.TrimSpace(policyName) != "" {
+		return fmt.Errorf("--expired cannot be combined with --policy")
 	}
 
 	noPrompt := globalNoPrompt || noPromptFlag
@@ -69,29 +96,47 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 		return err
 	}
 
-	manifestPath := manifest.Path(rootDir)
-	originalBytes, err := os.ReadFile(manifestPath)
+	var policy *manifest.GCPolicy
+	policyName = strings.TrimSpace(policyName)
+	if policyName != "" {
+		p, ok := desired.GC.Policies[policyName]
+		if !ok {
+			return fmt.Errorf("gc policy not found: %s (available: %s)", policyName, manifestGcPolicyNames(desired.GC))
+		}
+		policy = &p
+	}
+	now := time.Now()
+
+	original, err := manifest.TakeSnapshot(rootDir)
 	if err != nil {
-		return fmt.Errorf("read %s: %w", manifest.FileName, err)
+		return err
 	}
 
 	ids := make([]string, 0, len(desired.Workspaces))
-	for id := range desired.Workspaces {
+	for id, ws := range desired.Workspaces {
+		if !workspace.MatchLabels(ws.Labels, labelFilter) {
+			continue
+		}
+		if expiredMode && !ws.Expired(now) {
+			continue
+		}
 		ids = append(ids, id)
 	}
 	sort.Strings(ids)
 
 	var warnings []error
 	var candidates []manifestGcCandidate
+	var kept []manifestGcCandidate
 	scanned := 0
 	skipped := 0
 
 	fetchErrors := make(map[string]error)
 	defaultTargets := make(map[string]string)
-	if !noFetch {
+	// --expired only needs the risk checks; merge checks (and their fetches) are skipped.
+	if !noFetch && !expiredMode {
 		reposByKey := make(map[string][]manifest.Repo)
-		for _, ws := range desired.Workspaces {
-			for _, repoEntry := range ws.Repos {
+		for _, id := range ids {
+			for _, repoEntry := range desired.Workspaces[id].Repos {
 				repoKey := strings.TrimSpace(repoEntry.RepoKey)
 				if repoKey == "" {
 					continue
@@ -149,53 +194,77 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 			skipped++
 			continue
 		}
+		var archiveState workspace.WorkspaceStateKind
 		if state.Kind != workspace.WorkspaceStateClean {
-			skipped++
+			if !archiveDirty || state.Kind == workspace.WorkspaceStateUnknown {
+				skipped++
+				continue
+			}
+			archiveState = state.Kind
+		}
+
+		if expiredMode {
+			candidates = append(candidates, manifestGcCandidate{
+				WorkspaceID: id,
+				Reason:      manifestGcExpiredReason(ws, now),
+				Archive:     archiveState,
+			})
 			continue
 		}
 
-		var repoTargets []string
-		allMerged := true
-		for _, repoEntry := range ws.Repos {
-			if err := fetchErrors[repoEntry.RepoKey]; err != nil {
-				warnings = append(warnings, fmt.Errorf("%s: %s: fetch failed: %w", id, repoEntryLabel(repoEntry), err))
-				allMerged = false
-				break
-			}
-			target, ok, err := resolveMergeTarget(ctx, rootDir, repoEntry, defaultTargets)
-			if err != nil {
-				warnings = append(warnings, fmt.Errorf("%s: %s: resolve merge target: %w", id, repoEntryLabel(repoEntry), err))
-				allMerged = false
-				break
-			}
+		if policy == nil {
+			reason, repoTargets, ok, mergeWarnings := evaluateManifestGcMerged(ctx, rootDir, id, ws, noProvider, fetchErrors, defaultTargets)
+			warnings = append(warnings, mergeWarnings...)
 			if !ok {
-				warnings = append(warnings, fmt.Errorf("%s: %s: merge target unavailable", id, repoEntryLabel(repoEntry)))
-				allMerged = false
-				break
-			}
-			repoTargets = append(repoTargets, fmt.Sprintf("%s=%s", repoEntryLabel(repoEntry), target))
-
-			merged, err := strictMergedIntoTarget(ctx, rootDir, repoEntry, target)
-			if err != nil {
-				warnings = append(warnings, fmt.Errorf("%s: %s: merged check failed: %w", id, repoEntryLabel(repoEntry), err))
-				allMerged = false
-				break
-			}
-			if !merged {
-				allMerged = false
-				break
+				skipped++
+				continue
 			}
+			candidates = append(candidates, manifestGcCandidate{
+				WorkspaceID: id,
+				Targets:     repoTargets,
+				Reason:      reason,
+				Archive:     archiveState,
+			})
+			continue
 		}
 
-		if !allMerged {
+		var repoTargets []string
+		match, ok, err := gcpolicy.Evaluate(*policy, gcpolicy.Facts{
+			Mode:      ws.Mode,
+			Labels:    ws.Labels,
+			ExpiresAt: ws.ExpiresAt,
+			Activity: func() (gcpolicy.Activity, error) {
+				return gcpolicy.WorkspaceActivity(ctx, rootDir, id)
+			},
+			Merged: func() (string, bool, error) {
+				reason, targets, ok, mergeWarnings := evaluateManifestGcMerged(ctx, rootDir, id, ws, noProvider, fetchErrors, defaultTargets)
+				warnings = append(warnings, mergeWarnings...)
+				repoTargets = targets
+				return reason, ok, nil
+			},
+		}, now)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: policy %s: %w", id, policyName, err))
+			skipped++
+			continue
+		}
+		if !ok {
 			skipped++
 			continue
 		}
-		candidates = append(candidates, manifestGcCandidate{
+		candidate := manifestGcCandidate{
 			WorkspaceID: id,
 			Targets:     repoTargets,
-			Reason:      "merged",
-		})
+			Reason:      match.Reason,
+			Rule:        match.Rule,
+		}
+		if match.Action == manifest.GCActionKeep {
+			kept = append(kept, candidate)
+			skipped++
+			continue
+		}
+		candidate.Archive = archiveState
+		candidates = append(candidates, candidate)
 	}
 
 	theme := ui.DefaultTheme()
@@ -206,10 +275,21 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 	for _, warn := range warnings {
 		warningLines = append(warningLines, compactError(warn))
 	}
+	if dryRun {
+		renderer.Section("Info")
+		renderManifestGcInfo(renderer, policyName, candidates, kept, warningLines)
+		if policyName == "" && len(candidates) == 0 && len(warningLines) == 0 {
+			renderer.Bullet("no candidates")
+		}
+		renderer.Blank()
+		renderer.Section("Result")
+		renderer.Bullet(fmt.Sprintf("dry run: %d candidate(s) (%s not modified)", len(candidates), manifest.FileName))
+		return nil
+	}
 	if len(candidates) == 0 {
-		if len(warningLines) > 0 {
+		if len(warningLines) > 0 || len(kept) > 0 || policyName != "" {
 			renderer.Section("Info")
-			renderManifestGcWarnings(renderer, warningLines)
+			renderManifestGcInfo(renderer, policyName, nil, kept, warningLines)
 			renderer.Blank()
 		}
 		renderer.Section("Result")
@@ -217,6 +297,31 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 		return nil
 	}
 
+	// Archive dirty candidates first: once they leave gion.yaml, apply removes them.
+	// They are deleted again if gc is declined.
+	var created []archive.Archive
+	var archives []string
+	removeArchives := func() error {
+		var errs []error
+		for _, archived := range created {
+			if err := archived.Remove(); err != nil {
+				errs = append(errs, fmt.Errorf("remove archive %s: %w", archived.Dir, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	for _, c := range candidates {
+		if c.Archive == "" {
+			continue
+		}
+		archived, err := archive.Create(ctx, rootDir, c.WorkspaceID, desired.Workspaces[c.WorkspaceID], now)
+		if err != nil {
+			return errors.Join(fmt.Errorf("%s: archive: %w", c.WorkspaceID, err), removeArchives())
+		}
+		created = append(created, archived)
+		archives = append(archives, archived.Name())
+	}
+
 	updated := desired
 	for _, c := range candidates {
 		delete(updated.Workspaces, c.WorkspaceID)
@@ -228,13 +333,15 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 	}
 
 	return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
-		NoApply:       noApply,
-		NoPrompt:      noPrompt,
-		OriginalBytes: originalBytes,
+		NoApply:  noApply,
+		NoPrompt: noPrompt,
+		Original: original,
+		Rollback: removeArchives,
 		Hooks: manifestMutationHooks{
 			RenderNoApply: func(r *ui.Renderer) {
 				r.Section("Info")
-				renderManifestGcInfo(r, candidates, warningLines)
+				renderManifestGcInfo(r, policyName, candidates, kept, warningLines)
+				renderManifestGcArchives(r, archives)
 				r.Blank()
 				r.Section("Result")
 				r.Bullet(fmt.Sprintf("updated %s (removed %d workspace(s))", manifest.FileName, len(candidateIDs)))
@@ -249,7 +356,8 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 			},
 			RenderInfoBeforeApply: func(r *ui.Renderer, plan manifestplan.Result, _ bool) {
 				r.Section("Info")
-				renderManifestGcInfo(r, candidates, warningLines)
+				renderManifestGcInfo(r, policyName, candidates, kept, warningLines)
+				renderManifestGcArchives(r, archives)
 				r.Bullet(r.AccentText("manifest:") + " " + r.SuccessText("updated") + " " + manifest.FileName + " (" + r.ErrorText(fmt.Sprintf("removed %d workspace(s)", len(candidateIDs))) + ")")
 				if planIncludesChangesOutsideWorkspaceIDs(plan, candidateIDs) {
 					r.Bullet(r.AccentText("apply:") + " reconciling entire root (" + r.WarnText("plan includes changes outside GC scope") + ")")
@@ -261,27 +369,186 @@ func runManifestGc(ctx context.Context, rootDir string, args []string, globalNoP
 	})
 }
 
-func renderManifestGcInfo(r *ui.Renderer, candidates []manifestGcCandidate, warningLines []string) {
+func manifestGcExpiredReason(ws manifest.Workspace, now time.Time) string {
+	expiresAt, err := manifest.ParseExpiresAt(ws.ExpiresAt)
+	if err != nil {
+		return "expired"
+	}
+	return "expired " + gcpolicy.FormatAge(now.Sub(expiresAt)) + " ago"
+}
+
+// evaluateManifestGcMerged applies the default gc rules to a clean workspace: the
+// provider state of its PR/issue first, then per-repo strict/patch merge checks.
+// Check failures are returned as warnings and make the workspace ineligible.
+func evaluateManifestGcMerged(ctx context.Context, rootDir, id string, ws manifest.Workspace, noProvider bool, fetchErrors map[string]error, defaultTargets map[string]string) (string, []string, bool, []error) {
+	var warnings []error
+	if !noProvider {
+		reason, ok, err := manifestGcProviderReason(ctx, ws)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: provider state unavailable: %w", id, err))
+		} else if ok {
+			return reason, nil, true, warnings
+		}
+	}
+
+	var repoTargets []string
+	reason := "merged"
+	pinned := 0
+	for _, repoEntry := range ws.Repos {
+		if strings.TrimSpace(repoEntry.Ref) != "" {
+			// Pinned repos carry no branch work of their own; they neither block nor satisfy the merge rules.
+			pinned++
+			continue
+		}
+		if err := fetchErrors[repoEntry.RepoKey]; err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: %s: fetch failed: %w", id, repoEntryLabel(repoEntry), err))
+			return "", nil, false, warnings
+		}
+		target, ok, err := resolveMergeTarget(ctx, rootDir, repoEntry, defaultTargets)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: %s: resolve merge target: %w", id, repoEntryLabel(repoEntry), err))
+			return "", nil, false, warnings
+		}
+		if !ok {
+			warnings = append(warnings, fmt.Errorf("%s: %s: merge target unavailable", id, repoEntryLabel(repoEntry)))
+			return "", nil, false, warnings
+		}
+		repoTargets = append(repoTargets, fmt.Sprintf("%s=%s", repoEntryLabel(repoEntry), target))
+
+		merged, err := strictMergedIntoTarget(ctx, rootDir, repoEntry, target)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: %s: merged check failed: %w", id, repoEntryLabel(repoEntry), err))
+			return "", nil, false, warnings
+		}
+		if merged {
+			continue
+		}
+		patchReason, ok, err := patchMergedIntoTarget(ctx, rootDir, repoEntry, target)
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("%s: %s: patch merge check failed: %w", id, repoEntryLabel(repoEntry), err))
+			return "", nil, false, warnings
+		}
+		if !ok {
+			return "", nil, false, warnings
+		}
+		reason = patchReason
+	}
+	if pinned > 0 && pinned == len(ws.Repos) {
+		return "", nil, false, warnings
+	}
+	return reason, repoTargets, true, warnings
+}
+
+// manifestGcProviderReason reports whether the PR/issue a review or issue workspace
+// was created from is finished on the provider side (PR merged/closed, issue closed).
+// Workspaces without a source URL, or whose item is still open, return ok=false.
+func manifestGcProviderReason(ctx context.Context, ws manifest.Workspace) (string, bool, error) {
+	sourceURL := strings.TrimSpace(ws.SourceURL)
+	if sourceURL == "" {
+		return "", false, nil
+	}
+	switch strings.TrimSpace(ws.Mode) {
+	case workspace.MetadataModeReview:
+		req, err := parsePRURL(sourceURL)
+		if err != nil {
+			return "", false, err
+		}
+		prov, err := providerByName(req.Provider)
+		if err != nil {
+			return "", false, err
+		}
+		pr, err := prov.FetchPR(ctx, req.Host, req.Owner, req.Repo, req.Number)
+		if err != nil {
+			return "", false, err
+		}
+		if pr.Merged {
+			return "pr merged", true, nil
+		}
+		if pr.State == "closed" {
+			return "pr closed", true, nil
+		}
+	case workspace.MetadataModeIssue:
+		req, err := parseIssueURL(sourceURL)
+		if err != nil {
+			return "", false, err
+		}
+		prov, err := providerByName(req.Provider)
+		if err != nil {
+			return "", false, err
+		}
+		issue, err := prov.FetchIssue(ctx, req.Host, req.Owner, req.Repo, req.Number)
+		if err != nil {
+			return "", false, err
+		}
+		if issue.State == "closed" {
+			return "issue closed", true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func renderManifestGcInfo(r *ui.Renderer, policyName string, candidates, kept []manifestGcCandidate, warningLines []string) {
 	if r == nil {
 		return
 	}
+	if policyName != "" {
+		r.Bullet(r.AccentText("policy:") + " " + policyName)
+	}
 	if len(warningLines) > 0 {
 		renderManifestGcWarnings(r, warningLines)
 	}
 
 	if len(candidates) > 0 {
-		lines := make([]string, 0, len(candidates))
-		for _, c := range candidates {
-			reason := strings.TrimSpace(c.Reason)
-			if reason == "" {
-				reason = "unknown"
-			}
-			line := fmt.Sprintf("%s %s", c.WorkspaceID, r.MutedText(fmt.Sprintf("[%s]", reason)))
-			lines = append(lines, line)
-		}
 		r.Bullet(fmt.Sprintf("%s %d", r.WarnText("candidates:"), len(candidates)))
-		renderTreeLines(r, lines, treeLineNormal)
+		renderTreeLines(r, manifestGcCandidateLines(r, candidates), treeLineNormal)
+	}
+	if len(kept) > 0 {
+		r.Bullet(fmt.Sprintf("kept: %d", len(kept)))
+		renderTreeLines(r, manifestGcCandidateLines(r, kept), treeLineNormal)
+	}
+}
+
+// renderManifestGcArchives lists the archives written for dirty candidates.
+func renderManifestGcArchives(r *ui.Renderer, archives []string) {
+	if r == nil || len(archives) == 0 {
+		return
+	}
+	r.Bullet(fmt.Sprintf("archived: %d", len(archives)))
+	renderTreeLines(r, archives, treeLineNormal)
+}
+
+func manifestGcCandidateLines(r *ui.Renderer, candidates []manifestGcCandidate) []string {
+	lines := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		line := c.WorkspaceID
+		reason := strings.TrimSpace(c.Reason)
+		if reason == "" && c.Rule == "" {
+			reason = "unknown"
+		}
+		if reason != "" {
+			line += " " + r.MutedText(fmt.Sprintf("[%s]", reason))
+		}
+		if c.Rule != "" {
+			line += " " + r.MutedText(fmt.Sprintf("(rule: %s)", c.Rule))
+		}
+		if c.Archive != "" {
+			line += " " + r.WarnText(fmt.Sprintf("(archive: %s)", c.Archive))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func manifestGcPolicyNames(gc manifest.GC) string {
+	if len(gc.Policies) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(gc.Policies))
+	for name := range gc.Policies {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
 }
 
 func renderManifestGcWarnings(r *ui.Renderer, warningLines []string) {
@@ -411,6 +678,77 @@ func strictMergedIntoTarget(ctx context.Context, rootDir string, entry manifest.
 	})
 }
 
+// patchMergedIntoTarget detects branches whose changes landed on target without
+// being ancestors of it: rebase merges (every branch commit has a patch-equivalent
+// commit on target) and squash merges (the combined branch diff matches a single
+// commit on target). Branches without commits of their own never match.
+func patchMergedIntoTarget(ctx context.Context, rootDir string, entry manifest.Repo, target string) (string, bool, error) {
+	storePath, exists, err := repo.Exists(rootDir, repo.SpecFromKey(entry.RepoKey))
+	if err != nil {
+		return "", false, err
+	}
+	if !exists {
+		return "", false, fmt.Errorf("repo store not found (run: gion repo get %s)", repo.SpecFromKey(entry.RepoKey))
+	}
+	branch := strings.TrimSpace(entry.Branch)
+	if branch == "" {
+		return "", false, nil
+	}
+	branchRef := "refs/heads/" + branch
+	head, ok, err := gitcmd.ShowRef(ctx, storePath, branchRef)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	base, err := gitcmd.MergeBase(ctx, storePath, target, branchRef)
+	if err != nil {
+		return "", false, err
+	}
+	if base == head {
+		return "", false, nil
+	}
+
+	commits, err := gitcmd.Cherry(ctx, storePath, target, branchRef)
+	if err != nil {
+		return "", false, err
+	}
+	if len(commits) > 0 && allCherryEquivalent(commits) {
+		return "merged (rebase)", true, nil
+	}
+
+	tree, err := gitcmd.RevParse(ctx, storePath, branchRef+"^{tree}")
+	if err != nil {
+		return "", false, err
+	}
+	baseTree, err := gitcmd.RevParse(ctx, storePath, base+"^{tree}")
+	if err != nil {
+		return "", false, err
+	}
+	if tree == baseTree {
+		return "", false, nil
+	}
+	squashed, err := gitcmd.CommitTree(ctx, storePath, tree, base, "gion gc squash check")
+	if err != nil {
+		return "", false, err
+	}
+	commits, err = gitcmd.Cherry(ctx, storePath, target, squashed)
+	if err != nil {
+		return "", false, err
+	}
+	if len(commits) == 1 && commits[0].Equivalent {
+		return "merged (squash)", true, nil
+	}
+	return "", false, nil
+}
+
+func allCherryEquivalent(commits []gitcmd.CherryCommit) bool {
+	for _, c := range commits {
+		if !c.Equivalent {
+			return false
+		}
+	}
+	return true
+}
+
 type manifestGcMergeChecker struct{}
 
 func (manifestGcMergeChecker) ShowRef(ctx context.Context, storePath, ref string) (string, bool, error) {
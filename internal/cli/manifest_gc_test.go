@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+type gcStubProvider struct {
+	githubProvider
+	pr    prSummary
+	issue issueSummary
+}
+
+func (p gcStubProvider) FetchPR(context.Context, string, string, string, int) (prSummary, error) {
+	return p.pr, nil
+}
+
+func (p gcStubProvider) FetchIssue(context.Context, string, string, string, int) (issueSummary, error) {
+	return p.issue, nil
+}
+
+func TestManifestGcProviderReason(t *testing.T) {
+	cases := []struct {
+		name       string
+		ws         manifest.Workspace
+		stub       gcStubProvider
+		wantReason string
+		wantOK     bool
+	}{
+		{
+			name:       "merged pr",
+			ws:         manifest.Workspace{Mode: workspace.MetadataModeReview, SourceURL: "https://github.com/org/app/pull/1"},
+			stub:       gcStubProvider{pr: prSummary{Number: 1, State: "closed", Merged: true}},
+			wantReason: "pr merged",
+			wantOK:     true,
+		},
+		{
+			name:       "closed pr",
+			ws:         manifest.Workspace{Mode: workspace.MetadataModeReview, SourceURL: "https://github.com/org/app/pull/1"},
+			stub:       gcStubProvider{pr: prSummary{Number: 1, State: "closed"}},
+			wantReason: "pr closed",
+			wantOK:     true,
+		},
+		{
+			name: "open pr",
+			ws:   manifest.Workspace{Mode: workspace.MetadataModeReview, SourceURL: "https://github.com/org/app/pull/1"},
+			stub: gcStubProvider{pr: prSummary{Number: 1, State: "open"}},
+		},
+		{
+			name:       "closed issue",
+			ws:         manifest.Workspace{Mode: workspace.MetadataModeIssue, SourceURL: "https://github.com/org/app/issues/7"},
+			stub:       gcStubProvider{issue: issueSummary{Number: 7, State: "closed"}},
+			wantReason: "issue closed",
+			wantOK:     true,
+		},
+		{
+			name: "repo mode is ignored",
+			ws:   manifest.Workspace{Mode: workspace.MetadataModeRepo, SourceURL: "https://github.com/org/app/pull/1"},
+			stub: gcStubProvider{pr: prSummary{Number: 1, State: "closed", Merged: true}},
+		},
+		{
+			name: "missing source url",
+			ws:   manifest.Workspace{Mode: workspace.MetadataModeReview},
+			stub: gcStubProvider{pr: prSummary{Number: 1, State: "closed", Merged: true}},
+		},
+	}
+	original := providers["github"]
+	t.Cleanup(func() { providers["github"] = original })
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			providers["github"] = tc.stub
+			reason, ok, err := manifestGcProviderReason(context.Background(), tc.ws)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tc.wantOK || reason != tc.wantReason {
+				t.Fatalf("got (%q, %v), want (%q, %v)", reason, ok, tc.wantReason, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizeGitHubPR_State(t *testing.T) {
+	var item githubPRItem
+	item.Number = 3
+	item.State = "closed"
+	item.MergedAt = "2026-01-02T03:04:05Z"
+	pr := normalizeGitHubPR(item)
+	if pr.State != "closed" || !pr.Merged {
+		t.Fatalf("unexpected pr: %+v", pr)
+	}
+	item.MergedAt = ""
+	if normalizeGitHubPR(item).Merged {
+		t.Fatalf("expected unmerged when merged_at is empty")
+	}
+}
+
+func TestPatchMergedIntoTarget(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	seedDir := filepath.Join(tmp, "seed")
+
+	writeCommit := func(branch, name, msg string) {
+		t.Helper()
+		runGit(t, seedDir, "checkout", branch)
+		if err := os.WriteFile(filepath.Join(seedDir, name), []byte(msg+"\n"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		runGit(t, seedDir, "add", ".")
+		runGit(t, seedDir, "commit", "-m", msg)
+	}
+
+	runGit(t, seedDir, "branch", "squashed", "main")
+	writeCommit("squashed", "a.txt", "squash a")
+	writeCommit("squashed", "b.txt", "squash b")
+	runGit(t, seedDir, "branch", "rebased", "main")
+	writeCommit("rebased", "c.txt", "rebase c")
+	runGit(t, seedDir, "branch", "open", "main")
+	writeCommit("open", "d.txt", "open d")
+	runGit(t, seedDir, "branch", "empty", "main")
+
+	runGit(t, seedDir, "checkout", "main")
+	writeCommit("main", "other.txt", "unrelated")
+	runGit(t, seedDir, "merge", "--squash", "squashed")
+	runGit(t, seedDir, "commit", "-m", "squash merge")
+	runGit(t, seedDir, "cherry-pick", "rebased")
+	runGit(t, seedDir, "push", "origin", "main", "squashed", "rebased", "open", "empty")
+
+	store, err := repo.Get(ctx, rootDir, repoSpec)
+	if err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	// Workspace branches live as local heads in the bare store; remote-tracking refs come from fetch.
+	runGit(t, store.StorePath, "fetch", "origin")
+	for _, branch := range []string{"squashed", "rebased", "open", "empty"} {
+		runGit(t, store.StorePath, "branch", "-f", branch, "refs/remotes/origin/"+branch)
+	}
+
+	cases := []struct {
+		branch     string
+		wantReason string
+		wantOK     bool
+	}{
+		{branch: "squashed", wantReason: "merged (squash)", wantOK: true},
+		{branch: "rebased", wantReason: "merged (rebase)", wantOK: true},
+		{branch: "open"},
+		{branch: "empty"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.branch, func(t *testing.T) {
+			entry := manifest.Repo{Alias: "repo", RepoKey: "example.com/org/repo", Branch: tc.branch}
+			reason, ok, err := patchMergedIntoTarget(ctx, rootDir, entry, "origin/main")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tc.wantOK || reason != tc.wantReason {
+				t.Fatalf("got (%q, %v), want (%q, %v)", reason, ok, tc.wantReason, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestManifestGc_Expired(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+
+	past := manifest.FormatExpiresAt(time.Now().Add(-48 * time.Hour))
+	future := manifest.FormatExpiresAt(time.Now().Add(48 * time.Hour))
+	desired := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"WS-OLD":  {Mode: workspace.MetadataModeRepo, ExpiresAt: past},
+			"WS-NEW":  {Mode: workspace.MetadataModeRepo, ExpiresAt: future},
+			"WS-KEEP": {Mode: workspace.MetadataModeRepo},
+		},
+	}
+	if err := manifest.Save(rootDir, desired); err != nil {
+		t.Fatalf("manifest save: %v", err)
+	}
+	if err := runApply(ctx, rootDir, nil, true); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "WS-OLD"))
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta.ExpiresAt != past {
+		t.Fatalf("expected expires_at in metadata, got %+v", meta)
+	}
+	rebuilt, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	if rebuilt.Workspaces["WS-OLD"].ExpiresAt != past {
+		t.Fatalf("expected expires_at to round-trip, got %+v", rebuilt.Workspaces["WS-OLD"])
+	}
+
+	if err := runManifestGc(ctx, rootDir, []string{"--policy", "x", "--expired"}, true); err == nil {
+		t.Fatalf("expected --expired with --policy to fail")
+	}
+	if err := runManifestGc(ctx, rootDir, []string{"--expired", "--no-apply"}, true); err != nil {
+		t.Fatalf("gc: %v", err)
+	}
+	after, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("manifest load: %v", err)
+	}
+	if _, ok := after.Workspaces["WS-OLD"]; ok {
+		t.Fatalf("expected expired workspace to be removed from %s", manifest.FileName)
+	}
+	if _, ok := after.Workspaces["WS-NEW"]; !ok {
+		t.Fatalf("expected unexpired workspace to stay")
+	}
+	if _, ok := after.Workspaces["WS-KEEP"]; !ok {
+		t.Fatalf("expected workspace without expiry to stay")
+	}
+}
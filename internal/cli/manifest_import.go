@@ -4,9 +4,35 @@ import (
 	"context"
 
 	"github.com/tasuku43/gion/internal/app/manifestimport"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
 )
 
 func rebuildManifest(ctx context.Context, rootDir string) error {
 	_, err := manifestimport.Import(ctx, rootDir)
 	return err
 }
+
+// rebuildManifestAfterApply rewrites gion.yaml from the filesystem. When the plan was
+// narrowed by labels, workspaces outside the filter keep their gion.yaml entries so
+// their pending changes are not lost.
+func rebuildManifestAfterApply(ctx context.Context, rootDir string, plan manifestplan.Result) error {
+	if len(plan.LabelFilter) == 0 {
+		return rebuildManifest(ctx, rootDir)
+	}
+	file, warnings, err := manifestimport.Build(ctx, rootDir)
+	if err != nil {
+		return err
+	}
+	for id := range file.Workspaces {
+		if !plan.InScope(id) {
+			delete(file.Workspaces, id)
+		}
+	}
+	for id, ws := range plan.Desired.Workspaces {
+		if !plan.InScope(id) {
+			file.Workspaces[id] = ws
+		}
+	}
+	_, err = manifestimport.Write(rootDir, file, warnings)
+	return err
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runManifestMigrate(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	migrateFlags := flag.NewFlagSet("manifest migrate", flag.ContinueOnError)
+	migrateFlags.SetOutput(os.Stdout)
+	var helpFlag bool
+	var dryRun bool
+	var noPromptFlag bool
+	migrateFlags.BoolVar(&dryRun, "dry-run", false, "show the migration without changing gion.yaml")
+	migrateFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	migrateFlags.BoolVar(&helpFlag, "help", false, "show help")
+	migrateFlags.BoolVar(&helpFlag, "h", false, "show help")
+	migrateFlags.Usage = func() {
+		printManifestMigrateHelp(os.Stdout)
+	}
+	if err := migrateFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printManifestMigrateHelp(os.Stdout)
+		return nil
+	}
+	if migrateFlags.NArg() != 0 {
+		return fmt.Errorf("usage: gion manifest migrate [--dry-run] [--no-prompt]")
+	}
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+
+	// Migrating a broken file would silently rewrite (or drop) the broken parts.
+	validation, err := manifest.Validate(ctx, rootDir)
+	if err != nil {
+		return err
+	}
+	if len(validation.Issues) > 0 {
+		renderManifestValidationResult(renderer, validation)
+		return fmt.Errorf("manifest validation failed (fix issues before migrating)")
+	}
+
+	migrations, err := manifest.MigrateAll(rootDir)
+	if err != nil {
+		return err
+	}
+	var changed []manifest.FileMigration
+	for _, migration := range migrations {
+		if migration.Changed() {
+			changed = append(changed, migration)
+		}
+	}
+	if len(changed) == 0 {
+		renderer.Section("Result")
+		renderer.Bullet(fmt.Sprintf("%s is already at version %d", manifest.FileName, manifest.CurrentVersion))
+		return nil
+	}
+
+	var warningLines []string
+	for _, migration := range changed {
+		for _, dropped := range migration.Dropped {
+			warningLines = append(warningLines, fmt.Sprintf("%s: %s: dropped (unknown field)", migration.Name, dropped.Ref))
+		}
+	}
+	if len(warningLines) > 0 {
+		renderWarningsSection(renderer, "warnings", warningLines, false)
+		renderer.Blank()
+	}
+	renderer.Section("Plan")
+	for _, migration := range changed {
+		diffLines, err := buildUnifiedDiffLines(migration.Original, migration.Data)
+		if err != nil {
+			return err
+		}
+		renderer.Bullet(fmt.Sprintf("migrate %s: version %d -> %d", migration.Name, migration.FromVersion, migration.ToVersion))
+		renderDiffLines(renderer, diffLines, "")
+	}
+
+	if dryRun {
+		renderer.Blank()
+		renderer.Section("Result")
+		renderer.Bullet("dry run: no files modified")
+		return nil
+	}
+	for _, migration := range changed {
+		backupPath := migration.BackupPath()
+		if _, err := os.Stat(backupPath); err == nil {
+			return fmt.Errorf("backup already exists: %s (move it away and retry)", backupPath)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if !noPrompt {
+		renderer.Blank()
+		confirm, err := ui.PromptConfirmInlinePlan("Migrate gion.yaml? (default: No)", theme, useColor)
+		if err != nil {
+			if errors.Is(err, ui.ErrPromptCanceled) {
+				return nil
+			}
+			return err
+		}
+		if !confirm {
+			return nil
+		}
+	}
+
+	for _, migration := range changed {
+		if err := os.WriteFile(migration.BackupPath(), migration.Original, 0o600); err != nil {
+			return fmt.Errorf("write backup: %w", err)
+		}
+	}
+	var written []manifest.FileMigration
+	for _, migration := range changed {
+		if err := os.WriteFile(migration.Path, migration.Data, 0o600); err != nil {
+			return errors.Join(fmt.Errorf("write %s: %w", migration.Name, err), restoreMigrated(written))
+		}
+		written = append(written, migration)
+	}
+	validation, err = manifest.Validate(ctx, rootDir)
+	if err != nil {
+		return err
+	}
+	if len(validation.Issues) > 0 {
+		if err := restoreMigrated(written); err != nil {
+			return err
+		}
+		renderer.Blank()
+		renderManifestValidationResult(renderer, validation)
+		return fmt.Errorf("migrated manifest failed validation (original files restored)")
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	for _, migration := range changed {
+		renderer.Bullet(fmt.Sprintf("migrated %s to version %d", migration.Name, migration.ToVersion))
+		renderer.Bullet(fmt.Sprintf("backup: %s", migration.BackupPath()))
+	}
+	return nil
+}
+
+// restoreMigrated writes back the original contents of migrated files.
+func restoreMigrated(migrations []manifest.FileMigration) error {
+	var errs []error
+	for _, migration := range migrations {
+		if err := os.WriteFile(migration.Path, migration.Original, 0o600); err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", migration.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
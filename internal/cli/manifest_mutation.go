@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -20,10 +21,14 @@ type manifestMutationHooks struct {
 }
 
 type manifestMutationOptions struct {
-	NoApply       bool
-	NoPrompt      bool
-	OriginalBytes []byte
-	Hooks         manifestMutationHooks
+	NoApply  bool
+	NoPrompt bool
+	Original manifest.Snapshot
+	// Rollback undoes side effects of the command made before the mutation (such
+	// as archives it wrote). It runs whenever gion.yaml is restored: apply was
+	// declined or canceled, or failed before changing anything.
+	Rollback func() error
+	Hooks    manifestMutationHooks
 }
 
 func applyManifestMutation(ctx context.Context, rootDir string, updated manifest.File, opts manifestMutationOptions) error {
@@ -54,7 +59,7 @@ func applyManifestMutation(ctx context.Context, rootDir string, updated manifest
 	if !planOK {
 		return planErr
 	}
-	if planOK && len(plan.Changes) == 0 {
+	if planOK && !plan.HasChanges() {
 		if opts.Hooks.ShowPrelude != nil {
 			renderer.Blank()
 		}
@@ -74,11 +79,19 @@ func applyManifestMutation(ctx context.Context, rootDir string, updated manifest
 
 	res, err := runApplyInternalWithPlan(ctx, rootDir, renderer, opts.NoPrompt, plan)
 	if err != nil {
+		// Without confirmation nothing was applied (e.g. destructive changes with
+		// --no-prompt), so the edit is undone. Once apply has started, gion.yaml is
+		// kept: it describes what the partial apply was working towards.
+		if !res.Confirmed {
+			if restoreErr := restoreManifestMutation(opts); restoreErr != nil {
+				return errors.Join(err, restoreErr)
+			}
+		}
 		return err
 	}
 	if res.Canceled || (res.HadChanges && !res.Confirmed) {
-		if err := os.WriteFile(manifest.Path(rootDir), opts.OriginalBytes, 0o600); err != nil {
-			return fmt.Errorf("restore %s: %w", manifest.FileName, err)
+		if err := restoreManifestMutation(opts); err != nil {
+			return err
 		}
 		renderer.Blank()
 		renderer.Section("Result")
@@ -92,6 +105,17 @@ func applyManifestMutation(ctx context.Context, rootDir string, updated manifest
 	return nil
 }
 
+// restoreManifestMutation puts gion.yaml back and undoes the command's side effects.
+func restoreManifestMutation(opts manifestMutationOptions) error {
+	if err := opts.Original.Restore(); err != nil {
+		return fmt.Errorf("restore %s: %w", manifest.FileName, err)
+	}
+	if opts.Rollback != nil {
+		return opts.Rollback()
+	}
+	return nil
+}
+
 func planIncludesChangesOutsideWorkspaceIDs(plan manifestplan.Result, workspaceIDs []string) bool {
 	inScope := map[string]struct{}{}
 	for _, id := range workspaceIDs {
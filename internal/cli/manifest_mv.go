@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runManifestMv(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	mvFlags := flag.NewFlagSet("manifest mv", flag.ContinueOnError)
+	var renameBranches bool
+	var noApply bool
+	var noPromptFlag bool
+	var helpFlag bool
+	mvFlags.BoolVar(&renameBranches, "rename-branches", false, "rename branches derived from the old workspace id")
+	mvFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
+	mvFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	mvFlags.BoolVar(&helpFlag, "help", false, "show help")
+	mvFlags.BoolVar(&helpFlag, "h", false, "show help")
+	mvFlags.SetOutput(os.Stdout)
+	mvFlags.Usage = func() {
+		printManifestMvHelp(os.Stdout)
+	}
+	if err := mvFlags.Parse(normalizeArgsFlagsFirst(args, nil)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printManifestMvHelp(os.Stdout)
+		return nil
+	}
+	if mvFlags.NArg() != 2 {
+		return fmt.Errorf("usage: gion manifest mv <OLD_ID> <NEW_ID> [--rename-branches] [--no-apply] [--no-prompt]")
+	}
+	oldID := strings.TrimSpace(mvFlags.Arg(0))
+	newID := strings.TrimSpace(mvFlags.Arg(1))
+	if err := workspace.ValidateWorkspaceID(ctx, newID); err != nil {
+		return err
+	}
+
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	original, err := manifest.TakeSnapshot(rootDir)
+	if err != nil {
+		return err
+	}
+
+	updated := desired
+	updated.Workspaces = make(map[string]manifest.Workspace, len(desired.Workspaces))
+	for id, ws := range desired.Workspaces {
+		updated.Workspaces[id] = ws
+	}
+	if err := updated.Rename(oldID, newID); err != nil {
+		return err
+	}
+	var renamed []string
+	if renameBranches {
+		renamed = renameWorkspaceBranches(&updated, newID, oldID)
+	}
+
+	summary := fmt.Sprintf("renamed %s -> %s", oldID, newID)
+	if len(renamed) > 0 {
+		summary += fmt.Sprintf(", %d branch(es)", len(renamed))
+	}
+
+	return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
+		NoApply:  noApply,
+		NoPrompt: noPrompt,
+		Original: original,
+		Hooks: manifestMutationHooks{
+			ShowPrelude: func(r *ui.Renderer) {
+				r.Section("Inputs")
+				r.Bullet(fmt.Sprintf("workspace: %s -> %s", oldID, newID))
+				for _, line := range renamed {
+					r.Bullet(fmt.Sprintf("branch: %s", line))
+				}
+			},
+			RenderNoApply: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("updated %s (%s)", manifest.FileName, summary))
+				r.Blank()
+				r.Section("Suggestion")
+				r.Bullet("gion apply")
+			},
+			RenderNoChanges: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("updated %s (%s)", manifest.FileName, summary))
+				r.Bullet("no changes")
+			},
+			RenderInfoBeforeApply: func(r *ui.Renderer, _ manifestplan.Result, _ bool) {
+				r.Section("Info")
+				r.Bullet(fmt.Sprintf("manifest: updated %s (%s)", manifest.FileName, summary))
+				r.Bullet("apply: reconciling entire root (destructive removals require confirmation)")
+			},
+		},
+	})
+}
+
+// renameWorkspaceBranches replaces oldID with workspaceID in the branch names of
+// the workspace's repos (e.g. feature/OLD -> feature/NEW, see renameBranchID).
+// Review workspaces and pinned repos are left alone. It returns
+// "alias: from -> to" for each rename.
+func renameWorkspaceBranches(file *manifest.File, workspaceID, oldID string) []string {
+	ws := file.Workspaces[workspaceID]
+	if strings.EqualFold(strings.TrimSpace(ws.Mode), workspace.MetadataModeReview) {
+		return nil
+	}
+	var renamed []string
+	repos := make([]manifest.Repo, len(ws.Repos))
+	for i, repoEntry := range ws.Repos {
+		branch := strings.TrimSpace(repoEntry.Branch)
+		if branch != "" && strings.TrimSpace(repoEntry.Ref) == "" {
+			if to, ok := renameBranchID(branch, oldID, workspaceID); ok {
+				repoEntry.Branch = to
+				renamed = append(renamed, fmt.Sprintf("%s: %s -> %s", repoEntry.Alias, branch, repoEntry.Branch))
+			}
+		}
+		repos[i] = repoEntry
+	}
+	ws.Repos = repos
+	file.Workspaces[workspaceID] = ws
+	return renamed
+}
+
+// renameBranchID replaces oldID with newID in each "/"-separated segment of branch
+// that is oldID or starts with it followed by a character other than a letter or
+// digit (feature/PROJ-1-login -> feature/PROJ-2-login). Other occurrences are
+// kept: renaming api to web turns feature/api-apis into feature/web-apis and
+// leaves feature/apis alone.
+func renameBranchID(branch, oldID, newID string) (string, bool) {
+	if oldID == "" {
+		return branch, false
+	}
+	segments := strings.Split(branch, "/")
+	changed := false
+	for i, segment := range segments {
+		rest, ok := strings.CutPrefix(segment, oldID)
+		if !ok {
+			continue
+		}
+		if rest != "" {
+			r, _ := utf8.DecodeRuneInString(rest)
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				continue
+			}
+		}
+		segments[i] = newID + rest
+		changed = true
+	}
+	if !changed {
+		return branch, false
+	}
+	return strings.Join(segments, "/"), true
+}
@@ -0,0 +1,30 @@
+package cli
+
+import "testing"
+
+func TestRenameBranchID(t *testing.T) {
+	cases := []struct {
+		name   string
+		branch string
+		oldID  string
+		newID  string
+		want   string
+		wantOK bool
+	}{
+		{name: "whole_branch", branch: "PROJ-1", oldID: "PROJ-1", newID: "PROJ-2", want: "PROJ-2", wantOK: true},
+		{name: "whole_segment", branch: "feature/PROJ-1", oldID: "PROJ-1", newID: "PROJ-2", want: "feature/PROJ-2", wantOK: true},
+		{name: "segment_prefix", branch: "feature/PROJ-1-login", oldID: "PROJ-1", newID: "PROJ-2", want: "feature/PROJ-2-login", wantOK: true},
+		{name: "substring_kept", branch: "feature/api-apis", oldID: "api", newID: "web", want: "feature/web-apis", wantOK: true},
+		{name: "longer_id_kept", branch: "feature/PROJ-10", oldID: "PROJ-1", newID: "PROJ-2", want: "feature/PROJ-10", wantOK: false},
+		{name: "word_kept", branch: "feature/apis", oldID: "api", newID: "web", want: "feature/apis", wantOK: false},
+		{name: "suffix_kept", branch: "fix-api", oldID: "api", newID: "web", want: "fix-api", wantOK: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := renameBranchID(tc.branch, tc.oldID, tc.newID)
+			if got != tc.want || ok != tc.wantOK {
+				t.Fatalf("renameBranchID(%q, %q, %q) = %q, %v; want %q, %v", tc.branch, tc.oldID, tc.newID, got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
@@ -30,6 +30,10 @@ func runManifestPreset(ctx context.Context, rootDir string, args []string, noPro
 		return runManifestPresetRemove(ctx, rootDir, args[1:], noPrompt)
 	case "validate":
 		return runManifestPresetValidate(ctx, rootDir, args[1:])
+	case "subscribe":
+		return runManifestPresetSubscribe(ctx, rootDir, args[1:])
+	case "sync":
+		return runManifestPresetSync(ctx, rootDir, args[1:])
 	default:
 		return fmt.Errorf("unknown manifest preset subcommand: %s", args[0])
 	}
@@ -71,9 +75,12 @@ func runManifestPresetList(ctx context.Context, rootDir string, args []string) e
 	useColor := isatty.IsTerminal(os.Stdout.Fd())
 	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
 
-	if len(names) > 0 {
+	if len(names) > 0 || len(file.PresetCatalogs) > 0 {
 		renderer.Section("Info")
 		renderer.Bullet(fmt.Sprintf("presets: %d", len(names)))
+		if len(file.PresetCatalogs) > 0 {
+			renderer.Bullet(fmt.Sprintf("catalogs: %d", len(file.PresetCatalogs)))
+		}
 		renderer.Blank()
 	}
 
@@ -88,10 +95,34 @@ func runManifestPresetList(ctx context.Context, rootDir string, args []string) e
 		if !ok {
 			continue
 		}
-		renderer.Bullet(name)
+		var notes []string
+		if len(entry.Extends) > 0 {
+			notes = append(notes, fmt.Sprintf("extends: %s", strings.Join(entry.Extends, ", ")))
+		}
+		if catalogRepo, ok := file.PresetCatalog(name); ok {
+			notes = append(notes, fmt.Sprintf("read-only, catalog: %s", displayPresetRepo(catalogRepo)))
+		}
+		if len(notes) > 0 {
+			renderer.Bullet(fmt.Sprintf("%s %s", name, renderer.MutedText(fmt.Sprintf("(%s)", strings.Join(notes, "; ")))))
+		} else {
+			renderer.Bullet(name)
+		}
+		expanded, err := manifest.ExpandPreset(file.Presets, name)
+		if err != nil {
+			renderTreeLines(renderer, []string{err.Error()}, treeLineError)
+			continue
+		}
 		var reposDisplay []string
-		for _, repoSpec := range entry.Repos {
-			reposDisplay = append(reposDisplay, displayPresetRepo(repoSpec))
+		for _, repoEntry := range expanded {
+			line := displayPresetRepo(repoEntry.Repo)
+			labels := presetRepoDefaultsLabel(repoEntry.PresetRepo)
+			if repoEntry.From != name {
+				labels = strings.TrimPrefix(labels+", from: "+repoEntry.From, ", ")
+			}
+			if labels != "" {
+				line += " " + renderer.MutedText(fmt.Sprintf("(%s)", labels))
+			}
+			reposDisplay = append(reposDisplay, line)
 		}
 		renderTreeLines(renderer, reposDisplay, treeLineNormal)
 	}
@@ -99,6 +130,21 @@ func runManifestPresetList(ctx context.Context, rootDir string, args []string) e
 	return nil
 }
 
+// presetRepoDefaultsLabel describes the per-repo defaults of a preset entry.
+func presetRepoDefaultsLabel(entry preset.Repo) string {
+	var parts []string
+	if entry.Alias != "" {
+		parts = append(parts, fmt.Sprintf("alias: %s", entry.Alias))
+	}
+	if entry.BaseRef != "" {
+		parts = append(parts, fmt.Sprintf("base: %s", entry.BaseRef))
+	}
+	if entry.Branch != "" {
+		parts = append(parts, fmt.Sprintf("branch: %s", entry.Branch))
+	}
+	return strings.Join(parts, ", ")
+}
+
 func runManifestPresetAdd(ctx context.Context, rootDir string, args []string, noPrompt bool) error {
 	addFlags := flag.NewFlagSet("manifest preset add", flag.ContinueOnError)
 	var helpFlag bool
@@ -197,6 +243,9 @@ func runManifestPresetAdd(ctx context.Context, rootDir string, args []string, no
 		return err
 	}
 	if _, exists := file.Presets[name]; exists {
+		if catalogRepo, ok := file.PresetCatalog(name); ok {
+			return fmt.Errorf("preset already exists: %s (read-only, catalog: %s)", name, displayPresetRepo(catalogRepo))
+		}
 		return fmt.Errorf("preset already exists: %s", name)
 	}
 	if len(repoSpecs) == 0 {
@@ -216,7 +265,11 @@ func runManifestPresetAdd(ctx context.Context, rootDir string, args []string, no
 	if file.Presets == nil {
 		file.Presets = map[string]preset.Preset{}
 	}
-	file.Presets[name] = preset.Preset{Repos: repoSpecs}
+	var presetRepos []preset.Repo
+	for _, repoSpec := range repoSpecs {
+		presetRepos = append(presetRepos, preset.Repo{Repo: repoSpec})
+	}
+	file.Presets[name] = preset.Preset{Repos: presetRepos}
 
 	if err := preset.Save(rootDir, file); err != nil {
 		return err
@@ -282,14 +335,16 @@ func runManifestPresetRemove(ctx context.Context, rootDir string, args []string,
 		if noPrompt {
 			return fmt.Errorf("preset name is required with --no-prompt")
 		}
-		presets := preset.Names(file)
-		if len(presets) == 0 {
-			return fmt.Errorf("no presets found in %s", filepath.Join(rootDir, manifest.FileName))
-		}
 		var choices []ui.PromptChoice
-		for _, name := range presets {
+		for _, name := range preset.Names(file) {
+			if _, readOnly := file.PresetCatalog(name); readOnly {
+				continue
+			}
 			choices = append(choices, ui.PromptChoice{Label: name, Value: name})
 		}
+		if len(choices) == 0 {
+			return fmt.Errorf("no presets found in %s", filepath.Join(rootDir, manifest.FileName))
+		}
 		theme := ui.DefaultTheme()
 		useColor := isatty.IsTerminal(os.Stdout.Fd())
 		selected, err := ui.PromptMultiSelect("gion manifest preset rm", "preset", choices, theme, useColor)
@@ -307,6 +362,23 @@ func runManifestPresetRemove(ctx context.Context, rootDir string, args []string,
 		if _, exists := file.Presets[name]; !exists {
 			return fmt.Errorf("preset not found: %s", name)
 		}
+		if catalogRepo, ok := file.PresetCatalog(name); ok {
+			return fmt.Errorf("preset %s is read-only (provided by catalog %s; change it in the catalog repository)", name, displayPresetRepo(catalogRepo))
+		}
+	}
+	removing := map[string]struct{}{}
+	for _, name := range names {
+		removing[name] = struct{}{}
+	}
+	for _, other := range preset.Names(file) {
+		if _, ok := removing[other]; ok {
+			continue
+		}
+		for _, parent := range file.Presets[other].Extends {
+			if _, ok := removing[parent]; ok {
+				return fmt.Errorf("preset %s is extended by %s", parent, other)
+			}
+		}
 	}
 	for _, name := range names {
 		delete(file.Presets, name)
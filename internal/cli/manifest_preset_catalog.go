@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/preset"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runManifestPresetSubscribe(ctx context.Context, rootDir string, args []string) error {
+	subscribeFlags := flag.NewFlagSet("manifest preset subscribe", flag.ContinueOnError)
+	subscribeFlags.SetOutput(os.Stdout)
+	var helpFlag bool
+	var noPrompt bool
+	var ref string
+	var catalogPath string
+	subscribeFlags.StringVar(&ref, "ref", "", "branch, tag or commit to read")
+	subscribeFlags.StringVar(&catalogPath, "path", "", "presets file in the repository")
+	subscribeFlags.BoolVar(&noPrompt, "no-prompt", false, "disable interactive prompt (no effect)")
+	subscribeFlags.BoolVar(&helpFlag, "help", false, "show help")
+	subscribeFlags.BoolVar(&helpFlag, "h", false, "show help")
+	subscribeFlags.Usage = func() {
+		printManifestPresetSubscribeHelp(os.Stdout)
+	}
+	if err := subscribeFlags.Parse(normalizeArgsFlagsFirst(args, map[string]struct{}{"--ref": {}, "-ref": {}, "--path": {}, "-path": {}})); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	_ = noPrompt
+	if helpFlag {
+		printManifestPresetSubscribeHelp(os.Stdout)
+		return nil
+	}
+	if subscribeFlags.NArg() != 1 {
+		return fmt.Errorf("usage: gion manifest preset subscribe <repo> [--ref <ref>] [--path <file>]")
+	}
+
+	catalog := preset.Catalog{
+		Repo: strings.TrimSpace(subscribeFlags.Arg(0)),
+		Ref:  strings.TrimSpace(ref),
+		Path: strings.TrimSpace(catalogPath),
+	}
+	if err := manifest.ValidatePresetCatalog(catalog); err != nil {
+		return err
+	}
+	file, err := preset.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	if _, exists := preset.FindCatalog(file, catalog.Repo); exists {
+		return fmt.Errorf("preset catalog already subscribed: %s", displayPresetRepo(catalog.Repo))
+	}
+	update, err := preset.FetchCatalog(ctx, rootDir, file, catalog)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := manifest.TakeSnapshot(rootDir)
+	if err != nil {
+		return err
+	}
+	file.PresetCatalogs = append(file.PresetCatalogs, catalog)
+	if err := preset.Save(rootDir, file); err != nil {
+		return err
+	}
+	if err := preset.WriteCatalog(update); err != nil {
+		return errors.Join(err, snapshot.Restore())
+	}
+	if _, err := manifest.Load(rootDir); err != nil {
+		return errors.Join(err, preset.RevertCatalog(update), snapshot.Restore())
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	renderer.Section("Result")
+	renderer.Bullet(fmt.Sprintf("subscribed to %s (%s at %s)", displayPresetRepo(catalog.Repo), catalog.FilePath(), shortSHA(update.Commit)))
+	renderTreeLines(renderer, update.Added, treeLineNormal)
+	renderer.Bullet(fmt.Sprintf("updated %s", manifest.FileName))
+	return nil
+}
+
+func runManifestPresetSync(ctx context.Context, rootDir string, args []string) error {
+	syncFlags := flag.NewFlagSet("manifest preset sync", flag.ContinueOnError)
+	syncFlags.SetOutput(os.Stdout)
+	var helpFlag bool
+	var noPrompt bool
+	var dryRun bool
+	syncFlags.BoolVar(&dryRun, "dry-run", false, "show changes without updating synced presets")
+	syncFlags.BoolVar(&noPrompt, "no-prompt", false, "disable interactive prompt (no effect)")
+	syncFlags.BoolVar(&helpFlag, "help", false, "show help")
+	syncFlags.BoolVar(&helpFlag, "h", false, "show help")
+	syncFlags.Usage = func() {
+		printManifestPresetSyncHelp(os.Stdout)
+	}
+	if err := syncFlags.Parse(normalizeArgsFlagsFirst(args, nil)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	_ = noPrompt
+	if helpFlag {
+		printManifestPresetSyncHelp(os.Stdout)
+		return nil
+	}
+
+	file, err := preset.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	catalogs := file.PresetCatalogs
+	if syncFlags.NArg() > 0 {
+		catalogs = nil
+		for _, repoSpec := range uniqueStringsPreserve(syncFlags.Args()) {
+			catalog, ok := preset.FindCatalog(file, repoSpec)
+			if !ok {
+				return fmt.Errorf("preset catalog not subscribed: %s", repoSpec)
+			}
+			catalogs = append(catalogs, catalog)
+		}
+	}
+	if len(catalogs) == 0 {
+		return fmt.Errorf("no preset catalogs in %s (run: gion manifest preset subscribe <repo>)", manifest.FileName)
+	}
+
+	var updates []preset.CatalogUpdate
+	for _, catalog := range catalogs {
+		update, err := preset.FetchCatalog(ctx, rootDir, file, catalog)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, update)
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+	renderer.Section("Plan")
+	changed := 0
+	for _, update := range updates {
+		renderer.Bullet(fmt.Sprintf("%s (%s at %s)", displayPresetRepo(update.Catalog.Repo), update.Catalog.FilePath(), shortSHA(update.Commit)))
+		if !update.HasChanges() {
+			renderTreeLines(renderer, []string{"up to date"}, treeLineNormal)
+			continue
+		}
+		changed++
+		var lines []string
+		for _, name := range update.Added {
+			lines = append(lines, fmt.Sprintf("add %s", name))
+		}
+		for _, name := range update.Changed {
+			lines = append(lines, fmt.Sprintf("update %s", name))
+		}
+		for _, name := range update.Removed {
+			lines = append(lines, fmt.Sprintf("remove %s", name))
+		}
+		renderTreeLines(renderer, lines, treeLineNormal)
+		label := displayPresetRepo(update.Catalog.Repo)
+		diffLines, err := buildUnifiedDiffLinesFor(label+" (synced)", label+" (fetched)", update.Before, update.After)
+		if err != nil {
+			return err
+		}
+		renderDiffLines(renderer, diffLines, "")
+	}
+	renderer.Blank()
+	renderer.Section("Result")
+	if dryRun {
+		renderer.Bullet("dry run: synced presets not modified")
+		return nil
+	}
+
+	for i, update := range updates {
+		if err := preset.WriteCatalog(update); err != nil {
+			return errors.Join(err, revertCatalogs(updates[:i]))
+		}
+	}
+	// Catalogs are checked against local presets when fetched; a preset added to
+	// two catalogs at once only shows up once both are written.
+	if _, err := manifest.Load(rootDir); err != nil {
+		return errors.Join(err, revertCatalogs(updates))
+	}
+	if changed == 0 {
+		renderer.Bullet("presets are up to date")
+		return nil
+	}
+	renderer.Bullet(fmt.Sprintf("synced %d preset catalogs", changed))
+	return nil
+}
+
+func revertCatalogs(updates []preset.CatalogUpdate) error {
+	var errs []error
+	for _, update := range updates {
+		if err := preset.RevertCatalog(update); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
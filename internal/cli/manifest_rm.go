@@ -47,10 +47,9 @@ func runManifestRm(ctx context.Context, rootDir string, args []string, globalNoP
 		return err
 	}
 
-	manifestPath := manifest.Path(rootDir)
-	originalBytes, err := os.ReadFile(manifestPath)
+	original, err := manifest.TakeSnapshot(rootDir)
 	if err != nil {
-		return fmt.Errorf("read %s: %w", manifest.FileName, err)
+		return err
 	}
 
 	selectedIDs := uniqueNonEmptyStrings(rmFlags.Args())
@@ -121,9 +120,9 @@ func runManifestRm(ctx context.Context, rootDir string, args []string, globalNoP
 	}
 
 	return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
-		NoApply:       noApply,
-		NoPrompt:      noPrompt,
-		OriginalBytes: originalBytes,
+		NoApply:  noApply,
+		NoPrompt: noPrompt,
+		Original: original,
 		Hooks: manifestMutationHooks{
 			ShowPrelude: showPrelude,
 			RenderNoApply: func(r *ui.Renderer) {
@@ -186,6 +185,8 @@ func buildManifestRmWorkspaceChoices(ctx context.Context, rootDir string, desire
 			branch := strings.TrimSpace(repoEntry.Branch)
 			if branch != "" {
 				label = fmt.Sprintf("%s (branch: %s)", repoName, branch)
+			} else if ref := strings.TrimSpace(repoEntry.Ref); ref != "" {
+				label = fmt.Sprintf("%s (ref: %s)", repoName, ref)
 			}
 			var details []string
 			repoKey := strings.TrimSpace(repoEntry.RepoKey)
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func runManifestSchema(args []string) error {
+	schemaFlags := flag.NewFlagSet("manifest schema", flag.ContinueOnError)
+	schemaFlags.SetOutput(os.Stdout)
+	var helpFlag bool
+	var noPrompt bool
+	schemaFlags.BoolVar(&helpFlag, "help", false, "show help")
+	schemaFlags.BoolVar(&helpFlag, "h", false, "show help")
+	schemaFlags.BoolVar(&noPrompt, "no-prompt", false, "disable interactive prompt (no effect)")
+	schemaFlags.Usage = func() {
+		printManifestSchemaHelp(os.Stdout)
+	}
+	if err := schemaFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	_ = noPrompt
+	if helpFlag {
+		printManifestSchemaHelp(os.Stdout)
+		return nil
+	}
+	if schemaFlags.NArg() != 0 {
+		return fmt.Errorf("usage: gion manifest schema [--no-prompt]")
+	}
+
+	// Plain JSON (no sections) so the output can be redirected into a file.
+	data, err := manifest.SchemaJSON()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
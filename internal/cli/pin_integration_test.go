@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/manifestimport"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func TestApply_PinnedRefDetachedWorktree(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	// Tag the initial commit as a release, then move main past it.
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, seedDir, "tag", "v1.0.0")
+	tagCommit := runGit(t, seedDir, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(seedDir, "CHANGELOG.md"), []byte("next\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "next")
+	runGit(t, seedDir, "push", "origin", "main", "v1.0.0")
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+
+	worktreePath := workspace.WorktreePath(rootDir, "REPRO-1", "repo")
+	apply := func(ref, branch string) manifestplan.Result {
+		t.Helper()
+		desired := manifest.File{
+			Version: 1,
+			Workspaces: map[string]manifest.Workspace{
+				"REPRO-1": {
+					Mode:  workspace.MetadataModeRepo,
+					Repos: []manifest.Repo{{Alias: "repo", RepoKey: "example.com/org/repo", Ref: ref, Branch: branch}},
+				},
+			},
+		}
+		if err := manifest.Save(rootDir, desired); err != nil {
+			t.Fatalf("manifest save: %v", err)
+		}
+		plan, err := manifestplan.Plan(ctx, rootDir)
+		if err != nil {
+			t.Fatalf("plan: %v", err)
+		}
+		var buf bytes.Buffer
+		renderer := ui.NewRenderer(&buf, ui.DefaultTheme(), false)
+		got, err := runApplyInternalWithPlan(ctx, rootDir, renderer, true, plan)
+		if err != nil {
+			t.Fatalf("apply: %v\n%s", err, buf.String())
+		}
+		if !got.Applied {
+			t.Fatalf("expected applied, got %+v\n%s", got, buf.String())
+		}
+		// Keep origin in a repo_key-derivable form (see TestApply_BranchRenameInPlace_SucceedsWithDirtyWorktree).
+		runGit(t, worktreePath, "remote", "set-url", "origin", repoSpec)
+		return plan
+	}
+	assertDetachedAt := func(want string) {
+		t.Helper()
+		head, err := gitcmd.RevParse(ctx, worktreePath, "HEAD")
+		if err != nil {
+			t.Fatalf("rev-parse: %v", err)
+		}
+		if head != want {
+			t.Fatalf("HEAD = %s, want %s", head, want)
+		}
+		if _, ok, err := gitcmd.SymbolicRef(ctx, worktreePath, "HEAD"); err != nil || ok {
+			t.Fatalf("expected detached HEAD (ok=%v err=%v)", ok, err)
+		}
+	}
+
+	apply("v1.0.0", "")
+	assertDetachedAt(tagCommit)
+
+	state, err := workspace.State(ctx, rootDir, "REPRO-1")
+	if err != nil {
+		t.Fatalf("state: %v", err)
+	}
+	if state.Kind != workspace.WorkspaceStateClean {
+		t.Fatalf("pinned worktree state = %s, want clean (%+v)", state.Kind, state.Repos)
+	}
+
+	imported, _, err := manifestimport.Build(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if got := imported.Workspaces["REPRO-1"].Repos; len(got) != 1 || got[0].Ref != "v1.0.0" || got[0].Branch != "" {
+		t.Fatalf("imported repos = %+v, want ref v1.0.0", got)
+	}
+	plan, err := manifestplan.Plan(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	if plan.HasChanges() {
+		t.Fatalf("expected no changes after apply, got %+v", plan.Changes)
+	}
+
+	// Switching to a branch and back again happens in place.
+	apply("", "REPRO-1")
+	branch, err := gitcmd.RevParse(ctx, worktreePath, "--abbrev-ref", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+	if branch != "REPRO-1" {
+		t.Fatalf("branch = %q, want REPRO-1", branch)
+	}
+	meta, err := workspace.LoadMetadata(workspace.WorkspaceDir(rootDir, "REPRO-1"))
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if len(meta.Pins) != 0 {
+		t.Fatalf("expected pin to be cleared, got %+v", meta.Pins)
+	}
+
+	plan = apply(tagCommit, "")
+	if planHasDestructiveChanges(plan) {
+		t.Fatalf("expected switching to a pin to be non-destructive, got %+v", plan.Changes)
+	}
+	assertDetachedAt(tagCommit)
+}
@@ -3,12 +3,14 @@ package cli
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/mattn/go-isatty"
 	"github.com/tasuku43/gion/internal/app/manifestplan"
 	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
 	"github.com/tasuku43/gion/internal/ui"
 )
 
@@ -17,8 +19,25 @@ func runPlan(ctx context.Context, rootDir string, args []string) error {
 		printPlanHelp(os.Stdout)
 		return nil
 	}
-	if len(args) != 0 {
-		return fmt.Errorf("usage: gion plan")
+	planFlags := flag.NewFlagSet("plan", flag.ContinueOnError)
+	var labelFlags stringSliceFlag
+	planFlags.Var(&labelFlags, "label", "only plan workspaces with this label (repeatable)")
+	planFlags.SetOutput(os.Stdout)
+	planFlags.Usage = func() {
+		printPlanHelp(os.Stdout)
+	}
+	if err := planFlags.Parse(normalizeArgsFlagsFirst(args, map[string]struct{}{"--label": {}, "-label": {}})); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if planFlags.NArg() != 0 {
+		return fmt.Errorf("usage: gion plan [--label <label>]...")
+	}
+	labelFilter, err := workspace.ParseLabelFilter(labelFlags)
+	if err != nil {
+		return err
 	}
 
 	theme := ui.DefaultTheme()
@@ -34,6 +53,7 @@ func runPlan(ctx context.Context, rootDir string, args []string) error {
 		}
 		return err
 	}
+	result = manifestplan.FilterByLabels(result, labelFilter)
 
 	var warningLines []string
 	for _, warn := range result.Warnings {
@@ -45,7 +65,7 @@ func runPlan(ctx context.Context, rootDir string, args []string) error {
 	}
 
 	renderer.Section("Plan")
-	if len(result.Changes) == 0 {
+	if !result.HasChanges() {
 		renderer.Bullet("no changes")
 		return nil
 	}
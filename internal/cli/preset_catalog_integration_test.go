@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+)
+
+func TestManifestPreset_SubscribeAndSyncCatalog(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+	if err := os.MkdirAll(rootDir, 0o755); err != nil {
+		t.Fatalf("mkdir root: %v", err)
+	}
+	repoSpec, _ := setupLocalRemoteRepoExampleDotCom(t, tmp)
+	seedDir := filepath.Join(tmp, "seed")
+	publish := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(seedDir, "team", "presets.yaml"), []byte(content), 0o644); err != nil {
+			t.Fatalf("write catalog: %v", err)
+		}
+		runGit(t, seedDir, "add", ".")
+		runGit(t, seedDir, "commit", "-m", "update presets")
+		runGit(t, seedDir, "push", "origin", "main")
+	}
+	if err := os.MkdirAll(filepath.Join(seedDir, "team"), 0o755); err != nil {
+		t.Fatalf("mkdir catalog dir: %v", err)
+	}
+	publish("presets:\n  webapp:\n    repos: [git@github.com:org/api.git]\n")
+
+	if err := os.WriteFile(manifest.Path(rootDir), []byte("version: 2\npresets:\n  mine:\n    repos: [git@github.com:org/mine.git]\nworkspaces: {}\n"), 0o644); err != nil {
+		t.Fatalf("write %s: %v", manifest.FileName, err)
+	}
+	if err := runManifestPresetSubscribe(ctx, rootDir, []string{repoSpec, "--path", "team/presets.yaml"}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	file, err := manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if catalog, ok := file.PresetCatalog("webapp"); !ok || catalog != repoSpec {
+		t.Fatalf("expected webapp from catalog, got %q %v", catalog, ok)
+	}
+	if err := runManifestPresetRemove(ctx, rootDir, []string{"webapp"}, true); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Fatalf("expected read-only error, got %v", err)
+	}
+
+	cachePath, err := manifest.CatalogCachePath(rootDir, repoSpec)
+	if err != nil {
+		t.Fatalf("cache path: %v", err)
+	}
+	readCache := func() string {
+		t.Helper()
+		data, err := os.ReadFile(cachePath)
+		if err != nil {
+			t.Fatalf("read cache: %v", err)
+		}
+		return string(data)
+	}
+	synced := readCache()
+
+	publish("presets:\n  webapp:\n    repos: [git@github.com:org/api.git, git@github.com:org/web.git]\n  docs:\n    repos: [git@github.com:org/docs.git]\n")
+	if err := runManifestPresetSync(ctx, rootDir, []string{"--dry-run"}); err != nil {
+		t.Fatalf("sync --dry-run: %v", err)
+	}
+	if readCache() != synced {
+		t.Fatalf("dry run updated the catalog")
+	}
+	if err := runManifestPresetSync(ctx, rootDir, nil); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	file, err = manifest.Load(rootDir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, ok := file.PresetCatalog("docs"); !ok || len(file.Presets["webapp"].Repos) != 2 {
+		t.Fatalf("expected synced presets, got %+v", file.Presets)
+	}
+
+	// A catalog preset must not shadow a local one.
+	synced = readCache()
+	publish("presets:\n  mine:\n    repos: [git@github.com:org/api.git]\n")
+	if err := runManifestPresetSync(ctx, rootDir, nil); err == nil || !strings.Contains(err.Error(), "already defined in gion.yaml") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if readCache() != synced {
+		t.Fatalf("conflicting sync updated the catalog")
+	}
+}
@@ -12,6 +12,8 @@ type provider interface {
 	FetchIssue(ctx context.Context, host, owner, repoName string, number int) (issueSummary, error)
 	FetchPRs(ctx context.Context, host, owner, repoName string) ([]prSummary, error)
 	FetchPR(ctx context.Context, host, owner, repoName string, number int) (prSummary, error)
+	SearchIssues(ctx context.Context, host, query string) (searchResults, error)
+	SearchPRs(ctx context.Context, host, query string) (searchResults, error)
 }
 
 type githubProvider struct{}
@@ -36,6 +38,14 @@ func (githubProvider) FetchPR(ctx context.Context, host, owner, repoName string,
 	return fetchGitHubPR(ctx, host, owner, repoName, number)
 }
 
+func (githubProvider) SearchIssues(ctx context.Context, host, query string) (searchResults, error) {
+	return searchGitHub(ctx, host, query, false)
+}
+
+func (githubProvider) SearchPRs(ctx context.Context, host, query string) (searchResults, error) {
+	return searchGitHub(ctx, host, query, true)
+}
+
 var providers = map[string]provider{
 	"github": githubProvider{},
 }
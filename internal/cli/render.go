@@ -103,11 +103,15 @@ func renderTreeLines(r *ui.Renderer, lines []string, style treeLineStyle) {
 }
 
 func buildUnifiedDiffLines(current, next []byte) ([]string, error) {
+	return buildUnifiedDiffLinesFor(fmt.Sprintf("%s (current)", manifest.FileName), fmt.Sprintf("%s (target)", manifest.FileName), current, next)
+}
+
+func buildUnifiedDiffLinesFor(fromFile, toFile string, current, next []byte) ([]string, error) {
 	diff := difflib.UnifiedDiff{
 		A:        difflib.SplitLines(string(current)),
 		B:        difflib.SplitLines(string(next)),
-		FromFile: fmt.Sprintf("%s (current)", manifest.FileName),
-		ToFile:   fmt.Sprintf("%s (target)", manifest.FileName),
+		FromFile: fromFile,
+		ToFile:   toFile,
 		Context:  3,
 	}
 	text, err := difflib.GetUnifiedDiffString(diff)
@@ -148,10 +152,10 @@ func renderPlanRepoChanges(renderer *ui.Renderer, changes []manifestplan.RepoCha
 	for _, change := range changes {
 		switch change.Kind {
 		case manifestplan.RepoAdd:
-			lines = append(lines, fmt.Sprintf("+ add repo %s (%s) branch %s", change.Alias, change.ToRepo, change.ToBranch))
+			lines = append(lines, fmt.Sprintf("+ add repo %s (%s) %s", change.Alias, change.ToRepo, formatPlanBranch(change.ToBranch)))
 			styles = append(styles, treeLineSuccess)
 		case manifestplan.RepoRemove:
-			lines = append(lines, fmt.Sprintf("- remove repo %s (%s) branch %s", change.Alias, change.FromRepo, change.FromBranch))
+			lines = append(lines, fmt.Sprintf("- remove repo %s (%s) %s", change.Alias, change.FromRepo, formatPlanBranch(change.FromBranch)))
 			styles = append(styles, treeLineError)
 		case manifestplan.RepoUpdate:
 			lines = append(lines, formatPlanRepoUpdate(change))
@@ -174,6 +178,8 @@ func formatPlanRepoUpdate(change manifestplan.RepoChange) string {
 	toBranch := strings.TrimSpace(change.ToBranch)
 
 	switch {
+	case fromRepo == toRepo && fromBranch != toBranch && isPlanPinSwitch(fromBranch, toBranch):
+		return fmt.Sprintf("~ update repo %s: %s -> %s", change.Alias, formatPlanBranch(fromBranch), formatPlanBranch(toBranch))
 	case fromRepo == toRepo && fromBranch != toBranch:
 		return fmt.Sprintf("~ update repo %s: branch %s -> %s", change.Alias, fromBranch, toBranch)
 	case fromRepo != toRepo && fromBranch == toBranch:
@@ -183,15 +189,49 @@ func formatPlanRepoUpdate(change manifestplan.RepoChange) string {
 	}
 }
 
+// formatPlanBranch renders a planner branch as "branch <name>" or, for pinned repos, "ref <ref>".
+func formatPlanBranch(branch string) string {
+	if ref, ok := manifestplan.PinnedRef(branch); ok {
+		return "ref " + ref
+	}
+	return "branch " + strings.TrimSpace(branch)
+}
+
+func isPlanPinSwitch(fromBranch, toBranch string) bool {
+	_, fromPinned := manifestplan.PinnedRef(fromBranch)
+	_, toPinned := manifestplan.PinnedRef(toBranch)
+	return fromPinned || toPinned
+}
+
+func renderPlanRepoTreeLine(renderer *ui.Renderer, prefix, name, branch string) {
+	if ref, ok := manifestplan.PinnedRef(branch); ok {
+		renderer.TreeLineBranchMuted(prefix, fmt.Sprintf("%s (ref: %s)", name, ref), "")
+		return
+	}
+	renderer.TreeLineBranchMuted(prefix, name, branch)
+}
+
 func renderPlanChanges(ctx context.Context, rootDir string, renderer *ui.Renderer, plan manifestplan.Result) {
 	if renderer == nil {
 		return
 	}
+	for _, rename := range plan.Renames {
+		renderer.BulletAccent(fmt.Sprintf("~ rename workspace %s -> %s", rename.From, rename.To))
+	}
+	for _, move := range plan.Moves {
+		renderer.BulletAccent(fmt.Sprintf("~ move repo %s/%s -> %s/%s", move.FromWorkspace, move.FromAlias, move.ToWorkspace, move.ToAlias))
+	}
 	for _, change := range plan.Changes {
 		switch change.Kind {
 		case manifestplan.WorkspaceAdd:
 			desc := strings.TrimSpace(planWorkspaceDescription(plan, change.WorkspaceID))
 			line := fmt.Sprintf("+ add workspace %s", change.WorkspaceID)
+			if from := strings.TrimSpace(plan.Desired.Workspaces[change.WorkspaceID].CopiedFrom); from != "" {
+				line += fmt.Sprintf(" (copy of %s)", from)
+			}
+			if from := strings.TrimSpace(plan.Desired.Workspaces[change.WorkspaceID].RestoreFrom); from != "" {
+				line += fmt.Sprintf(" (restore of %s)", from)
+			}
 			if desc != "" {
 				line += " - " + desc
 			}
@@ -211,6 +251,26 @@ func renderPlanChanges(ctx context.Context, rootDir string, renderer *ui.Rendere
 			renderPlanWorkspaceUpdateRepos(renderer, change)
 		}
 	}
+	for _, update := range plan.LabelUpdates {
+		renderer.BulletAccent(fmt.Sprintf("~ update labels %s: %s -> %s", update.WorkspaceID, formatLabelList(update.From), formatLabelList(update.To)))
+	}
+	for _, update := range plan.ExpiryUpdates {
+		renderer.BulletAccent(fmt.Sprintf("~ update expiry %s: %s -> %s", update.WorkspaceID, formatExpiresAt(update.From), formatExpiresAt(update.To)))
+	}
+}
+
+func formatExpiresAt(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+func formatLabelList(labels []string) string {
+	if len(labels) == 0 {
+		return "(none)"
+	}
+	return "[" + strings.Join(labels, ", ") + "]"
 }
 
 func renderPlanWorkspaceAddRepos(renderer *ui.Renderer, changes []manifestplan.RepoChange) {
@@ -239,7 +299,7 @@ func renderPlanWorkspaceAddRepos(renderer *ui.Renderer, changes []manifestplan.R
 		if name == "" {
 			name = strings.TrimSpace(change.ToRepo)
 		}
-		renderer.TreeLineBranchMuted(prefix, name, change.ToBranch)
+		renderPlanRepoTreeLine(renderer, prefix, name, change.ToBranch)
 		if strings.TrimSpace(change.ToRepo) != "" {
 			renderer.TreeLine(renderer.MutedText(detailPrefix), renderer.MutedText("repo: "+strings.TrimSpace(change.ToRepo)))
 		}
@@ -268,7 +328,7 @@ func renderPlanWorkspaceUpdateRepos(renderer *ui.Renderer, change manifestplan.W
 		if branch == "" {
 			branch = strings.TrimSpace(repoChange.ToBranch)
 		}
-		renderer.TreeLineBranchMuted(prefix, name, branch)
+		renderPlanRepoTreeLine(renderer, prefix, name, branch)
 
 		if summary := formatRepoChangeSummary(repoChange); strings.TrimSpace(summary) != "" {
 			style := repoChangeSummaryStyle(renderer, repoChange.Kind)
@@ -307,7 +367,7 @@ func formatRepoChangeSummary(change manifestplan.RepoChange) string {
 	switch change.Kind {
 	case manifestplan.RepoAdd:
 		if strings.TrimSpace(change.ToRepo) != "" && strings.TrimSpace(change.ToBranch) != "" {
-			return fmt.Sprintf("add repo %s branch %s", strings.TrimSpace(change.ToRepo), strings.TrimSpace(change.ToBranch))
+			return fmt.Sprintf("add repo %s %s", strings.TrimSpace(change.ToRepo), formatPlanBranch(change.ToBranch))
 		}
 		if strings.TrimSpace(change.ToRepo) != "" {
 			return fmt.Sprintf("add repo %s", strings.TrimSpace(change.ToRepo))
@@ -315,7 +375,7 @@ func formatRepoChangeSummary(change manifestplan.RepoChange) string {
 		return "add repo"
 	case manifestplan.RepoRemove:
 		if strings.TrimSpace(change.FromRepo) != "" && strings.TrimSpace(change.FromBranch) != "" {
-			return fmt.Sprintf("remove repo %s branch %s", strings.TrimSpace(change.FromRepo), strings.TrimSpace(change.FromBranch))
+			return fmt.Sprintf("remove repo %s %s", strings.TrimSpace(change.FromRepo), formatPlanBranch(change.FromBranch))
 		}
 		if strings.TrimSpace(change.FromRepo) != "" {
 			return fmt.Sprintf("remove repo %s", strings.TrimSpace(change.FromRepo))
@@ -327,6 +387,8 @@ func formatRepoChangeSummary(change manifestplan.RepoChange) string {
 		fromBranch := strings.TrimSpace(change.FromBranch)
 		toBranch := strings.TrimSpace(change.ToBranch)
 		switch {
+		case fromRepo == toRepo && fromBranch != toBranch && isPlanPinSwitch(fromBranch, toBranch):
+			return fmt.Sprintf("%s -> %s", formatPlanBranch(fromBranch), formatPlanBranch(toBranch))
 		case fromRepo == toRepo && fromBranch != toBranch:
 			return fmt.Sprintf("branch %s -> %s", fromBranch, toBranch)
 		case fromRepo != toRepo && fromBranch == toBranch:
@@ -374,6 +436,9 @@ func renderWorkspaceRiskDetails(renderer *ui.Renderer, status workspace.StatusRe
 			name = filepath.Base(repoEntry.WorktreePath)
 		}
 		label := formatRepoLabel(name, repoEntry.Branch)
+		if repoEntry.Pinned != "" {
+			label = fmt.Sprintf("%s (ref: %s)", name, repoEntry.Pinned)
+		}
 		renderer.TreeLineBranchMuted(prefix, label, "")
 
 		detailPrefix := extraIndent + detailTreePrefix(i == len(status.Repos)-1)
@@ -414,6 +479,10 @@ func buildRepoRiskDetailLines(r *ui.Renderer, repo workspace.RepoStatus) []strin
 	if riskLine := formatRiskLine(r, repo); strings.TrimSpace(ansi.Strip(riskLine)) != "" {
 		lines = append(lines, riskLine)
 	}
+	if repo.Pinned != "" {
+		// Pinned worktrees are detached on purpose and have no upstream to sync with.
+		return lines
+	}
 	if repo.Detached {
 		lines = append(lines, r.WarnText("note: detached HEAD"))
 	}
@@ -468,10 +537,10 @@ func formatRiskLine(r *ui.Renderer, repo workspace.RepoStatus) string {
 }
 
 func repoRiskSummary(repo workspace.RepoStatus) (string, string, treeLineStyle) {
-	if repo.Error != nil || repo.Detached || repo.HeadMissing {
+	if repo.Error != nil || (repo.Detached && repo.Pinned == "") || repo.HeadMissing {
 		return "unknown", "", treeLineError
 	}
-	if strings.TrimSpace(repo.Upstream) == "" {
+	if repo.Pinned == "" && strings.TrimSpace(repo.Upstream) == "" {
 		return "upstream missing", "", treeLineWarn
 	}
 	if repo.Dirty {
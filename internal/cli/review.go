@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/reviewsync"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runReview(ctx context.Context, rootDir string, args []string) error {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		printReviewHelp(os.Stdout)
+		return nil
+	}
+	switch args[0] {
+	case "sync":
+		return runReviewSync(ctx, rootDir, args[1:])
+	default:
+		return fmt.Errorf("unknown review subcommand: %s", args[0])
+	}
+}
+
+func runReviewSync(ctx context.Context, rootDir string, args []string) error {
+	syncFlags := flag.NewFlagSet("review sync", flag.ContinueOnError)
+	var helpFlag bool
+	syncFlags.BoolVar(&helpFlag, "help", false, "show help")
+	syncFlags.BoolVar(&helpFlag, "h", false, "show help")
+	syncFlags.SetOutput(os.Stdout)
+	syncFlags.Usage = func() {
+		printReviewSyncHelp(os.Stdout)
+	}
+	if err := syncFlags.Parse(normalizeArgsFlagsFirst(args, nil)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printReviewSyncHelp(os.Stdout)
+		return nil
+	}
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	ids, err := reviewSyncTargets(desired, syncFlags.Args())
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+
+	if len(ids) == 0 {
+		renderer.Section("Result")
+		renderer.Bullet("no review workspaces")
+		return nil
+	}
+
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+	startSteps(renderer)
+
+	var moved []reviewsync.Result
+	var upToDate []string
+	var refused []string
+	var failed []string
+	for _, id := range ids {
+		output.Step(formatStep("review sync", id, ""))
+		result, err := reviewsync.Sync(ctx, rootDir, id, desired.Workspaces[id].Repos)
+		if err != nil {
+			if errors.Is(err, reviewsync.ErrDirty) || errors.Is(err, reviewsync.ErrLocalCommits) {
+				refused = append(refused, fmt.Sprintf("%s: %s", id, compactError(err)))
+				continue
+			}
+			failed = append(failed, fmt.Sprintf("%s: %s", id, compactError(err)))
+			continue
+		}
+		if result.Moved() {
+			moved = append(moved, result)
+			continue
+		}
+		upToDate = append(upToDate, id)
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	renderReviewSyncResult(renderer, moved, upToDate, refused, failed)
+	if len(failed) > 0 {
+		return fmt.Errorf("review sync failed for %d workspace(s)", len(failed))
+	}
+	return nil
+}
+
+// reviewSyncTargets resolves the workspaces to sync: the given IDs (which must be
+// review workspaces in the manifest), or every review workspace when none are given.
+func reviewSyncTargets(desired manifest.File, args []string) ([]string, error) {
+	if len(args) == 0 {
+		var ids []string
+		for id, ws := range desired.Workspaces {
+			if ws.Mode == workspace.MetadataModeReview {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		return ids, nil
+	}
+	seen := make(map[string]bool, len(args))
+	var ids []string
+	for _, arg := range args {
+		id := strings.TrimSpace(arg)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ws, ok := desired.Workspaces[id]
+		if !ok {
+			return nil, fmt.Errorf("workspace not found in %s: %s", manifest.FileName, id)
+		}
+		if ws.Mode != workspace.MetadataModeReview {
+			return nil, fmt.Errorf("workspace is not a review workspace: %s (mode: %s)", id, ws.Mode)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func renderReviewSyncResult(r *ui.Renderer, moved []reviewsync.Result, upToDate, refused, failed []string) {
+	r.Bullet(fmt.Sprintf("%s %d", r.SuccessText("moved:"), len(moved)))
+	if len(moved) > 0 {
+		var lines []string
+		for _, result := range moved {
+			for _, repo := range result.Repos {
+				if repo.Outcome == reviewsync.OutcomeUpToDate {
+					continue
+				}
+				lines = append(lines, fmt.Sprintf("%s %s %s %s", result.WorkspaceID, repo.Alias, r.MutedText(fmt.Sprintf("[%s]", repo.Outcome)), r.MutedText(shortSHA(repo.Before)+".."+shortSHA(repo.After))))
+			}
+		}
+		renderTreeLines(r, lines, treeLineNormal)
+	}
+	r.Bullet(fmt.Sprintf("up to date: %d", len(upToDate)))
+	if len(refused) > 0 {
+		r.Bullet(fmt.Sprintf("%s %d", r.WarnText("refused:"), len(refused)))
+		renderTreeLines(r, refused, treeLineWarn)
+	}
+	if len(failed) > 0 {
+		r.Bullet(fmt.Sprintf("%s %d", r.ErrorText("failed:"), len(failed)))
+		renderTreeLines(r, failed, treeLineError)
+	}
+}
+
+func shortSHA(sha string) string {
+	sha = strings.TrimSpace(sha)
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
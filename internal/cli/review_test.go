@@ -1,6 +1,13 @@
 package cli
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
 
 func TestParsePRURLGitHub(t *testing.T) {
 	req, err := parsePRURL("https://github.com/owner/repo/pull/123")
@@ -23,3 +30,131 @@ func TestParsePRURLUnsupported(t *testing.T) {
 		t.Fatalf("expected error for unsupported host")
 	}
 }
+
+func TestCollectGitHubSearchPages_FiltersByKind(t *testing.T) {
+	data := []byte(`{"total_count":3,"items":[
+		{"number":1,"title":"Fix bug","repository_url":"https://api.github.com/repos/owner/api","pull_request":{"url":"x"}},
+		{"number":2,"title":"Issue","repository_url":"https://api.github.com/repos/owner/api"},
+		{"number":3,"title":"GHE PR","repository_url":"https://ghe.example.com/api/v3/repos/team/web","pull_request":{}}
+	]}`)
+	fetchPage := func(n int) ([]byte, error) {
+		if n != 1 {
+			t.Fatalf("unexpected page %d after a short page", n)
+		}
+		return data, nil
+	}
+	results, err := collectGitHubSearchPages(fetchPage, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results.Fetched != 3 || results.Truncated() {
+		t.Fatalf("expected 3 fetched items and no truncation, got total %d fetched %d", results.Total, results.Fetched)
+	}
+	prs := results.Items
+	if len(prs) != 2 {
+		t.Fatalf("expected 2 PRs, got %+v", prs)
+	}
+	if prs[0].Owner != "owner" || prs[0].Repo != "api" || prs[0].Number != 1 || prs[0].Title != "Fix bug" {
+		t.Fatalf("unexpected first PR: %+v", prs[0])
+	}
+	if prs[1].Owner != "team" || prs[1].Repo != "web" || prs[1].Number != 3 {
+		t.Fatalf("unexpected second PR: %+v", prs[1])
+	}
+
+	issues, err := collectGitHubSearchPages(fetchPage, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues.Items) != 1 || issues.Items[0].Number != 2 {
+		t.Fatalf("expected issue #2 only, got %+v", issues.Items)
+	}
+
+	if _, err := collectGitHubSearchPages(func(int) ([]byte, error) { return []byte("not json"), nil }, true); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
+
+func TestCollectGitHubSearchPages(t *testing.T) {
+	page := func(total, from, count int) []byte {
+		var items []string
+		for i := from; i < from+count; i++ {
+			items = append(items, fmt.Sprintf(`{"number":%d,"repository_url":"https://api.github.com/repos/owner/api","pull_request":{}}`, i))
+		}
+		return []byte(fmt.Sprintf(`{"total_count":%d,"items":[%s]}`, total, strings.Join(items, ",")))
+	}
+
+	t.Run("paginates", func(t *testing.T) {
+		var requested []int
+		results, err := collectGitHubSearchPages(func(n int) ([]byte, error) {
+			requested = append(requested, n)
+			return page(150, 1+(n-1)*githubSearchPageSize, min(githubSearchPageSize, 150-(n-1)*githubSearchPageSize)), nil
+		}, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(requested) != 2 || len(results.Items) != 150 || results.Truncated() {
+			t.Fatalf("pages %v: got %d items (total %d, fetched %d)", requested, len(results.Items), results.Total, results.Fetched)
+		}
+	})
+
+	t.Run("truncated_at_cap", func(t *testing.T) {
+		var requested []int
+		results, err := collectGitHubSearchPages(func(n int) ([]byte, error) {
+			requested = append(requested, n)
+			return page(2500, 1+(n-1)*githubSearchPageSize, githubSearchPageSize), nil
+		}, true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(requested) != githubSearchMaxResults/githubSearchPageSize || results.Fetched != githubSearchMaxResults {
+			t.Fatalf("pages %v: fetched %d", requested, results.Fetched)
+		}
+		if !results.Truncated() || results.Total != 2500 {
+			t.Fatalf("expected truncated results, got total %d fetched %d", results.Total, results.Fetched)
+		}
+	})
+}
+
+func TestNormalizeSearchQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		prs   bool
+		want  string
+	}{
+		{query: "review-requested:@me", prs: true, want: "is:pr is:open review-requested:@me"},
+		{query: "assignee:@me", prs: false, want: "is:issue is:open assignee:@me"},
+		{query: "is:pr is:merged author:@me", prs: true, want: "is:pr is:merged author:@me"},
+		{query: "state:closed label:bug", prs: false, want: "is:issue state:closed label:bug"},
+	}
+	for _, tc := range cases {
+		if got := normalizeSearchQuery(tc.query, tc.prs); got != tc.want {
+			t.Fatalf("normalizeSearchQuery(%q) = %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestReviewSyncTargets(t *testing.T) {
+	desired := manifest.File{
+		Version: 1,
+		Workspaces: map[string]manifest.Workspace{
+			"ORG-APP-REVIEW-PR-2": {Mode: workspace.MetadataModeReview},
+			"ORG-APP-REVIEW-PR-1": {Mode: workspace.MetadataModeReview},
+			"PROJ-1":              {Mode: workspace.MetadataModeRepo},
+		},
+	}
+
+	ids, err := reviewSyncTargets(desired, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ids, ",") != "ORG-APP-REVIEW-PR-1,ORG-APP-REVIEW-PR-2" {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+
+	if _, err := reviewSyncTargets(desired, []string{"PROJ-1"}); err == nil || !strings.Contains(err.Error(), "not a review workspace") {
+		t.Fatalf("expected non-review error, got: %v", err)
+	}
+	if _, err := reviewSyncTargets(desired, []string{"MISSING"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got: %v", err)
+	}
+}
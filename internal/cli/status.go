@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runStatus(ctx context.Context, rootDir string, args []string) error {
+	statusFlags := flag.NewFlagSet("status", flag.ContinueOnError)
+	var allFlag bool
+	var jsonFlag bool
+	var helpFlag bool
+	statusFlags.BoolVar(&allFlag, "all", false, "summarize every workspace")
+	statusFlags.BoolVar(&jsonFlag, "json", false, "print JSON")
+	statusFlags.BoolVar(&helpFlag, "help", false, "show help")
+	statusFlags.BoolVar(&helpFlag, "h", false, "show help")
+	statusFlags.SetOutput(os.Stdout)
+	statusFlags.Usage = func() {
+		printStatusHelp(os.Stdout)
+	}
+	if err := statusFlags.Parse(normalizeArgsFlagsFirst(args, nil)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printStatusHelp(os.Stdout)
+		return nil
+	}
+	if statusFlags.NArg() > 1 || (allFlag && statusFlags.NArg() > 0) {
+		return fmt.Errorf("usage: gion status [<WORKSPACE_ID> | --all] [--json]")
+	}
+
+	if allFlag {
+		entries, warnings, err := workspace.List(rootDir)
+		if err != nil {
+			return err
+		}
+		var statuses []workspace.StatusResult
+		for _, entry := range entries {
+			status, err := workspace.Status(ctx, rootDir, entry.WorkspaceID)
+			if err != nil {
+				status = workspace.StatusResult{WorkspaceID: entry.WorkspaceID, Warnings: []error{err}}
+			}
+			statuses = append(statuses, status)
+		}
+		if jsonFlag {
+			return writeStatusJSON(os.Stdout, toStatusAllJSON(rootDir, statuses, warnings))
+		}
+		renderer := ui.NewRenderer(os.Stdout, ui.DefaultTheme(), isatty.IsTerminal(os.Stdout.Fd()))
+		renderer.Section("Status")
+		if len(statuses) == 0 {
+			renderer.Bullet("no workspaces")
+		}
+		renderStatusSummary(renderer, statuses)
+		for _, warning := range warnings {
+			renderer.BulletWarn(compactError(warning))
+		}
+		return nil
+	}
+
+	workspaceID, err := statusWorkspaceID(rootDir, statusFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	status, err := workspace.Status(ctx, rootDir, workspaceID)
+	if err != nil {
+		return err
+	}
+	if jsonFlag {
+		return writeStatusJSON(os.Stdout, toStatusJSON(rootDir, status))
+	}
+	renderer := ui.NewRenderer(os.Stdout, ui.DefaultTheme(), isatty.IsTerminal(os.Stdout.Fd()))
+	renderer.Section("Status")
+	state := workspace.StateFromStatus(status)
+	renderer.Bullet(fmt.Sprintf("%s %s %s", workspaceID, formatWorkspaceStateTag(renderer, state.Kind), renderer.MutedText(workspace.WorkspaceDir(rootDir, workspaceID))))
+	renderWorkspaceStatusDetails(renderer, status, output.Indent)
+	return nil
+}
+
+// statusWorkspaceID returns the given workspace ID, or the workspace that
+// contains the current directory when none is given.
+func statusWorkspaceID(rootDir, arg string) (string, error) {
+	if workspaceID := strings.TrimSpace(arg); workspaceID != "" {
+		return workspaceID, nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	id, ok := workspace.WorkspaceIDFromPath(rootDir, cwd)
+	if !ok {
+		return "", fmt.Errorf("not inside a workspace (specify <WORKSPACE_ID> or --all)")
+	}
+	return id, nil
+}
+
+// renderWorkspaceStatusDetails lists every repo with its branch, sync state and
+// changed files. Unlike the risk details of removal plans, clean repos are shown too.
+func renderWorkspaceStatusDetails(r *ui.Renderer, status workspace.StatusResult, extraIndent string) {
+	for i, repoEntry := range status.Repos {
+		prefix := extraIndent + output.TreeBranchMid
+		if i == len(status.Repos)-1 && len(status.Warnings) == 0 {
+			prefix = extraIndent + output.TreeBranchLast
+		}
+		label := formatRepoLabel(repoEntry.Alias, repoEntry.Branch)
+		if repoEntry.Pinned != "" {
+			label = fmt.Sprintf("%s (ref: %s)", repoEntry.Alias, repoEntry.Pinned)
+		} else if repoEntry.Detached {
+			label = fmt.Sprintf("%s (detached at %s)", repoEntry.Alias, shortSHA(repoEntry.Head))
+		}
+		r.TreeLineBranchMuted(prefix, label, "")
+
+		detailPrefix := extraIndent + detailTreePrefix(i == len(status.Repos)-1 && len(status.Warnings) == 0)
+		for _, line := range buildRepoStatusLines(r, repoEntry) {
+			r.TreeLine(r.MutedText(detailPrefix), line)
+		}
+	}
+	for i, warning := range status.Warnings {
+		prefix := output.TreeBranchMid
+		if i == len(status.Warnings)-1 {
+			prefix = output.TreeBranchLast
+		}
+		r.TreeLine(r.MutedText(extraIndent+prefix), r.WarnText(fmt.Sprintf("warning: %s", compactError(warning))))
+	}
+}
+
+func buildRepoStatusLines(r *ui.Renderer, repo workspace.RepoStatus) []string {
+	if repo.Error != nil {
+		return []string{r.ErrorText(fmt.Sprintf("status error: %s", compactError(repo.Error)))}
+	}
+	var lines []string
+	if repo.Pinned == "" && !repo.Detached {
+		lines = append(lines, formatSyncSummaryLine(r, repo))
+	}
+	if repo.HeadMissing {
+		lines = append(lines, r.WarnText("note: head missing"))
+	}
+	if !repo.Dirty {
+		return append(lines, r.MutedText("files: clean"))
+	}
+	lines = append(lines, r.MutedText(fmt.Sprintf("files: %s", formatDirtySummary(repo))))
+	for _, file := range repo.ChangedFiles {
+		if strings.TrimSpace(file) == "" {
+			continue
+		}
+		lines = append(lines, "  "+formatChangedFileLine(r, file))
+	}
+	return lines
+}
+
+// renderStatusSummary prints one aligned row per workspace: ID, state, repo
+// count and the repos that are not clean.
+func renderStatusSummary(r *ui.Renderer, statuses []workspace.StatusResult) {
+	idWidth, stateWidth := 0, 0
+	states := make([]workspace.WorkspaceState, len(statuses))
+	for i, status := range statuses {
+		states[i] = workspace.StateFromStatus(status)
+		idWidth = max(idWidth, len(status.WorkspaceID))
+		stateWidth = max(stateWidth, len(states[i].Kind))
+	}
+	for i, status := range statuses {
+		state := states[i]
+		var notes []string
+		for _, repo := range state.Repos {
+			switch repo.Kind {
+			case workspace.RepoStateClean:
+				continue
+			case workspace.RepoStateDirty:
+				detail := formatDirtySummaryCounts(repo.StagedCount, repo.UnstagedCount, repo.UntrackedCount, repo.UnmergedCount)
+				notes = append(notes, fmt.Sprintf("%s: dirty (%s)", repo.Alias, detail))
+			case workspace.RepoStateUnpushed:
+				notes = append(notes, fmt.Sprintf("%s: ahead=%d", repo.Alias, repo.AheadCount))
+			case workspace.RepoStateDiverged:
+				notes = append(notes, fmt.Sprintf("%s: ahead=%d behind=%d", repo.Alias, repo.AheadCount, repo.BehindCount))
+			default:
+				notes = append(notes, fmt.Sprintf("%s: %s", repo.Alias, repo.Kind))
+			}
+		}
+		behind := 0
+		for _, repo := range state.Repos {
+			behind += repo.BehindCount
+		}
+		if behind > 0 && state.Kind == workspace.WorkspaceStateClean {
+			notes = append(notes, fmt.Sprintf("behind=%d", behind))
+		}
+		for _, warning := range status.Warnings {
+			notes = append(notes, compactError(warning))
+		}
+		kind := formatWorkspaceStateTag(r, state.Kind)
+		kind += strings.Repeat(" ", stateWidth-len(state.Kind))
+		line := fmt.Sprintf("%-*s  %s  %s", idWidth, status.WorkspaceID, kind, r.MutedText(fmt.Sprintf("repos=%d", len(status.Repos))))
+		if len(notes) > 0 {
+			line += "  " + strings.Join(notes, ", ")
+		}
+		r.Bullet(line)
+	}
+}
+
+func formatWorkspaceStateTag(r *ui.Renderer, kind workspace.WorkspaceStateKind) string {
+	switch kind {
+	case workspace.WorkspaceStateClean:
+		return r.SuccessText(string(kind))
+	case workspace.WorkspaceStateUnknown:
+		return r.ErrorText(string(kind))
+	default:
+		return r.WarnText(string(kind))
+	}
+}
+
+type statusJSON struct {
+	WorkspaceID string           `json:"workspace_id"`
+	Path        string           `json:"path"`
+	State       string           `json:"state"`
+	Repos       []repoStatusJSON `json:"repos"`
+	Warnings    []string         `json:"warnings,omitempty"`
+}
+
+// statusAllJSON is the --all output: every workspace plus the warnings from
+// listing the workspaces directory.
+type statusAllJSON struct {
+	Workspaces []statusJSON `json:"workspaces"`
+	Warnings   []string     `json:"warnings,omitempty"`
+}
+
+type repoStatusJSON struct {
+	Alias        string   `json:"alias"`
+	Path         string   `json:"path"`
+	Branch       string   `json:"branch,omitempty"`
+	Upstream     string   `json:"upstream,omitempty"`
+	Head         string   `json:"head,omitempty"`
+	Detached     bool     `json:"detached"`
+	Pinned       string   `json:"pinned,omitempty"`
+	State        string   `json:"state"`
+	Ahead        int      `json:"ahead"`
+	Behind       int      `json:"behind"`
+	Staged       int      `json:"staged"`
+	Unstaged     int      `json:"unstaged"`
+	Untracked    int      `json:"untracked"`
+	Unmerged     int      `json:"unmerged"`
+	ChangedFiles []string `json:"changed_files"`
+	Error        string   `json:"error,omitempty"`
+}
+
+func toStatusJSON(rootDir string, status workspace.StatusResult) statusJSON {
+	state := workspace.StateFromStatus(status)
+	out := statusJSON{
+		WorkspaceID: status.WorkspaceID,
+		Path:        workspace.WorkspaceDir(rootDir, status.WorkspaceID),
+		State:       string(state.Kind),
+		Repos:       make([]repoStatusJSON, 0, len(status.Repos)),
+	}
+	for i, repo := range status.Repos {
+		entry := repoStatusJSON{
+			Alias:        repo.Alias,
+			Path:         repo.WorktreePath,
+			Branch:       repo.Branch,
+			Upstream:     repo.Upstream,
+			Head:         repo.Head,
+			Detached:     repo.Detached,
+			Pinned:       repo.Pinned,
+			State:        string(state.Repos[i].Kind),
+			Ahead:        repo.AheadCount,
+			Behind:       repo.BehindCount,
+			Staged:       repo.StagedCount,
+			Unstaged:     repo.UnstagedCount,
+			Untracked:    repo.UntrackedCount,
+			Unmerged:     repo.UnmergedCount,
+			ChangedFiles: repo.ChangedFiles,
+		}
+		if entry.ChangedFiles == nil {
+			entry.ChangedFiles = []string{}
+		}
+		if repo.Error != nil {
+			entry.Error = compactError(repo.Error)
+		}
+		out.Repos = append(out.Repos, entry)
+	}
+	for _, warning := range status.Warnings {
+		out.Warnings = append(out.Warnings, compactError(warning))
+	}
+	return out
+}
+
+func toStatusAllJSON(rootDir string, statuses []workspace.StatusResult, warnings []error) statusAllJSON {
+	out := statusAllJSON{Workspaces: make([]statusJSON, 0, len(statuses))}
+	for _, status := range statuses {
+		out.Workspaces = append(out.Workspaces, toStatusJSON(rootDir, status))
+	}
+	for _, warning := range warnings {
+		out.Warnings = append(out.Warnings, compactError(warning))
+	}
+	return out
+}
+
+func writeStatusJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
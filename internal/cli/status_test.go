@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func TestToStatusJSON(t *testing.T) {
+	status := workspace.StatusResult{
+		WorkspaceID: "PROJ-1",
+		Repos: []workspace.RepoStatus{
+			{Alias: "api", Branch: "PROJ-1", Upstream: "origin/PROJ-1", Head: "abc1234", Dirty: true, UnstagedCount: 1, UntrackedCount: 1, ChangedFiles: []string{" M main.go", "?? new.txt"}},
+			{Alias: "web", Branch: "PROJ-1", Upstream: "origin/PROJ-1", Head: "def5678", AheadCount: 2},
+			{Alias: "docs", Error: errors.New("git status failed")},
+		},
+	}
+
+	got := toStatusJSON("/gion", status)
+	if got.State != string(workspace.WorkspaceStateDirty) {
+		t.Fatalf("state = %q, want dirty", got.State)
+	}
+	if got.Path != workspace.WorkspaceDir("/gion", "PROJ-1") {
+		t.Fatalf("path = %q", got.Path)
+	}
+	wantStates := []workspace.RepoStateKind{workspace.RepoStateDirty, workspace.RepoStateUnpushed, workspace.RepoStateUnknown}
+	for i, want := range wantStates {
+		if got.Repos[i].State != string(want) {
+			t.Fatalf("%s: state = %q, want %q", got.Repos[i].Alias, got.Repos[i].State, want)
+		}
+	}
+	if len(got.Repos[0].ChangedFiles) != 2 || got.Repos[1].ChangedFiles == nil {
+		t.Fatalf("changed_files = %v / %v", got.Repos[0].ChangedFiles, got.Repos[1].ChangedFiles)
+	}
+	if got.Repos[2].Error != "git status failed" {
+		t.Fatalf("error = %q", got.Repos[2].Error)
+	}
+
+	var buf bytes.Buffer
+	if err := writeStatusJSON(&buf, got); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if decoded["workspace_id"] != "PROJ-1" {
+		t.Fatalf("workspace_id = %v", decoded["workspace_id"])
+	}
+}
+
+func TestToStatusAllJSON_IncludesListWarnings(t *testing.T) {
+	statuses := []workspace.StatusResult{
+		{WorkspaceID: "PROJ-1", Repos: []workspace.RepoStatus{{Alias: "api", Branch: "PROJ-1", Upstream: "origin/PROJ-1"}}},
+	}
+	got := toStatusAllJSON("/gion", statuses, []error{errors.New("read workspace STRAY: not a directory")})
+
+	var buf bytes.Buffer
+	if err := writeStatusJSON(&buf, got); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	var decoded struct {
+		Workspaces []map[string]any `json:"workspaces"`
+		Warnings   []string         `json:"warnings"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if len(decoded.Workspaces) != 1 || decoded.Workspaces[0]["workspace_id"] != "PROJ-1" {
+		t.Fatalf("workspaces = %v", decoded.Workspaces)
+	}
+	if len(decoded.Warnings) != 1 || !strings.Contains(decoded.Warnings[0], "STRAY") {
+		t.Fatalf("warnings = %v", decoded.Warnings)
+	}
+
+	buf.Reset()
+	if err := writeStatusJSON(&buf, toStatusAllJSON("/gion", nil, nil)); err != nil {
+		t.Fatalf("write json: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"workspaces": []`) || strings.Contains(buf.String(), "warnings") {
+		t.Fatalf("unexpected empty output:\n%s", buf.String())
+	}
+}
+
+func TestStatusWorkspaceID_FromNestedWorktreeDir(t *testing.T) {
+	rootDir := t.TempDir()
+	nested := filepath.Join(workspace.WorktreePath(rootDir, "PROJ-1", "api"), "internal", "pkg")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	t.Chdir(nested)
+	id, err := statusWorkspaceID(rootDir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "PROJ-1" {
+		t.Fatalf("id = %q, want PROJ-1", id)
+	}
+	if id, err := statusWorkspaceID(rootDir, " PROJ-2 "); err != nil || id != "PROJ-2" {
+		t.Fatalf("explicit id = %q, %v; want PROJ-2", id, err)
+	}
+
+	t.Chdir(rootDir)
+	if _, err := statusWorkspaceID(rootDir, ""); err == nil || !strings.Contains(err.Error(), "not inside a workspace") {
+		t.Fatalf("expected not inside a workspace, got %v", err)
+	}
+}
+
+func TestRenderStatusSummary(t *testing.T) {
+	statuses := []workspace.StatusResult{
+		{WorkspaceID: "PROJ-1", Repos: []workspace.RepoStatus{
+			{Alias: "api", Branch: "PROJ-1", Upstream: "origin/PROJ-1", Dirty: true, UnstagedCount: 2},
+			{Alias: "web", Branch: "PROJ-1", Upstream: "origin/PROJ-1", AheadCount: 1},
+		}},
+		{WorkspaceID: "LONGER-ID-2", Repos: []workspace.RepoStatus{
+			{Alias: "api", Branch: "LONGER-ID-2", Upstream: "origin/LONGER-ID-2", BehindCount: 3},
+		}},
+		{WorkspaceID: "BROKEN", Repos: []workspace.RepoStatus{{Alias: "docs", Error: errors.New("git status failed")}}, Warnings: []error{errors.New("scan repos: permission denied")}},
+	}
+
+	var buf bytes.Buffer
+	renderStatusSummary(ui.NewRenderer(&buf, ui.DefaultTheme(), false), statuses)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 rows, got:\n%s", buf.String())
+	}
+	want := []string{
+		"PROJ-1       dirty    repos=2  api: dirty (unstaged=2), web: ahead=1",
+		"LONGER-ID-2  clean    repos=1  behind=3",
+		"BROKEN       unknown  repos=1  docs: unknown, scan repos: permission denied",
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, want[i]) {
+			t.Fatalf("row %d = %q, want suffix %q", i, line, want[i])
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/workspacesync"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/infra/prefetcher"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runSync(ctx context.Context, rootDir string, args []string) error {
+	syncFlags := flag.NewFlagSet("sync", flag.ContinueOnError)
+	var strategyFlag string
+	var noFetch bool
+	var helpFlag bool
+	syncFlags.StringVar(&strategyFlag, "strategy", string(workspacesync.StrategyMerge), "ff-only, merge or rebase")
+	syncFlags.BoolVar(&noFetch, "no-fetch", false, "do not fetch repo stores")
+	syncFlags.BoolVar(&helpFlag, "help", false, "show help")
+	syncFlags.BoolVar(&helpFlag, "h", false, "show help")
+	syncFlags.SetOutput(os.Stdout)
+	syncFlags.Usage = func() {
+		printSyncHelp(os.Stdout)
+	}
+	if err := syncFlags.Parse(normalizeArgsFlagsFirst(args, map[string]struct{}{"--strategy": {}, "-strategy": {}})); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printSyncHelp(os.Stdout)
+		return nil
+	}
+	strategy, err := workspacesync.ParseStrategy(strategyFlag)
+	if err != nil {
+		return err
+	}
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	ids, err := syncTargets(desired, syncFlags.Args())
+	if err != nil {
+		return err
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+
+	if len(ids) == 0 {
+		renderer.Section("Result")
+		renderer.Bullet("no workspaces")
+		return nil
+	}
+
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+	startSteps(renderer)
+
+	if !noFetch {
+		toFetch := repoSpecsForSync(desired, ids)
+		output.Step(formatStep("fetch", fmt.Sprintf("%d repo(s)", len(toFetch)), ""))
+		prefetch := prefetcher.New(defaultPrefetchTimeout)
+		if _, err := prefetch.StartAll(ctx, rootDir, toFetch); err != nil {
+			return err
+		}
+		if err := prefetch.WaitAll(ctx, toFetch); err != nil {
+			renderer.BulletWarn(fmt.Sprintf("fetch failed (continuing): %v", err))
+		}
+	}
+
+	var results []workspacesync.Result
+	var failed []string
+	for _, id := range ids {
+		output.Step(formatStep("sync", id, ""))
+		result, err := workspacesync.Sync(ctx, rootDir, id, desired.Workspaces[id].Repos, workspacesync.Options{Strategy: strategy})
+		if len(result.Repos) > 0 {
+			results = append(results, result)
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", id, compactError(err)))
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	renderSyncResult(renderer, results, failed)
+
+	var conflicts []string
+	for _, result := range results {
+		for _, repoResult := range result.Repos {
+			if repoResult.Outcome == workspacesync.OutcomeConflict {
+				conflicts = append(conflicts, fmt.Sprintf("cd %s && git %s %s (resolve conflicts manually)", repoResult.WorktreePath, strategy, repoResult.Base))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		renderer.Blank()
+		renderer.Section("Suggestion")
+		for _, line := range conflicts {
+			renderer.Bullet(line)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("sync failed for %d workspace(s)", len(failed))
+	}
+	failedRepos := 0
+	for _, result := range results {
+		failedRepos += len(result.Failed())
+	}
+	if failedRepos > 0 {
+		return fmt.Errorf("sync failed for %d repo(s)", failedRepos)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("sync stopped on %d conflict(s)", len(conflicts))
+	}
+	return nil
+}
+
+// syncTargets resolves the workspaces to sync: the given IDs, or every workspace
+// in the manifest except review workspaces (see gion review sync) when none are given.
+func syncTargets(desired manifest.File, args []string) ([]string, error) {
+	if len(args) == 0 {
+		var ids []string
+		for id, ws := range desired.Workspaces {
+			if ws.Mode != workspace.MetadataModeReview {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		return ids, nil
+	}
+	seen := make(map[string]bool, len(args))
+	var ids []string
+	for _, arg := range args {
+		id := strings.TrimSpace(arg)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ws, ok := desired.Workspaces[id]
+		if !ok {
+			return nil, fmt.Errorf("workspace not found in %s: %s", manifest.FileName, id)
+		}
+		if ws.Mode == workspace.MetadataModeReview {
+			return nil, fmt.Errorf("workspace %s is a review workspace (use: gion review sync)", id)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func repoSpecsForSync(desired manifest.File, ids []string) []string {
+	seen := map[string]bool{}
+	var specs []string
+	for _, id := range ids {
+		for _, repoEntry := range desired.Workspaces[id].Repos {
+			if strings.TrimSpace(repoEntry.Ref) != "" {
+				continue
+			}
+			spec := strings.TrimSpace(repo.SpecFromKey(repoEntry.RepoKey))
+			if spec == "" || seen[spec] {
+				continue
+			}
+			seen[spec] = true
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+func renderSyncResult(r *ui.Renderer, results []workspacesync.Result, failed []string) {
+	counts := map[workspacesync.Outcome]int{}
+	for _, result := range results {
+		aliasWidth, outcomeWidth := 0, 0
+		for _, repoResult := range result.Repos {
+			aliasWidth = max(aliasWidth, len(repoResult.Alias))
+			outcomeWidth = max(outcomeWidth, len(repoResult.Outcome))
+		}
+		var lines []string
+		for _, repoResult := range result.Repos {
+			counts[repoResult.Outcome]++
+			outcome := fmt.Sprintf("%-*s", outcomeWidth, repoResult.Outcome)
+			var detail string
+			switch repoResult.Outcome {
+			case workspacesync.OutcomeFastForward:
+				outcome = r.SuccessText(outcome)
+				detail = fmt.Sprintf("%s %s", repoResult.Upstream, r.MutedText(shortSHA(repoResult.Before)+".."+shortSHA(repoResult.After)))
+			case workspacesync.OutcomeMerged, workspacesync.OutcomeRebased:
+				outcome = r.SuccessText(outcome)
+				detail = fmt.Sprintf("%s %s", repoResult.Base, r.MutedText(shortSHA(repoResult.Before)+".."+shortSHA(repoResult.After)))
+			case workspacesync.OutcomeConflict:
+				outcome = r.ErrorText(outcome)
+				detail = fmt.Sprintf("%s (aborted): %s", repoResult.Base, strings.Join(repoResult.Conflicts, ", "))
+			case workspacesync.OutcomeDirty:
+				outcome = r.WarnText(outcome)
+				detail = r.MutedText("uncommitted changes, not touched")
+			case workspacesync.OutcomeDiverged:
+				outcome = r.WarnText(outcome)
+				detail = r.MutedText(fmt.Sprintf("diverged from %s", repoResult.Upstream))
+			case workspacesync.OutcomeFailed:
+				outcome = r.ErrorText(outcome)
+				detail = compactError(repoResult.Err)
+			case workspacesync.OutcomeUpToDate:
+				outcome = r.MutedText(outcome)
+				detail = r.MutedText(repoResult.Base)
+			default:
+				outcome = r.MutedText(outcome)
+			}
+			lines = append(lines, strings.TrimRight(fmt.Sprintf("%-*s  %s  %s", aliasWidth, repoResult.Alias, outcome, detail), " "))
+		}
+		r.Bullet(result.WorkspaceID)
+		renderTreeLines(r, lines, treeLineNormal)
+	}
+
+	var summary []string
+	for _, outcome := range []workspacesync.Outcome{
+		workspacesync.OutcomeUpToDate,
+		workspacesync.OutcomeFastForward,
+		workspacesync.OutcomeMerged,
+		workspacesync.OutcomeRebased,
+		workspacesync.OutcomeConflict,
+		workspacesync.OutcomeFailed,
+		workspacesync.OutcomeDirty,
+		workspacesync.OutcomeDiverged,
+		workspacesync.OutcomeDetached,
+		workspacesync.OutcomePinned,
+	} {
+		if counts[outcome] > 0 {
+			summary = append(summary, fmt.Sprintf("%s: %d", outcome, counts[outcome]))
+		}
+	}
+	if len(summary) > 0 {
+		r.Bullet(strings.Join(summary, ", "))
+	}
+	if len(failed) > 0 {
+		r.Bullet(fmt.Sprintf("%s %d", r.ErrorText("failed:"), len(failed)))
+		renderTreeLines(r, failed, treeLineError)
+	}
+}
@@ -180,6 +180,9 @@ func repoNeedsStatusDetails(repo workspace.RepoStatus) bool {
 	if repo.Dirty {
 		return true
 	}
+	if repo.Pinned != "" {
+		return false
+	}
 	if repo.Detached || repo.HeadMissing {
 		return true
 	}
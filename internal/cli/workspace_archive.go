@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tasuku43/gion/internal/app/archive"
+	"github.com/tasuku43/gion/internal/app/manifestplan"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runWorkspaceArchive(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	archiveFlags := flag.NewFlagSet("workspace archive", flag.ContinueOnError)
+	var noApply bool
+	var noPromptFlag bool
+	var helpFlag bool
+	archiveFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
+	archiveFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	archiveFlags.BoolVar(&helpFlag, "help", false, "show help")
+	archiveFlags.BoolVar(&helpFlag, "h", false, "show help")
+	archiveFlags.SetOutput(os.Stdout)
+	archiveFlags.Usage = func() {
+		printWorkspaceArchiveHelp(os.Stdout)
+	}
+	if err := archiveFlags.Parse(normalizeArgsFlagsFirst(args, nil)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printWorkspaceArchiveHelp(os.Stdout)
+		return nil
+	}
+	if archiveFlags.NArg() != 1 {
+		return fmt.Errorf("usage: gion workspace archive <WORKSPACE_ID> [--no-apply] [--no-prompt]")
+	}
+	workspaceID := strings.TrimSpace(archiveFlags.Arg(0))
+
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	entry, ok := desired.Workspaces[workspaceID]
+	if !ok {
+		return fmt.Errorf("workspace not found in %s: %s", manifest.FileName, workspaceID)
+	}
+	original, err := manifest.TakeSnapshot(rootDir)
+	if err != nil {
+		return err
+	}
+
+	archived, err := archive.Create(ctx, rootDir, workspaceID, entry, time.Now())
+	if err != nil {
+		return err
+	}
+
+	updated := desired
+	updated.Workspaces = make(map[string]manifest.Workspace, len(desired.Workspaces))
+	for id, ws := range desired.Workspaces {
+		if id == workspaceID {
+			continue
+		}
+		updated.Workspaces[id] = ws
+	}
+
+	return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
+		NoApply:  noApply,
+		NoPrompt: noPrompt,
+		Original: original,
+		Rollback: func() error {
+			if err := archived.Remove(); err != nil {
+				return fmt.Errorf("remove archive %s: %w", archived.Dir, err)
+			}
+			return nil
+		},
+		Hooks: manifestMutationHooks{
+			ShowPrelude: func(r *ui.Renderer) {
+				r.Section("Inputs")
+				r.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+				renderArchiveRepos(r, archived)
+			},
+			RenderNoApply: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("archived %s to %s", workspaceID, archived.Dir))
+				r.Bullet(fmt.Sprintf("updated %s (removed %s)", manifest.FileName, workspaceID))
+				r.Blank()
+				r.Section("Suggestion")
+				r.Bullet("gion apply")
+			},
+			RenderNoChanges: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("archived %s to %s", workspaceID, archived.Dir))
+				r.Bullet("no changes")
+			},
+			RenderInfoBeforeApply: func(r *ui.Renderer, _ manifestplan.Result, _ bool) {
+				r.Section("Info")
+				r.Bullet(fmt.Sprintf("archive: %s", archived.Dir))
+				r.Bullet(fmt.Sprintf("manifest: updated %s (removed %s)", manifest.FileName, workspaceID))
+				r.Bullet("apply: reconciling entire root (destructive removals require confirmation)")
+				r.Bullet(fmt.Sprintf("restore: gion workspace restore %s", archived.Name()))
+			},
+		},
+	})
+}
+
+func runWorkspaceRestore(ctx context.Context, rootDir string, args []string, globalNoPrompt bool) error {
+	restoreFlags := flag.NewFlagSet("workspace restore", flag.ContinueOnError)
+	var noApply bool
+	var noPromptFlag bool
+	var helpFlag bool
+	restoreFlags.BoolVar(&noApply, "no-apply", false, "do not run gion apply")
+	restoreFlags.BoolVar(&noPromptFlag, "no-prompt", false, "disable interactive prompt")
+	restoreFlags.BoolVar(&helpFlag, "help", false, "show help")
+	restoreFlags.BoolVar(&helpFlag, "h", false, "show help")
+	restoreFlags.SetOutput(os.Stdout)
+	restoreFlags.Usage = func() {
+		printWorkspaceRestoreHelp(os.Stdout)
+	}
+	if err := restoreFlags.Parse(normalizeArgsFlagsFirst(args, nil)); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printWorkspaceRestoreHelp(os.Stdout)
+		return nil
+	}
+	if restoreFlags.NArg() != 1 {
+		return fmt.Errorf("usage: gion workspace restore <ARCHIVE> [--no-apply] [--no-prompt]")
+	}
+
+	noPrompt := globalNoPrompt || noPromptFlag
+
+	dir, err := archive.Resolve(rootDir, restoreFlags.Arg(0))
+	if err != nil {
+		return err
+	}
+	archived, err := archive.Load(dir)
+	if err != nil {
+		return err
+	}
+	workspaceID := archived.WorkspaceID
+	if err := workspace.ValidateWorkspaceID(ctx, workspaceID); err != nil {
+		return err
+	}
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	if _, exists := desired.Workspaces[workspaceID]; exists {
+		return fmt.Errorf("workspace already exists in %s: %s", manifest.FileName, workspaceID)
+	}
+	if exists, err := paths.DirExists(workspace.WorkspaceDir(rootDir, workspaceID)); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("workspace already exists on the filesystem: %s", workspaceID)
+	}
+	original, err := manifest.TakeSnapshot(rootDir)
+	if err != nil {
+		return err
+	}
+
+	entry := archived.Entry()
+	entry.RestoreFrom = archiveReference(rootDir, dir)
+	updated := desired
+	updated.Workspaces = make(map[string]manifest.Workspace, len(desired.Workspaces)+1)
+	for id, ws := range desired.Workspaces {
+		updated.Workspaces[id] = ws
+	}
+	updated.Workspaces[workspaceID] = entry
+
+	summary := fmt.Sprintf("restored %s from %s", workspaceID, archived.Name())
+
+	return applyManifestMutation(ctx, rootDir, updated, manifestMutationOptions{
+		NoApply:  noApply,
+		NoPrompt: noPrompt,
+		Original: original,
+		Hooks: manifestMutationHooks{
+			ShowPrelude: func(r *ui.Renderer) {
+				r.Section("Inputs")
+				r.Bullet(fmt.Sprintf("archive: %s (archived at %s)", archived.Dir, archived.ArchivedAt))
+				r.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+				renderArchiveRepos(r, archived)
+			},
+			RenderNoApply: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("updated %s (%s)", manifest.FileName, summary))
+				r.Blank()
+				r.Section("Suggestion")
+				r.Bullet("gion apply")
+			},
+			RenderNoChanges: func(r *ui.Renderer) {
+				r.Section("Result")
+				r.Bullet(fmt.Sprintf("updated %s (%s)", manifest.FileName, summary))
+				r.Bullet("no changes")
+			},
+			RenderInfoBeforeApply: func(r *ui.Renderer, _ manifestplan.Result, _ bool) {
+				r.Section("Info")
+				r.Bullet(fmt.Sprintf("manifest: updated %s (%s)", manifest.FileName, summary))
+				r.Bullet("apply: reconciling entire root (destructive removals require confirmation)")
+			},
+		},
+	})
+}
+
+// renderArchiveRepos lists the archived repos with what was saved for each.
+func renderArchiveRepos(r *ui.Renderer, archived archive.Archive) {
+	for _, repoEntry := range archived.Repos {
+		line := fmt.Sprintf("repo: %s (branch: %s)", repoEntry.Alias, repoEntry.Branch)
+		if repoEntry.Branch == "" {
+			line = fmt.Sprintf("repo: %s (ref: %s)", repoEntry.Alias, repoEntry.Ref)
+		}
+		var saved []string
+		if repoEntry.Bundle != "" {
+			saved = append(saved, "unpushed commits")
+		}
+		if repoEntry.Patch != "" {
+			saved = append(saved, "uncommitted changes")
+		}
+		if len(saved) > 0 {
+			line += " " + r.MutedText("["+strings.Join(saved, ", ")+"]")
+		}
+		r.Bullet(line)
+	}
+}
+
+// archiveReference returns how gion.yaml refers to the archive in dir: its name
+// when it lives under GION_ROOT/archive, its path otherwise.
+func archiveReference(rootDir, dir string) string {
+	if filepath.Dir(dir) == filepath.Clean(paths.ArchiveRoot(rootDir)) {
+		return filepath.Base(dir)
+	}
+	return dir
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+func runWorkspace(ctx context.Context, rootDir string, args []string, noPrompt bool) error {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		printWorkspaceHelp(os.Stdout)
+		return nil
+	}
+	switch args[0] {
+	case "archive":
+		return runWorkspaceArchive(ctx, rootDir, args[1:], noPrompt)
+	case "restore":
+		return runWorkspaceRestore(ctx, rootDir, args[1:], noPrompt)
+	case "rebase":
+		return runWorkspaceRebase(ctx, rootDir, args[1:])
+	default:
+		return fmt.Errorf("unknown workspace subcommand: %s", args[0])
+	}
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/tasuku43/gion/internal/app/rebase"
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/ui"
+)
+
+func runWorkspaceRebase(ctx context.Context, rootDir string, args []string) error {
+	rebaseFlags := flag.NewFlagSet("workspace rebase", flag.ContinueOnError)
+	var onto string
+	var noFetch bool
+	var helpFlag bool
+	rebaseFlags.StringVar(&onto, "onto", "", "new base ref (origin/<branch>)")
+	rebaseFlags.BoolVar(&noFetch, "no-fetch", false, "do not fetch base branches")
+	rebaseFlags.BoolVar(&helpFlag, "help", false, "show help")
+	rebaseFlags.BoolVar(&helpFlag, "h", false, "show help")
+	rebaseFlags.SetOutput(os.Stdout)
+	rebaseFlags.Usage = func() {
+		printWorkspaceRebaseHelp(os.Stdout)
+	}
+	if err := rebaseFlags.Parse(normalizeArgsFlagsFirst(args, map[string]struct{}{"--onto": {}, "-onto": {}})); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if helpFlag {
+		printWorkspaceRebaseHelp(os.Stdout)
+		return nil
+	}
+	if rebaseFlags.NArg() != 1 {
+		return fmt.Errorf("usage: gion workspace rebase <WORKSPACE_ID> [--onto <ref>] [--no-fetch]")
+	}
+	workspaceID := strings.TrimSpace(rebaseFlags.Arg(0))
+	onto = strings.TrimSpace(onto)
+
+	desired, err := manifest.Load(rootDir)
+	if err != nil {
+		return err
+	}
+	ws, ok := desired.Workspaces[workspaceID]
+	if !ok {
+		return fmt.Errorf("workspace not found in %s: %s", manifest.FileName, workspaceID)
+	}
+	if ws.Mode == workspace.MetadataModeReview {
+		return fmt.Errorf("workspace %s is a review workspace (use: gion review sync)", workspaceID)
+	}
+
+	theme := ui.DefaultTheme()
+	useColor := isatty.IsTerminal(os.Stdout.Fd())
+	renderer := ui.NewRenderer(os.Stdout, theme, useColor)
+
+	renderer.Section("Inputs")
+	renderer.Bullet(fmt.Sprintf("workspace: %s", workspaceID))
+	if onto != "" {
+		renderer.Bullet(fmt.Sprintf("onto: %s", onto))
+	}
+	renderer.Blank()
+
+	output.SetStepLogger(renderer)
+	defer output.SetStepLogger(nil)
+	startSteps(renderer)
+	output.Step(formatStep("rebase", workspaceID, ""))
+
+	result, rebaseErr := rebase.Rebase(ctx, rootDir, workspaceID, ws.Repos, rebase.Options{Onto: onto, NoFetch: noFetch})
+
+	// Keep gion.yaml in line with the branches that now sit on the new base.
+	var updated []string
+	if onto != "" {
+		landed := map[string]bool{}
+		for _, repoResult := range result.Repos {
+			if repoResult.Outcome == rebase.OutcomeRebased || repoResult.Outcome == rebase.OutcomeUpToDate {
+				landed[repoResult.Alias] = true
+			}
+		}
+		repos := make([]manifest.Repo, len(ws.Repos))
+		for i, repoEntry := range ws.Repos {
+			if landed[repoEntry.Alias] && repoEntry.BaseRef != onto {
+				repoEntry.BaseRef = onto
+				updated = append(updated, repoEntry.Alias)
+			}
+			repos[i] = repoEntry
+		}
+		if len(updated) > 0 {
+			ws.Repos = repos
+			desired.Workspaces[workspaceID] = ws
+			if err := manifest.Save(rootDir, desired); err != nil {
+				return errors.Join(rebaseErr, err)
+			}
+		}
+	}
+
+	renderer.Blank()
+	renderer.Section("Result")
+	renderWorkspaceRebaseResult(renderer, result)
+	if len(updated) > 0 {
+		renderer.Bullet(fmt.Sprintf("updated %s (base_ref: %s for %s)", manifest.FileName, onto, strings.Join(updated, ", ")))
+	}
+	if rebaseErr != nil {
+		return rebaseErr
+	}
+	if result.Conflicted() {
+		renderer.Blank()
+		renderer.Section("Suggestion")
+		for _, repoResult := range result.Repos {
+			if repoResult.Outcome == rebase.OutcomeConflict {
+				renderer.Bullet(fmt.Sprintf("cd %s && %s (resolve conflicts manually)", repoResult.WorktreePath, rebaseCommand(repoResult)))
+			}
+		}
+		return fmt.Errorf("rebase stopped on a conflict in %s", workspaceID)
+	}
+	return nil
+}
+
+// rebaseCommand is the git command that redoes the aborted rebase of a repo,
+// keeping --onto so the commits of the previous base are not replayed.
+func rebaseCommand(repoResult rebase.RepoResult) string {
+	if repoResult.Upstream != "" {
+		return fmt.Sprintf("git rebase --onto %s %s", repoResult.Base, repoResult.Upstream)
+	}
+	return fmt.Sprintf("git rebase %s", repoResult.Base)
+}
+
+func renderWorkspaceRebaseResult(r *ui.Renderer, result rebase.Result) {
+	for _, repoResult := range result.Repos {
+		label := r.MutedText(fmt.Sprintf("[%s]", repoResult.Outcome))
+		switch repoResult.Outcome {
+		case rebase.OutcomeRebased:
+			r.BulletSuccess(fmt.Sprintf("%s %s onto %s %s", repoResult.Alias, label, repoResult.Base, r.MutedText(shortSHA(repoResult.Before)+".."+shortSHA(repoResult.After))))
+		case rebase.OutcomeUpToDate:
+			r.Bullet(fmt.Sprintf("%s %s on %s", repoResult.Alias, label, repoResult.Base))
+		case rebase.OutcomeConflict:
+			r.BulletError(fmt.Sprintf("%s %s onto %s (rebase aborted, worktree unchanged)", repoResult.Alias, r.ErrorText(fmt.Sprintf("[%s]", repoResult.Outcome)), repoResult.Base))
+			renderTreeLines(r, repoResult.Conflicts, treeLineError)
+		case rebase.OutcomeDirty:
+			r.BulletWarn(fmt.Sprintf("%s %s uncommitted changes, not touched", repoResult.Alias, label))
+		default:
+			r.Bullet(fmt.Sprintf("%s %s", repoResult.Alias, label))
+		}
+	}
+	if result.BaseBranch != "" {
+		r.Bullet(fmt.Sprintf("base_branch: %s", result.BaseBranch))
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/tasuku43/gion/internal/app/rebase"
+)
+
+func TestRebaseCommand(t *testing.T) {
+	cases := []struct {
+		name   string
+		result rebase.RepoResult
+		want   string
+	}{
+		{name: "plain", result: rebase.RepoResult{Base: "origin/main"}, want: "git rebase origin/main"},
+		{name: "onto", result: rebase.RepoResult{Base: "origin/release", Upstream: "origin/main"}, want: "git rebase --onto origin/release origin/main"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rebaseCommand(tc.result); got != tc.want {
+				t.Fatalf("rebaseCommand() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
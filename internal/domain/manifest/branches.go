@@ -0,0 +1,122 @@
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// Branches holds the branch naming settings of `gion manifest add`.
+type Branches struct {
+	Templates BranchTemplates `yaml:"templates,omitempty"`
+	// Pattern is a regular expression every workspace branch must match
+	// (checked by `gion manifest validate`).
+	Pattern string `yaml:"pattern,omitempty"`
+}
+
+// BranchTemplates are the default branch-name templates per `manifest add` mode
+// (see workspace.ExpandBranchTemplate). Empty templates keep the built-in defaults.
+type BranchTemplates struct {
+	Repo   string `yaml:"repo,omitempty"`
+	Issue  string `yaml:"issue,omitempty"`
+	Preset string `yaml:"preset,omitempty"`
+}
+
+// IsZero lets `branches:` be omitted when nothing is configured.
+func (b Branches) IsZero() bool {
+	return b.Templates == BranchTemplates{} && strings.TrimSpace(b.Pattern) == ""
+}
+
+// MatchBranch checks branch against the branch-name pattern, if one is set.
+func (b Branches) MatchBranch(branch string) error {
+	pattern := strings.TrimSpace(b.Pattern)
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid branches.pattern: %w", err)
+	}
+	if !re.MatchString(branch) {
+		return fmt.Errorf("branch %s does not match branches.pattern %s", branch, pattern)
+	}
+	return nil
+}
+
+// validateBranches checks the branches settings. Only gion.yaml may declare them.
+func validateBranches(root *yaml.Node, allowed bool) []ValidationIssue {
+	node := mappingValue(root, "branches")
+	if node == nil {
+		return nil
+	}
+	if !allowed {
+		return []ValidationIssue{{Ref: "branches", Message: fmt.Sprintf("only allowed in %s", FileName)}}
+	}
+	if node.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: "branches", Message: "invalid value (must be a mapping)"}}
+	}
+	var issues []ValidationIssue
+	if templates := mappingValue(node, "templates"); templates != nil {
+		if templates.Kind != yaml.MappingNode {
+			issues = append(issues, ValidationIssue{Ref: "branches.templates", Message: "invalid value (must be a mapping)"})
+		} else {
+			for _, mode := range []string{"repo", "issue", "preset"} {
+				valueNode := mappingValue(templates, mode)
+				if valueNode == nil {
+					continue
+				}
+				if err := workspace.ValidateBranchTemplate(scalarValue(valueNode)); err != nil {
+					issues = append(issues, ValidationIssue{Ref: "branches.templates." + mode, Message: err.Error()})
+				}
+			}
+		}
+	}
+	if patternNode := mappingValue(node, "pattern"); patternNode != nil {
+		pattern := strings.TrimSpace(scalarValue(patternNode))
+		if pattern == "" {
+			issues = append(issues, ValidationIssue{Ref: "branches.pattern", Message: "invalid value (must not be empty)"})
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			issues = append(issues, ValidationIssue{Ref: "branches.pattern", Message: fmt.Sprintf("invalid regular expression (%s)", err)})
+		}
+	}
+	return issues
+}
+
+// branchPatternIssues checks workspace branches in every file against the
+// branches.pattern of gion.yaml. Review workspaces track branches named by the
+// pull request author and are not checked.
+func branchPatternIssues(docs []validationDoc) []ValidationIssue {
+	if len(docs) == 0 {
+		return nil
+	}
+	pattern := strings.TrimSpace(scalarValue(mappingValue(mappingValue(docs[0].root, "branches"), "pattern")))
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	var issues []ValidationIssue
+	for _, d := range docs {
+		forEachMappingEntry(mappingValue(d.root, "workspaces"), func(id string, ws *yaml.Node) {
+			if strings.TrimSpace(scalarValue(mappingValue(ws, "mode"))) == workspace.MetadataModeReview {
+				return
+			}
+			forEachSequenceItem(mappingValue(ws, "repos"), func(i int, entry *yaml.Node) {
+				branch := strings.TrimSpace(scalarValue(mappingValue(entry, "branch")))
+				if branch == "" || re.MatchString(branch) {
+					return
+				}
+				issues = append(issues, ValidationIssue{
+					Ref:     d.ref(fmt.Sprintf("workspaces.%s.repos[%d].branch", id, i)),
+					Message: fmt.Sprintf("does not match branches.pattern (%s)", pattern),
+				})
+			})
+		})
+	}
+	return issues
+}
@@ -0,0 +1,101 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadSave_Branches(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+branches:
+  templates:
+    issue: feature/{user}/{issue}-{slug}
+  pattern: ^(feature|fix)/
+workspaces: {}
+`,
+		"team.yaml": "workspaces: {}\n",
+	})
+
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if file.Branches.Templates.Issue != "feature/{user}/{issue}-{slug}" || file.Branches.Pattern != "^(feature|fix)/" {
+		t.Fatalf("unexpected branches: %+v", file.Branches)
+	}
+	if err := file.Branches.MatchBranch("feature/alice/1-x"); err != nil {
+		t.Fatalf("MatchBranch: %v", err)
+	}
+	if err := file.Branches.MatchBranch("WS-1"); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("expected mismatch, got %v", err)
+	}
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	root, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(root), "issue: feature/{user}/{issue}-{slug}") {
+		t.Fatalf("branches not saved:\n%s", root)
+	}
+}
+
+func TestValidate_Branches(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+branches:
+  templates:
+    repo: feature/{ticket}
+  pattern: ^(feature|fix)/
+workspaces:
+  WS-1:
+    repos:
+      - alias: api
+        repo_key: github.com/org/api.git
+        branch: feature/WS-1
+      - alias: web
+        repo_key: github.com/org/web.git
+        branch: WS-1
+      - alias: docs
+        repo_key: github.com/org/docs.git
+        ref: v1.0.0
+  PR-1:
+    mode: review
+    repos:
+      - alias: api
+        repo_key: github.com/org/api.git
+        branch: someone-elses-branch
+`,
+		"team.yaml": `branches:
+  pattern: .*
+workspaces:
+  WS-2:
+    repos:
+      - alias: api
+        repo_key: github.com/org/api.git
+        branch: main-fix
+`,
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "branches.templates.repo,team.yaml:branches,workspaces.WS-1.repos[1].branch,team.yaml:workspaces.WS-2.repos[0].branch"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
@@ -0,0 +1,100 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// CarryRule is a `carry:` entry: untracked files brought into new worktrees of a repo.
+type CarryRule struct {
+	// Files are globs relative to the source (`**` matches any number of directories).
+	Files []string `yaml:"files"`
+	// Mode is copy (default) or symlink.
+	Mode string `yaml:"mode,omitempty"`
+	// FromWorkspace takes the files from the repo's worktree in that workspace
+	// instead of <root>/templates/<host>/<owner>/<repo>.
+	FromWorkspace string `yaml:"from_workspace,omitempty"`
+}
+
+// CarryRules returns the carry rules of a repo key. Keys match with or without
+// the .git suffix.
+func (f File) CarryRules(repoKey string) []workspace.CarryRule {
+	want := strings.TrimSuffix(strings.TrimSpace(repoKey), ".git")
+	var rules []workspace.CarryRule
+	for _, key := range sortedKeys(f.Carry) {
+		if strings.TrimSuffix(strings.TrimSpace(key), ".git") != want {
+			continue
+		}
+		for _, rule := range f.Carry[key] {
+			rules = append(rules, workspace.CarryRule{
+				Patterns:        rule.Files,
+				Mode:            strings.TrimSpace(rule.Mode),
+				SourceWorkspace: strings.TrimSpace(rule.FromWorkspace),
+			})
+		}
+	}
+	return rules
+}
+
+// validateCarry checks the top-level carry section. Only gion.yaml may declare it.
+func validateCarry(ctx context.Context, root *yaml.Node, allowed bool) []ValidationIssue {
+	node := mappingValue(root, "carry")
+	if node == nil {
+		return nil
+	}
+	if !allowed {
+		return []ValidationIssue{{Ref: "carry", Message: fmt.Sprintf("only allowed in %s", FileName)}}
+	}
+	if node.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: "carry", Message: "invalid value (must be a mapping)"}}
+	}
+	var issues []ValidationIssue
+	forEachMappingEntry(node, func(repoKey string, value *yaml.Node) {
+		ref := "carry." + repoKey
+		if err := validateRepoKey(repoKey); err != nil {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: err.Error()})
+		}
+		if value == nil || value.Kind != yaml.SequenceNode {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: "invalid value (must be a list)"})
+			return
+		}
+		for i, entry := range value.Content {
+			issues = append(issues, validateCarryRule(ctx, fmt.Sprintf("%s[%d]", ref, i), entry)...)
+		}
+	})
+	return issues
+}
+
+func validateCarryRule(ctx context.Context, ref string, node *yaml.Node) []ValidationIssue {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: ref, Message: "invalid value (must be a mapping)"}}
+	}
+	var rule CarryRule
+	if err := node.Decode(&rule); err != nil {
+		return []ValidationIssue{{Ref: ref, Message: err.Error()}}
+	}
+	var issues []ValidationIssue
+	if len(rule.Files) == 0 {
+		issues = append(issues, ValidationIssue{Ref: ref + ".files", Message: "missing required field"})
+	}
+	for i, pattern := range rule.Files {
+		if err := workspace.ValidateCarryPattern(pattern); err != nil {
+			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("%s.files[%d]", ref, i), Message: err.Error()})
+		}
+	}
+	switch strings.TrimSpace(rule.Mode) {
+	case "", workspace.CarryModeCopy, workspace.CarryModeSymlink:
+	default:
+		issues = append(issues, ValidationIssue{Ref: ref + ".mode", Message: fmt.Sprintf("invalid mode %q (must be %s or %s)", rule.Mode, workspace.CarryModeCopy, workspace.CarryModeSymlink)})
+	}
+	if source := strings.TrimSpace(rule.FromWorkspace); source != "" {
+		if err := workspace.ValidateWorkspaceID(ctx, source); err != nil {
+			issues = append(issues, ValidationIssue{Ref: ref + ".from_workspace", Message: err.Error()})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,77 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLoad_CarryRules(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+carry:
+  github.com/org/web:
+    - files: [.env, .vscode]
+  github.com/org/web.git:
+    - files: [node_modules]
+      mode: symlink
+      from_workspace: MAIN
+workspaces: {}
+`,
+	})
+
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	rules := file.CarryRules("github.com/org/web.git")
+	if len(rules) != 2 {
+		t.Fatalf("rules = %+v", rules)
+	}
+	if strings.Join(rules[0].Patterns, ",") != ".env,.vscode" || rules[0].Mode != "" {
+		t.Fatalf("unexpected first rule: %+v", rules[0])
+	}
+	if rules[1].Mode != "symlink" || rules[1].SourceWorkspace != "MAIN" {
+		t.Fatalf("unexpected second rule: %+v", rules[1])
+	}
+	if got := file.CarryRules("github.com/org/api.git"); len(got) != 0 {
+		t.Fatalf("unexpected rules for api: %+v", got)
+	}
+}
+
+func TestValidate_Carry(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+carry:
+  github.com/org/web:
+    - files: [.env, ../secrets]
+    - mode: hardlink
+  web:
+    - files: [.env]
+      from_workspace: a/b
+workspaces: {}
+`,
+		"team.yaml": `carry:
+  github.com/org/api:
+    - files: [.env]
+workspaces: {}
+`,
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "carry.github.com/org/web[0].files[1],carry.github.com/org/web[1].files,carry.github.com/org/web[1].mode,carry.web,carry.web[0].from_workspace,team.yaml:carry"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
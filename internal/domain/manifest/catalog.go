@@ -0,0 +1,298 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/infra/paths"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultCatalogPath is the file read from a catalog repository when its entry has no path.
+const DefaultCatalogPath = "presets.yaml"
+
+// PresetCatalog subscribes gion.yaml to the presets published in a git repository.
+// `gion manifest preset sync` copies them under <root>/catalogs; they are read-only locally.
+type PresetCatalog struct {
+	Repo string `yaml:"repo"`
+	// Ref is the branch, tag or commit to read. Empty means the default branch.
+	Ref string `yaml:"ref,omitempty"`
+	// Path is the file in the repository holding a `presets:` mapping (DefaultCatalogPath when empty).
+	Path string `yaml:"path,omitempty"`
+}
+
+// FilePath returns the catalog file path inside the repository.
+func (c PresetCatalog) FilePath() string {
+	if p := strings.TrimSpace(c.Path); p != "" {
+		return p
+	}
+	return DefaultCatalogPath
+}
+
+// PresetCatalog returns the repo of the catalog that provides the preset, or
+// false when the preset is defined locally.
+func (f File) PresetCatalog(name string) (string, bool) {
+	if f.sources == nil {
+		return "", false
+	}
+	idx, ok := f.sources.owner[entryKey(entryPreset, name)]
+	if !ok {
+		return "", false
+	}
+	catalog := f.sources.files[idx].catalog
+	return catalog, catalog != ""
+}
+
+// CatalogFiles returns the absolute paths of the synced catalog files gion.yaml subscribes to.
+func CatalogFiles(rootDir string) ([]string, error) {
+	file, err := Load(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if file.sources == nil {
+		return nil, nil
+	}
+	var paths []string
+	for _, src := range file.sources.files[1:] {
+		if src.catalog != "" {
+			paths = append(paths, src.path)
+		}
+	}
+	return paths, nil
+}
+
+// CatalogCachePath returns where the synced presets of a catalog repo are stored.
+func CatalogCachePath(rootDir, repoSpec string) (string, error) {
+	spec, _, err := repo.Normalize(repoSpec)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(paths.CatalogsRoot(rootDir), filepath.FromSlash(spec.RepoKey)+".yaml"), nil
+}
+
+// ParseCatalog reads the presets of a catalog file. Other sections are ignored.
+func ParseCatalog(name string, data []byte) (map[string]Preset, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	root := unwrapDocument(&doc)
+	version, issues := validateVersion(root)
+	if len(issues) == 0 {
+		issues = validatePresets(root, version)
+	}
+	if len(issues) > 0 {
+		return nil, fmt.Errorf("%s: %s: %s", name, issues[0].Ref, issues[0].Message)
+	}
+	file, err := parse(name, data)
+	if err != nil {
+		return nil, err
+	}
+	// Hooks run shell commands; only the local gion.yaml may declare them.
+	for _, presetName := range sortedKeys(file.Presets) {
+		if !file.Presets[presetName].Hooks.IsZero() {
+			return nil, fmt.Errorf("%s: presets.%s.hooks: not allowed in preset catalogs", name, presetName)
+		}
+	}
+	return file.Presets, nil
+}
+
+// MarshalPresets encodes presets as a `presets:` mapping.
+func MarshalPresets(presets map[string]Preset) ([]byte, error) {
+	if presets == nil {
+		presets = map[string]Preset{}
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(struct {
+		Presets map[string]Preset `yaml:"presets"`
+	}{presets}); err != nil {
+		_ = enc.Close()
+		return nil, fmt.Errorf("marshal presets: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("close presets encoder: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalCatalog encodes the synced presets of a catalog as stored under <root>/catalogs.
+func MarshalCatalog(catalog PresetCatalog, commit string, presets map[string]Preset) ([]byte, error) {
+	body, err := marshal(File{Version: CurrentVersion, Presets: presets}, true)
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("# Synced by `gion manifest preset sync` from %s (%s at %s).\n# Do not edit: changes are overwritten by the next sync.\n", catalog.Repo, catalog.FilePath(), commit)
+	return append([]byte(header), body...), nil
+}
+
+// loadCatalogs merges the synced presets of every catalog of root into merged.
+// Catalogs that have not been synced yet contribute nothing.
+func loadCatalogs(rootDir string, root File, merged *File, set *sourceSet) error {
+	visited := map[string]struct{}{}
+	for _, catalog := range root.PresetCatalogs {
+		cachePath, err := CatalogCachePath(rootDir, catalog.Repo)
+		if err != nil {
+			return fmt.Errorf("%s: preset_catalogs: %w", FileName, err)
+		}
+		if _, ok := visited[cachePath]; ok {
+			continue
+		}
+		visited[cachePath] = struct{}{}
+		name := displayIncludePath(rootDir, cachePath)
+		data, err := os.ReadFile(cachePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+		presets, err := ParseCatalog(name, data)
+		if err != nil {
+			return err
+		}
+		idx := len(set.files)
+		set.files = append(set.files, sourceFile{path: cachePath, name: name, catalog: catalog.Repo})
+		if err := mergeFragment(merged, set, idx, File{Presets: presets}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadCatalogDocs returns the synced catalog files of gion.yaml for Validate.
+func loadCatalogDocs(rootDir string, rootDoc validationDoc) ([]validationDoc, []ValidationIssue) {
+	var docs []validationDoc
+	var issues []ValidationIssue
+	visited := map[string]struct{}{}
+	forEachSequenceItem(mappingValue(rootDoc.root, "preset_catalogs"), func(i int, entry *yaml.Node) {
+		repoSpec := strings.TrimSpace(scalarValue(mappingValue(entry, "repo")))
+		if repoSpec == "" {
+			return
+		}
+		cachePath, err := CatalogCachePath(rootDir, repoSpec)
+		if err != nil {
+			return
+		}
+		if _, ok := visited[cachePath]; ok {
+			return
+		}
+		visited[cachePath] = struct{}{}
+		doc := validationDoc{path: cachePath, name: displayIncludePath(rootDir, cachePath), included: true}
+		data, err := os.ReadFile(cachePath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("preset_catalogs[%d]", i), Message: "not synced (run: gion manifest preset sync)"})
+			} else {
+				issues = append(issues, ValidationIssue{Ref: doc.name, Message: err.Error()})
+			}
+			return
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			issues = append(issues, ValidationIssue{Ref: doc.name, Message: fmt.Sprintf("invalid yaml (%s)", strings.TrimSpace(err.Error()))})
+			return
+		}
+		doc.root = unwrapDocument(&node)
+		version, versionIssues := validateVersion(doc.root)
+		if len(versionIssues) > 0 {
+			issues = append(issues, doc.refIssues(versionIssues)...)
+			return
+		}
+		doc.version = version
+		docs = append(docs, doc)
+	})
+	return docs, issues
+}
+
+// validatePresetCatalogs checks the preset_catalogs list. Only gion.yaml may declare catalogs.
+func validatePresetCatalogs(root *yaml.Node, allowed bool) []ValidationIssue {
+	node := mappingValue(root, "preset_catalogs")
+	if node == nil {
+		return nil
+	}
+	if !allowed {
+		return []ValidationIssue{{Ref: "preset_catalogs", Message: fmt.Sprintf("only allowed in %s", FileName)}}
+	}
+	if node.Kind != yaml.SequenceNode {
+		return []ValidationIssue{{Ref: "preset_catalogs", Message: "invalid value (must be a list)"}}
+	}
+	var issues []ValidationIssue
+	seen := map[string]int{}
+	for i, entry := range node.Content {
+		refPrefix := fmt.Sprintf("preset_catalogs[%d]", i)
+		if entry == nil || entry.Kind != yaml.MappingNode {
+			issues = append(issues, ValidationIssue{Ref: refPrefix, Message: "invalid value (catalog entry must be a mapping)"})
+			continue
+		}
+		repoSpec := strings.TrimSpace(scalarValue(mappingValue(entry, "repo")))
+		if repoSpec == "" {
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".repo", Message: "missing required field"})
+		} else if spec, _, err := repo.Normalize(repoSpec); err != nil {
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".repo", Message: err.Error()})
+		} else if prev, ok := seen[spec.RepoKey]; ok {
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".repo", Message: fmt.Sprintf("duplicate catalog (already subscribed in preset_catalogs[%d])", prev)})
+		} else {
+			seen[spec.RepoKey] = i
+		}
+		if refNode := mappingValue(entry, "ref"); refNode != nil {
+			if err := validateCatalogRef(scalarValue(refNode)); err != nil {
+				issues = append(issues, ValidationIssue{Ref: refPrefix + ".ref", Message: err.Error()})
+			}
+		}
+		if pathNode := mappingValue(entry, "path"); pathNode != nil {
+			if err := validateCatalogFilePath(scalarValue(pathNode)); err != nil {
+				issues = append(issues, ValidationIssue{Ref: refPrefix + ".path", Message: err.Error()})
+			}
+		}
+	}
+	return issues
+}
+
+// ValidatePresetCatalog checks a single catalog entry before it is added to gion.yaml.
+func ValidatePresetCatalog(catalog PresetCatalog) error {
+	if _, _, err := repo.Normalize(catalog.Repo); err != nil {
+		return err
+	}
+	if catalog.Ref != "" {
+		if err := validateCatalogRef(catalog.Ref); err != nil {
+			return fmt.Errorf("ref: %w", err)
+		}
+	}
+	if catalog.Path != "" {
+		if err := validateCatalogFilePath(catalog.Path); err != nil {
+			return fmt.Errorf("path: %w", err)
+		}
+	}
+	return nil
+}
+
+func validateCatalogRef(value string) error {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" || strings.HasPrefix(trimmed, "-") || strings.ContainsAny(trimmed, " \t:") {
+		return fmt.Errorf("invalid value: %q", value)
+	}
+	return nil
+}
+
+func validateCatalogFilePath(value string) error {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return fmt.Errorf("invalid value (must not be empty)")
+	}
+	if path.IsAbs(trimmed) || strings.Contains(trimmed, "\\") {
+		return fmt.Errorf("invalid value (must be a relative path in the repository): %s", trimmed)
+	}
+	for _, part := range strings.Split(trimmed, "/") {
+		if part == ".." {
+			return fmt.Errorf("invalid value (must be a relative path in the repository): %s", trimmed)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadSave_PresetCatalogs(t *testing.T) {
+	rootDir := t.TempDir()
+	const catalogRepo = "git@github.com:org/presets.git"
+	cachePath, err := CatalogCachePath(rootDir, catalogRepo)
+	if err != nil {
+		t.Fatalf("CatalogCachePath: %v", err)
+	}
+	cache, err := MarshalCatalog(PresetCatalog{Repo: catalogRepo}, "abc1234", map[string]Preset{
+		"team": {Repos: []PresetRepo{{Repo: "git@github.com:org/api.git"}}},
+	})
+	if err != nil {
+		t.Fatalf("MarshalCatalog: %v", err)
+	}
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+preset_catalogs:
+  - repo: git@github.com:org/presets.git
+presets:
+  mine:
+    repos: [git@github.com:org/web.git]
+workspaces: {}
+`,
+		strings.TrimPrefix(cachePath, rootDir+string(os.PathSeparator)): string(cache),
+	})
+
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, ok := file.PresetCatalog("team"); !ok || got != catalogRepo {
+		t.Fatalf("PresetCatalog(team) = %q, %v", got, ok)
+	}
+	if _, ok := file.PresetCatalog("mine"); ok {
+		t.Fatalf("expected mine to be a local preset")
+	}
+
+	file.Presets["other"] = Preset{Repos: []PresetRepo{{Repo: "git@github.com:org/other.git"}}}
+	delete(file.Presets, "team")
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	root, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if strings.Contains(string(root), "team") || !strings.Contains(string(root), "other") || !strings.Contains(string(root), "preset_catalogs") {
+		t.Fatalf("unexpected %s:\n%s", FileName, root)
+	}
+	if data, err := os.ReadFile(cachePath); err != nil || string(data) != string(cache) {
+		t.Fatalf("catalog file was rewritten (%v):\n%s", err, data)
+	}
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(result.Issues) != 0 {
+		t.Fatalf("unexpected issues: %+v", result.Issues)
+	}
+}
+
+func TestValidate_PresetCatalogs(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+preset_catalogs:
+  - repo: git@github.com:org/presets.git
+  - repo: https://github.com/org/presets
+  - repo: git@github.com:org/more.git
+    path: ../presets.yaml
+workspaces:
+  WS-1:
+    mode: preset
+    preset_name: team
+    repos: []
+`,
+		"team.yaml": "preset_catalogs:\n  - repo: git@github.com:org/x.git\n",
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "preset_catalogs[0],preset_catalogs[2],preset_catalogs[1].repo,preset_catalogs[2].path,team.yaml:preset_catalogs"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
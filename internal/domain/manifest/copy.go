@@ -0,0 +1,36 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// validateCopyFields checks copied_from and copy_changes of a workspace entry.
+func validateCopyFields(ctx context.Context, workspaceID string, node *yaml.Node) []ValidationIssue {
+	var issues []ValidationIssue
+	ref := fmt.Sprintf("workspaces.%s.copied_from", workspaceID)
+	from := strings.TrimSpace(scalarValue(mappingValue(node, "copied_from")))
+	if from != "" {
+		if err := workspace.ValidateWorkspaceID(ctx, from); err != nil {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: err.Error()})
+		} else if from == workspaceID {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: "a workspace cannot be copied from itself"})
+		}
+		if strings.TrimSpace(scalarValue(mappingValue(node, "renamed_from"))) != "" {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: "cannot be combined with renamed_from"})
+		}
+	}
+	if changes := mappingValue(node, "copy_changes"); changes != nil {
+		var value bool
+		if err := changes.Decode(&value); err != nil {
+			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("workspaces.%s.copy_changes", workspaceID), Message: "invalid value (must be a boolean)"})
+		} else if value && from == "" {
+			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("workspaces.%s.copy_changes", workspaceID), Message: "requires copied_from"})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,46 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidate_CopyFields(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+workspaces:
+  WS-1:
+    repos: []
+  WS-2:
+    copied_from: WS-1
+    copy_changes: true
+    repos: []
+  WS-3:
+    copied_from: WS-3
+    repos: []
+  WS-4:
+    copy_changes: true
+    repos: []
+  WS-5:
+    copied_from: WS-1
+    renamed_from: WS-0
+    repos: []
+`,
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "workspaces.WS-3.copied_from,workspaces.WS-4.copy_changes,workspaces.WS-5.copied_from"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
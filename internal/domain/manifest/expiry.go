@@ -0,0 +1,38 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseExpiresAt parses a workspace expires_at value (RFC 3339).
+func ParseExpiresAt(value string) (time.Time, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return time.Time{}, fmt.Errorf("expires_at is required")
+	}
+	t, err := time.Parse(time.RFC3339, trimmed)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp (must be RFC 3339, e.g. 2026-01-02T15:04:05Z): %s", trimmed)
+	}
+	return t, nil
+}
+
+// FormatExpiresAt renders an expiry timestamp as stored in gion.yaml.
+func FormatExpiresAt(t time.Time) string {
+	return t.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
+
+// Expired reports whether the workspace has an expires_at at or before now.
+// Workspaces without (or with an unparsable) expires_at never expire.
+func (w Workspace) Expired(now time.Time) bool {
+	if strings.TrimSpace(w.ExpiresAt) == "" {
+		return false
+	}
+	expiresAt, err := ParseExpiresAt(w.ExpiresAt)
+	if err != nil {
+		return false
+	}
+	return !now.Before(expiresAt)
+}
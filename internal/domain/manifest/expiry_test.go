@@ -0,0 +1,56 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		expiresAt string
+		want      bool
+	}{
+		{"", false},
+		{"2026-03-01T11:59:59Z", true},
+		{"2026-03-01T12:00:00Z", true},
+		{"2026-03-01T21:00:00+09:00", true},
+		{"2026-03-02T00:00:00Z", false},
+		{"tomorrow", false},
+	}
+	for _, tc := range cases {
+		if got := (Workspace{ExpiresAt: tc.expiresAt}).Expired(now); got != tc.want {
+			t.Fatalf("Expired(%q) = %v, want %v", tc.expiresAt, got, tc.want)
+		}
+	}
+	if got := FormatExpiresAt(time.Date(2026, 3, 1, 21, 0, 0, 500, time.FixedZone("JST", 9*3600))); got != "2026-03-01T12:00:00Z" {
+		t.Fatalf("FormatExpiresAt = %s", got)
+	}
+}
+
+func TestValidate_ExpiresAt(t *testing.T) {
+	rootDir := t.TempDir()
+	content := `
+version: 1
+workspaces:
+  WS-1:
+    expires_at: "2026-03-01T12:00:00Z"
+    repos: []
+  WS-2:
+    expires_at: "72h"
+    repos: []
+`
+	if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Ref != "workspaces.WS-2.expires_at" {
+		t.Fatalf("unexpected issues: %+v", result.Issues)
+	}
+}
@@ -0,0 +1,188 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"gopkg.in/yaml.v3"
+)
+
+// ExpandedRepo is a repo of an expanded preset.
+type ExpandedRepo struct {
+	PresetRepo
+	// From is the preset that declares the entry.
+	From string
+}
+
+// PresetRepoAlias returns the directory name of a preset repo: its alias, or the repo name.
+func PresetRepoAlias(r PresetRepo) string {
+	if alias := strings.TrimSpace(r.Alias); alias != "" {
+		return alias
+	}
+	spec, _, err := repo.Normalize(r.Repo)
+	if err != nil {
+		return strings.TrimSpace(r.Repo)
+	}
+	return spec.Repo
+}
+
+// ExpandPreset returns the repos of a preset: the repos of the presets it extends,
+// in extends order, followed by its own. An entry replaces an inherited entry with
+// the same alias in place, so the closest preset decides a repo's defaults.
+func ExpandPreset(presets map[string]Preset, name string) ([]ExpandedRepo, error) {
+	var out []ExpandedRepo
+	index := map[string]int{}
+	visited := map[string]struct{}{}
+	var visit func(name string, stack []string) error
+	visit = func(name string, stack []string) error {
+		for i, prev := range stack {
+			if prev == name {
+				return fmt.Errorf("preset cycle: %s", strings.Join(append(stack[i:], name), " -> "))
+			}
+		}
+		p, ok := presets[name]
+		if !ok {
+			return fmt.Errorf("preset not found: %s", name)
+		}
+		stack = append(stack, name)
+		for _, parent := range p.Extends {
+			parent = strings.TrimSpace(parent)
+			if _, ok := presets[parent]; !ok {
+				return fmt.Errorf("preset %s extends unknown preset %s", name, parent)
+			}
+			if err := visit(parent, stack); err != nil {
+				return err
+			}
+		}
+		if _, ok := visited[name]; ok {
+			return nil
+		}
+		visited[name] = struct{}{}
+		for _, r := range p.Repos {
+			entry := ExpandedRepo{PresetRepo: r, From: name}
+			alias := PresetRepoAlias(r)
+			if i, ok := index[alias]; ok {
+				out[i] = entry
+				continue
+			}
+			index[alias] = len(out)
+			out = append(out, entry)
+		}
+		return nil
+	}
+	if err := visit(name, nil); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResolvePreset returns the preset with the repos of the presets it extends merged in.
+func (f File) ResolvePreset(name string) (Preset, error) {
+	expanded, err := ExpandPreset(f.Presets, name)
+	if err != nil {
+		return Preset{}, err
+	}
+	resolved := Preset{Repos: make([]PresetRepo, 0, len(expanded))}
+	for _, r := range expanded {
+		resolved.Repos = append(resolved.Repos, r.PresetRepo)
+	}
+	return resolved, nil
+}
+
+// PresetExtendsIssue is a problem with the extends list of a preset.
+type PresetExtendsIssue struct {
+	Preset  string
+	Message string
+	// Cycle is the extends path back to Preset when the issue is a cycle.
+	Cycle []string
+}
+
+// ValidatePresetExtends reports extends entries that name unknown presets and
+// extends cycles. A cycle is reported once, on its first preset by name.
+func ValidatePresetExtends(presets map[string]Preset) []PresetExtendsIssue {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []PresetExtendsIssue
+	reported := map[string]struct{}{}
+	for _, name := range names {
+		for _, parent := range presets[name].Extends {
+			parent = strings.TrimSpace(parent)
+			if _, ok := presets[parent]; !ok {
+				issues = append(issues, PresetExtendsIssue{Preset: name, Message: fmt.Sprintf("preset not found: %s", parent)})
+			}
+		}
+	}
+	for _, name := range names {
+		cycle := presetCycle(presets, name)
+		if len(cycle) == 0 {
+			continue
+		}
+		members := append([]string(nil), cycle[:len(cycle)-1]...)
+		sort.Strings(members)
+		key := strings.Join(members, "\x00")
+		if _, ok := reported[key]; ok {
+			continue
+		}
+		reported[key] = struct{}{}
+		issues = append(issues, PresetExtendsIssue{Preset: name, Message: fmt.Sprintf("preset cycle: %s", strings.Join(cycle, " -> ")), Cycle: cycle})
+	}
+	return issues
+}
+
+// presetCycle returns a path from name back to name through extends, if any.
+func presetCycle(presets map[string]Preset, name string) []string {
+	visited := map[string]struct{}{}
+	var walk func(current string, path []string) []string
+	walk = func(current string, path []string) []string {
+		for _, parent := range presets[current].Extends {
+			parent = strings.TrimSpace(parent)
+			if parent == name {
+				return append(append([]string(nil), path...), parent)
+			}
+			if _, ok := presets[parent]; !ok {
+				continue
+			}
+			if _, ok := visited[parent]; ok {
+				continue
+			}
+			visited[parent] = struct{}{}
+			if cycle := walk(parent, append(path, parent)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk(name, []string{name})
+}
+
+// presetExtendsIssues checks extends across every file, since a preset may extend
+// a preset declared in an included file or a synced catalog.
+func presetExtendsIssues(docs []validationDoc) []ValidationIssue {
+	presets := map[string]Preset{}
+	owner := map[string]int{}
+	for i, d := range docs {
+		forEachMappingEntry(mappingValue(d.root, "presets"), func(name string, node *yaml.Node) {
+			if _, ok := owner[name]; ok {
+				return
+			}
+			var p Preset
+			if err := node.Decode(&p); err != nil {
+				return
+			}
+			presets[name] = p
+			owner[name] = i
+		})
+	}
+	var issues []ValidationIssue
+	for _, issue := range ValidatePresetExtends(presets) {
+		d := docs[owner[issue.Preset]]
+		issues = append(issues, ValidationIssue{Ref: d.ref(fmt.Sprintf("presets.%s.extends", issue.Preset)), Message: issue.Message})
+	}
+	return issues
+}
@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExpandPreset(t *testing.T) {
+	presets := map[string]Preset{
+		"base": {Repos: []PresetRepo{{Repo: "git@github.com:org/api.git"}, {Repo: "git@github.com:org/web.git"}}},
+		"ops":  {Extends: []string{"base"}, Repos: []PresetRepo{{Repo: "git@github.com:org/infra.git"}}},
+		"full": {
+			Extends: []string{"base", "ops"},
+			Repos:   []PresetRepo{{Repo: "git@github.com:org/api.git", BaseRef: "origin/develop"}, {Repo: "git@github.com:org/docs.git"}},
+		},
+	}
+	expanded, err := ExpandPreset(presets, "full")
+	if err != nil {
+		t.Fatalf("ExpandPreset: %v", err)
+	}
+	var got []string
+	for _, r := range expanded {
+		got = append(got, r.From+":"+PresetRepoAlias(r.PresetRepo)+":"+r.BaseRef)
+	}
+	want := "full:api:origin/develop,base:web:,ops:infra:,full:docs:"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expanded = %s, want %s", strings.Join(got, ","), want)
+	}
+
+	presets["base"] = Preset{Extends: []string{"full"}}
+	if _, err := ExpandPreset(presets, "full"); err == nil || !strings.Contains(err.Error(), "preset cycle: full -> base -> full") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+}
+
+func TestValidate_PresetExtends(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+presets:
+  app-full:
+    extends: [app]
+    repos: [git@github.com:org/infra.git]
+  a:
+    extends: [b]
+  b:
+    extends: [a, missing]
+workspaces: {}
+`,
+		"team.yaml": "presets:\n  app:\n    repos: [git@github.com:org/api.git]\n",
+	})
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var got []string
+	for _, issue := range result.Issues {
+		got = append(got, issue.Ref+": "+issue.Message)
+	}
+	want := "presets.b.extends: preset not found: missing,presets.a.extends: preset cycle: a -> b -> a"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("issues = %s, want %s", strings.Join(got, ","), want)
+	}
+
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	resolved, err := file.ResolvePreset("app-full")
+	if err != nil {
+		t.Fatalf("ResolvePreset: %v", err)
+	}
+	if len(resolved.Repos) != 2 || resolved.Repos[0].Repo != "git@github.com:org/api.git" {
+		t.Fatalf("unexpected resolved preset: %+v", resolved)
+	}
+}
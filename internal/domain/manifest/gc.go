@@ -0,0 +1,82 @@
+package manifest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	GCActionRemove = "remove"
+	GCActionKeep   = "keep"
+)
+
+// GC holds settings for `gion manifest gc`.
+type GC struct {
+	Policies map[string]GCPolicy `yaml:"policies,omitempty"`
+}
+
+// GCPolicy is an ordered list of rules. The first rule whose conditions all match
+// a workspace decides whether it is removed or kept; unmatched workspaces are kept.
+type GCPolicy struct {
+	Description string   `yaml:"description,omitempty"`
+	Rules       []GCRule `yaml:"rules"`
+}
+
+// GCRule matches workspaces by mode, labels, expiry, activity, age and merge state.
+// Empty conditions match everything.
+type GCRule struct {
+	Name        string   `yaml:"name,omitempty"`
+	Action      string   `yaml:"action"`
+	Mode        string   `yaml:"mode,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	Expired     bool     `yaml:"expired,omitempty"`
+	InactiveFor string   `yaml:"inactive_for,omitempty"`
+	OlderThan   string   `yaml:"older_than,omitempty"`
+	Merged      bool     `yaml:"merged,omitempty"`
+}
+
+// Label returns the rule name, or "rule <n>" (1-based) when it is unnamed.
+func (r GCRule) Label(index int) string {
+	if name := strings.TrimSpace(r.Name); name != "" {
+		return name
+	}
+	return fmt.Sprintf("rule %d", index+1)
+}
+
+// HasConditions reports whether the rule restricts which workspaces it matches.
+func (r GCRule) HasConditions() bool {
+	return strings.TrimSpace(r.Mode) != "" ||
+		len(r.Labels) > 0 ||
+		r.Expired ||
+		strings.TrimSpace(r.InactiveFor) != "" ||
+		strings.TrimSpace(r.OlderThan) != "" ||
+		r.Merged
+}
+
+// ParseGCDuration parses durations such as "14d", "2w", "36h" or any
+// time.ParseDuration value.
+func ParseGCDuration(value string) (time.Duration, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return 0, fmt.Errorf("duration is required")
+	}
+	unit := trimmed[len(trimmed)-1]
+	if unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(trimmed[:len(trimmed)-1])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %s", value)
+		}
+		day := 24 * time.Hour
+		if unit == 'w' {
+			return time.Duration(n) * 7 * day, nil
+		}
+		return time.Duration(n) * day, nil
+	}
+	d, err := time.ParseDuration(trimmed)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	return d, nil
+}
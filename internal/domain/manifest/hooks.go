@@ -0,0 +1,234 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// Hook points run by `gion apply`.
+const (
+	HookPostCreate  = "post_create"
+	HookPostRepoAdd = "post_repo_add"
+	HookPreRemove   = "pre_remove"
+)
+
+// Hook failure handling: block stops apply, warn reports the failure and continues.
+const (
+	HookOnFailureBlock = "block"
+	HookOnFailureWarn  = "warn"
+)
+
+// Hook is a shell command run at a hook point.
+type Hook struct {
+	Name string `yaml:"name,omitempty"`
+	// Run is passed to `sh -c`.
+	Run string `yaml:"run"`
+	// OnFailure is HookOnFailureBlock (default) or HookOnFailureWarn.
+	OnFailure string `yaml:"on_failure,omitempty"`
+}
+
+// Label returns the hook name, or its command when it is unnamed.
+func (h Hook) Label() string {
+	if name := strings.TrimSpace(h.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(h.Run)
+}
+
+// Warns reports whether a failure of the hook only produces a warning.
+func (h Hook) Warns() bool {
+	return strings.TrimSpace(h.OnFailure) == HookOnFailureWarn
+}
+
+// Hooks are the hooks of gion.yaml or of a preset.
+type Hooks struct {
+	// PostCreate runs in the workspace directory once a new workspace and its repos exist.
+	PostCreate []Hook `yaml:"post_create,omitempty"`
+	// PostRepoAdd runs in the worktree of every repo added to a workspace.
+	PostRepoAdd []Hook `yaml:"post_repo_add,omitempty"`
+	// PreRemove runs in the workspace directory before a workspace is removed.
+	PreRemove []Hook `yaml:"pre_remove,omitempty"`
+}
+
+// IsZero reports whether no hook is configured.
+func (h Hooks) IsZero() bool {
+	return len(h.PostCreate)+len(h.PostRepoAdd)+len(h.PreRemove) == 0
+}
+
+// RepoHooks are the hooks of a repo key.
+type RepoHooks struct {
+	PostRepoAdd []Hook `yaml:"post_repo_add,omitempty"`
+	// PreRemove runs in the worktree before it is removed, on its own or with its workspace.
+	PreRemove []Hook `yaml:"pre_remove,omitempty"`
+}
+
+// HookConfig is the `hooks:` section of gion.yaml: global hooks and hooks per repo key.
+type HookConfig struct {
+	PostCreate  []Hook `yaml:"post_create,omitempty"`
+	PostRepoAdd []Hook `yaml:"post_repo_add,omitempty"`
+	PreRemove   []Hook `yaml:"pre_remove,omitempty"`
+	// Repos holds hooks keyed by repo key (<host>/<owner>/<repo>[.git]).
+	Repos map[string]RepoHooks `yaml:"repos,omitempty"`
+}
+
+// IsZero lets `hooks:` be omitted when no hook is configured.
+func (c HookConfig) IsZero() bool {
+	return len(c.PostCreate)+len(c.PostRepoAdd)+len(c.PreRemove)+len(c.Repos) == 0
+}
+
+// WorkspaceHooks returns the hooks that apply to a workspace: the global hooks
+// followed by the hooks of its preset (preset mode only).
+func (f File) WorkspaceHooks(ws Workspace) Hooks {
+	hooks := Hooks{
+		PostCreate:  append([]Hook(nil), f.Hooks.PostCreate...),
+		PostRepoAdd: append([]Hook(nil), f.Hooks.PostRepoAdd...),
+		PreRemove:   append([]Hook(nil), f.Hooks.PreRemove...),
+	}
+	if strings.TrimSpace(ws.Mode) != workspace.MetadataModePreset {
+		return hooks
+	}
+	p, ok := f.Presets[strings.TrimSpace(ws.PresetName)]
+	if !ok {
+		return hooks
+	}
+	hooks.PostCreate = append(hooks.PostCreate, p.Hooks.PostCreate...)
+	hooks.PostRepoAdd = append(hooks.PostRepoAdd, p.Hooks.PostRepoAdd...)
+	hooks.PreRemove = append(hooks.PreRemove, p.Hooks.PreRemove...)
+	return hooks
+}
+
+// RepoKeyHooks returns the hooks configured for a repo key. Keys match with or
+// without the .git suffix.
+func (f File) RepoKeyHooks(repoKey string) RepoHooks {
+	want := strings.TrimSuffix(strings.TrimSpace(repoKey), ".git")
+	keys := make([]string, 0, len(f.Hooks.Repos))
+	for key := range f.Hooks.Repos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var hooks RepoHooks
+	for _, key := range keys {
+		if strings.TrimSuffix(strings.TrimSpace(key), ".git") != want {
+			continue
+		}
+		hooks.PostRepoAdd = append(hooks.PostRepoAdd, f.Hooks.Repos[key].PostRepoAdd...)
+		hooks.PreRemove = append(hooks.PreRemove, f.Hooks.Repos[key].PreRemove...)
+	}
+	return hooks
+}
+
+// ValidateHooks checks the hooks of a preset.
+func ValidateHooks(hooks Hooks) error {
+	for _, point := range []struct {
+		name  string
+		hooks []Hook
+	}{
+		{HookPostCreate, hooks.PostCreate},
+		{HookPostRepoAdd, hooks.PostRepoAdd},
+		{HookPreRemove, hooks.PreRemove},
+	} {
+		for i, hook := range point.hooks {
+			if err := validateHook(hook); err != nil {
+				return fmt.Errorf("%s[%d]: %w", point.name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateHook(hook Hook) error {
+	if strings.TrimSpace(hook.Run) == "" {
+		return fmt.Errorf("run is required")
+	}
+	switch strings.TrimSpace(hook.OnFailure) {
+	case "", HookOnFailureBlock, HookOnFailureWarn:
+		return nil
+	default:
+		return fmt.Errorf("invalid on_failure %q (must be %s or %s)", hook.OnFailure, HookOnFailureBlock, HookOnFailureWarn)
+	}
+}
+
+// validateHooks checks the top-level hooks section. Only gion.yaml may declare hooks.
+func validateHooks(root *yaml.Node, allowed bool) []ValidationIssue {
+	node := mappingValue(root, "hooks")
+	if node == nil {
+		return nil
+	}
+	if !allowed {
+		return []ValidationIssue{{Ref: "hooks", Message: fmt.Sprintf("only allowed in %s", FileName)}}
+	}
+	if node.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: "hooks", Message: "invalid value (must be a mapping)"}}
+	}
+	issues := validateHookPoints("hooks", node, []string{HookPostCreate, HookPostRepoAdd, HookPreRemove})
+	reposNode := mappingValue(node, "repos")
+	if reposNode == nil {
+		return issues
+	}
+	if reposNode.Kind != yaml.MappingNode {
+		return append(issues, ValidationIssue{Ref: "hooks.repos", Message: "invalid value (must be a mapping)"})
+	}
+	forEachMappingEntry(reposNode, func(repoKey string, value *yaml.Node) {
+		ref := "hooks.repos." + repoKey
+		if err := validateRepoKey(repoKey); err != nil {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: err.Error()})
+		}
+		if value == nil || value.Kind != yaml.MappingNode {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: "invalid value (must be a mapping)"})
+			return
+		}
+		issues = append(issues, validateHookPoints(ref, value, []string{HookPostRepoAdd, HookPreRemove})...)
+	})
+	return issues
+}
+
+// validatePresetHooks checks the hooks of a preset entry.
+func validatePresetHooks(refPrefix string, node *yaml.Node) []ValidationIssue {
+	hooksNode := mappingValue(node, "hooks")
+	if hooksNode == nil {
+		return nil
+	}
+	if hooksNode.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: refPrefix + ".hooks", Message: "invalid value (must be a mapping)"}}
+	}
+	return validateHookPoints(refPrefix+".hooks", hooksNode, []string{HookPostCreate, HookPostRepoAdd, HookPreRemove})
+}
+
+func validateHookPoints(refPrefix string, node *yaml.Node, points []string) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, point := range points {
+		listNode := mappingValue(node, point)
+		if listNode == nil {
+			continue
+		}
+		ref := refPrefix + "." + point
+		if listNode.Kind != yaml.SequenceNode {
+			issues = append(issues, ValidationIssue{Ref: ref, Message: "invalid value (must be a list)"})
+			continue
+		}
+		for i, entry := range listNode.Content {
+			entryRef := fmt.Sprintf("%s[%d]", ref, i)
+			if entry == nil || entry.Kind != yaml.MappingNode {
+				issues = append(issues, ValidationIssue{Ref: entryRef, Message: "invalid value (hook must be a mapping)"})
+				continue
+			}
+			var hook Hook
+			if err := entry.Decode(&hook); err != nil {
+				issues = append(issues, ValidationIssue{Ref: entryRef, Message: err.Error()})
+				continue
+			}
+			if strings.TrimSpace(hook.Run) == "" {
+				issues = append(issues, ValidationIssue{Ref: entryRef + ".run", Message: "missing required field"})
+				continue
+			}
+			if err := validateHook(hook); err != nil {
+				issues = append(issues, ValidationIssue{Ref: entryRef + ".on_failure", Message: err.Error()})
+			}
+		}
+	}
+	return issues
+}
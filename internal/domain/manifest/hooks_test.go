@@ -0,0 +1,118 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadSave_Hooks(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+hooks:
+  post_create:
+    - name: devcontainer
+      run: devcontainer up --workspace-folder .
+      on_failure: warn
+  repos:
+    github.com/org/web:
+      post_repo_add:
+        - run: npm ci
+presets:
+  web:
+    repos: [git@github.com:org/web.git]
+    hooks:
+      pre_remove:
+        - run: docker compose down
+workspaces: {}
+`,
+		"team.yaml": "workspaces: {}\n",
+	})
+
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	hooks := file.WorkspaceHooks(Workspace{Mode: "preset", PresetName: "web"})
+	if len(hooks.PostCreate) != 1 || hooks.PostCreate[0].Label() != "devcontainer" || !hooks.PostCreate[0].Warns() {
+		t.Fatalf("unexpected post_create hooks: %+v", hooks.PostCreate)
+	}
+	if len(hooks.PreRemove) != 1 || hooks.PreRemove[0].Label() != "docker compose down" {
+		t.Fatalf("unexpected pre_remove hooks: %+v", hooks.PreRemove)
+	}
+	if got := file.WorkspaceHooks(Workspace{Mode: "repo"}).PreRemove; len(got) != 0 {
+		t.Fatalf("preset hooks applied to a repo workspace: %+v", got)
+	}
+	if got := file.RepoKeyHooks("github.com/org/web.git").PostRepoAdd; len(got) != 1 || got[0].Run != "npm ci" {
+		t.Fatalf("unexpected repo hooks: %+v", got)
+	}
+	if got := file.RepoKeyHooks("github.com/org/api.git").PostRepoAdd; len(got) != 0 {
+		t.Fatalf("unexpected repo hooks for api: %+v", got)
+	}
+
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	root, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	for _, want := range []string{"run: devcontainer up --workspace-folder .", "github.com/org/web:", "run: docker compose down"} {
+		if !strings.Contains(string(root), want) {
+			t.Fatalf("missing %q in saved file:\n%s", want, root)
+		}
+	}
+}
+
+func TestValidate_Hooks(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+hooks:
+  post_create:
+    - name: setup
+    - run: make setup
+      on_failure: ignore
+  repos:
+    web:
+      pre_remove:
+        - run: make clean
+presets:
+  web:
+    repos: [git@github.com:org/web.git]
+    hooks:
+      post_repo_add: npm ci
+workspaces: {}
+`,
+		"team.yaml": `hooks:
+  post_create:
+    - run: make setup
+workspaces: {}
+`,
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "hooks.post_create[0].run,hooks.post_create[1].on_failure,hooks.repos.web,presets.web.hooks.post_repo_add,team.yaml:hooks"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
+
+func TestParseCatalog_RejectsHooks(t *testing.T) {
+	data := []byte("presets:\n  web:\n    repos: [git@github.com:org/web.git]\n    hooks:\n      post_create:\n        - run: make setup\n")
+	if _, err := ParseCatalog("presets.yaml", data); err == nil || !strings.Contains(err.Error(), "presets.web.hooks") {
+		t.Fatalf("expected hooks error, got %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry kinds that can be split across included files.
+const (
+	entryWorkspace = "workspaces"
+	entryPreset    = "presets"
+	entryGCPolicy  = "gc.policies"
+)
+
+// sourceSet records which file each entry of a loaded File came from, so Save
+// can write every entry back to the file that owns it.
+type sourceSet struct {
+	// files[0] is gion.yaml; the rest are included files in load order, then synced catalogs.
+	files []sourceFile
+	owner map[string]int
+}
+
+type sourceFile struct {
+	path     string
+	name     string
+	version  int
+	include  []string
+	original []byte
+	// catalog is the repo of a synced preset catalog. Catalog files are never written by Save.
+	catalog string
+}
+
+func entryKey(kind, name string) string {
+	return kind + "\x00" + name
+}
+
+// IncludedFiles returns the absolute paths of the files gion.yaml includes
+// (directly or through other included files), in load order.
+func IncludedFiles(rootDir string) ([]string, error) {
+	file, err := Load(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if file.sources == nil {
+		return nil, nil
+	}
+	var paths []string
+	for _, src := range file.sources.files[1:] {
+		if src.catalog != "" {
+			continue
+		}
+		paths = append(paths, src.path)
+	}
+	return paths, nil
+}
+
+// loadIncludes merges the files included by root, and the synced catalogs of root,
+// into a single File. Entries defined in more than one file are reported as conflicts.
+func loadIncludes(rootDir string, root File) (File, error) {
+	merged := File{
+		Version:        root.Version,
+		Include:        root.Include,
+		PresetCatalogs: root.PresetCatalogs,
+		Branches:       root.Branches,
+		Hooks:          root.Hooks,
+		Carry:          root.Carry,
+		Workspaces:     map[string]Workspace{},
+		Presets:        map[string]Preset{},
+	}
+	set := &sourceSet{owner: map[string]int{}}
+	rootPath := filepath.Clean(Path(rootDir))
+	visited := map[string]struct{}{rootPath: {}}
+
+	var add func(path, name string, fragment File) error
+	add = func(path, name string, fragment File) error {
+		original, err := marshal(fragment, len(set.files) > 0)
+		if err != nil {
+			return err
+		}
+		idx := len(set.files)
+		if idx > 0 && len(fragment.PresetCatalogs) > 0 {
+			return fmt.Errorf("%s: preset_catalogs is only allowed in %s", name, FileName)
+		}
+		if idx > 0 && !fragment.Branches.IsZero() {
+			return fmt.Errorf("%s: branches is only allowed in %s", name, FileName)
+		}
+		if idx > 0 && !fragment.Hooks.IsZero() {
+			return fmt.Errorf("%s: hooks is only allowed in %s", name, FileName)
+		}
+		if idx > 0 && len(fragment.Carry) > 0 {
+			return fmt.Errorf("%s: carry is only allowed in %s", name, FileName)
+		}
+		set.files = append(set.files, sourceFile{
+			path:     path,
+			name:     name,
+			version:  fragment.Version,
+			include:  fragment.Include,
+			original: original,
+		})
+		if err := mergeFragment(&merged, set, idx, fragment); err != nil {
+			return err
+		}
+		for _, pattern := range fragment.Include {
+			paths, err := expandInclude(filepath.Dir(path), pattern)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			for _, included := range paths {
+				if _, ok := visited[included]; ok {
+					continue
+				}
+				visited[included] = struct{}{}
+				includedName := displayIncludePath(rootDir, included)
+				data, err := os.ReadFile(included)
+				if err != nil {
+					return fmt.Errorf("read %s: %w", includedName, err)
+				}
+				includedFile, err := parse(includedName, data)
+				if err != nil {
+					return err
+				}
+				if err := add(included, includedName, includedFile); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := add(rootPath, FileName, root); err != nil {
+		return File{}, err
+	}
+	if err := loadCatalogs(rootDir, root, &merged, set); err != nil {
+		return File{}, err
+	}
+	merged.sources = set
+	return merged, nil
+}
+
+func mergeFragment(merged *File, set *sourceSet, idx int, fragment File) error {
+	claim := func(kind, name string) error {
+		key := entryKey(kind, name)
+		if prev, ok := set.owner[key]; ok {
+			return fmt.Errorf("%s: %s.%s is already defined in %s", set.files[idx].name, kind, name, set.files[prev].name)
+		}
+		set.owner[key] = idx
+		return nil
+	}
+	for _, id := range sortedKeys(fragment.Workspaces) {
+		if err := claim(entryWorkspace, id); err != nil {
+			return err
+		}
+		merged.Workspaces[id] = fragment.Workspaces[id]
+	}
+	for _, name := range sortedKeys(fragment.Presets) {
+		if err := claim(entryPreset, name); err != nil {
+			return err
+		}
+		merged.Presets[name] = fragment.Presets[name]
+	}
+	for _, name := range sortedKeys(fragment.GC.Policies) {
+		if err := claim(entryGCPolicy, name); err != nil {
+			return err
+		}
+		if merged.GC.Policies == nil {
+			merged.GC.Policies = map[string]GCPolicy{}
+		}
+		merged.GC.Policies[name] = fragment.GC.Policies[name]
+	}
+	return nil
+}
+
+// saveSources splits file back into the files it was loaded from. Entries that
+// are not owned by an included file (e.g. newly added ones) go to gion.yaml.
+// Included files are only rewritten when their entries changed; catalogs never are.
+func saveSources(file File) error {
+	set := file.sources
+	fragments := make([]File, len(set.files))
+	for i, src := range set.files {
+		fragments[i] = File{
+			Version:    src.version,
+			Include:    src.include,
+			Workspaces: map[string]Workspace{},
+			Presets:    map[string]Preset{},
+		}
+	}
+	fragments[0].Version = file.Version
+	fragments[0].Include = file.Include
+	fragments[0].PresetCatalogs = file.PresetCatalogs
+	fragments[0].Branches = file.Branches
+	fragments[0].Hooks = file.Hooks
+	fragments[0].Carry = file.Carry
+	for id, ws := range file.Workspaces {
+		fragments[set.owner[entryKey(entryWorkspace, id)]].Workspaces[id] = ws
+	}
+	for name, p := range file.Presets {
+		fragments[set.owner[entryKey(entryPreset, name)]].Presets[name] = p
+	}
+	for name, policy := range file.GC.Policies {
+		fragment := &fragments[set.owner[entryKey(entryGCPolicy, name)]]
+		if fragment.GC.Policies == nil {
+			fragment.GC.Policies = map[string]GCPolicy{}
+		}
+		fragment.GC.Policies[name] = policy
+	}
+
+	for i, src := range set.files {
+		if src.catalog != "" {
+			continue
+		}
+		data, err := marshal(fragments[i], i > 0)
+		if err != nil {
+			return err
+		}
+		if i > 0 && bytes.Equal(data, src.original) {
+			continue
+		}
+		if err := os.WriteFile(src.path, data, 0o600); err != nil {
+			return fmt.Errorf("write %s: %w", src.name, err)
+		}
+	}
+	return nil
+}
+
+// expandInclude resolves one include entry relative to baseDir. Plain paths must
+// exist; glob patterns may match nothing. Directories are skipped.
+func expandInclude(baseDir, pattern string) ([]string, error) {
+	trimmed := strings.TrimSpace(pattern)
+	if trimmed == "" {
+		return nil, fmt.Errorf("include: path is empty")
+	}
+	path := trimmed
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	if !strings.ContainsAny(trimmed, "*?[") {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", trimmed, err)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("include %s: is a directory", trimmed)
+		}
+		return []string{filepath.Clean(path)}, nil
+	}
+	matches, err := filepath.Glob(path)
+	if err != nil {
+		return nil, fmt.Errorf("include %s: %w", trimmed, err)
+	}
+	sort.Strings(matches)
+	var paths []string
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		paths = append(paths, filepath.Clean(match))
+	}
+	return paths, nil
+}
+
+func displayIncludePath(rootDir, path string) string {
+	if rel, err := filepath.Rel(rootDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// validationDoc is one file taking part in Validate.
+type validationDoc struct {
+	path     string
+	name     string
+	root     *yaml.Node
+	version  int
+	included bool
+}
+
+// ref qualifies an issue ref with the file name for included files,
+// e.g. "team/presets.yaml:presets.webapp".
+func (d validationDoc) ref(ref string) string {
+	if !d.included {
+		return ref
+	}
+	if ref == "" {
+		return d.name
+	}
+	return d.name + ":" + ref
+}
+
+func (d validationDoc) refIssues(issues []ValidationIssue) []ValidationIssue {
+	if !d.included {
+		return issues
+	}
+	out := make([]ValidationIssue, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, ValidationIssue{Ref: d.ref(issue.Ref), Message: issue.Message})
+	}
+	return out
+}
+
+// loadIncludeDocs parses every file reachable through include entries, in the same
+// order as Load. Files that cannot be read or parsed are reported as issues.
+func loadIncludeDocs(rootDir string, rootDoc validationDoc) ([]validationDoc, []ValidationIssue) {
+	docs := []validationDoc{rootDoc}
+	visited := map[string]struct{}{rootDoc.path: {}}
+	var issues []ValidationIssue
+	var walk func(d validationDoc)
+	walk = func(d validationDoc) {
+		includeNode := mappingValue(d.root, "include")
+		if includeNode == nil || includeNode.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range includeNode.Content {
+			if item == nil || item.Kind != yaml.ScalarNode {
+				continue
+			}
+			paths, err := expandInclude(filepath.Dir(d.path), item.Value)
+			if err != nil {
+				issues = append(issues, ValidationIssue{Ref: d.ref(fmt.Sprintf("include[%d]", i)), Message: err.Error()})
+				continue
+			}
+			for _, path := range paths {
+				if _, ok := visited[path]; ok {
+					continue
+				}
+				visited[path] = struct{}{}
+				child := validationDoc{path: path, name: displayIncludePath(rootDir, path), included: true}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					issues = append(issues, ValidationIssue{Ref: child.name, Message: err.Error()})
+					continue
+				}
+				var node yaml.Node
+				if err := yaml.Unmarshal(data, &node); err != nil {
+					issues = append(issues, ValidationIssue{Ref: child.name, Message: fmt.Sprintf("invalid yaml (%s)", strings.TrimSpace(err.Error()))})
+					continue
+				}
+				child.root = unwrapDocument(&node)
+				version, versionIssues := validateVersion(child.root)
+				if len(versionIssues) > 0 {
+					issues = append(issues, child.refIssues(versionIssues)...)
+					continue
+				}
+				child.version = version
+				docs = append(docs, child)
+				walk(child)
+			}
+		}
+	}
+	walk(rootDoc)
+	return docs, issues
+}
+
+func validateIncludeList(root *yaml.Node) []ValidationIssue {
+	includeNode := mappingValue(root, "include")
+	if includeNode == nil {
+		return nil
+	}
+	if includeNode.Kind != yaml.SequenceNode {
+		return []ValidationIssue{{Ref: "include", Message: "invalid value (must be a list of paths)"}}
+	}
+	var issues []ValidationIssue
+	for i, item := range includeNode.Content {
+		if item == nil || item.Kind != yaml.ScalarNode {
+			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("include[%d]", i), Message: "invalid value (must be a path or glob)"})
+		}
+	}
+	return issues
+}
+
+// collectPresetNames returns the presets declared across docs, or nil when no
+// file declares a presets mapping.
+func collectPresetNames(docs []validationDoc) map[string]struct{} {
+	var names map[string]struct{}
+	for _, d := range docs {
+		presetsNode := mappingValue(d.root, "presets")
+		if presetsNode == nil || presetsNode.Kind != yaml.MappingNode {
+			continue
+		}
+		if names == nil {
+			names = map[string]struct{}{}
+		}
+		forEachMappingEntry(presetsNode, func(name string, _ *yaml.Node) {
+			names[name] = struct{}{}
+		})
+	}
+	return names
+}
+
+// includeConflictIssues reports entries defined in more than one file.
+func includeConflictIssues(docs []validationDoc) []ValidationIssue {
+	if len(docs) < 2 {
+		return nil
+	}
+	var issues []ValidationIssue
+	owner := map[string]int{}
+	for i, d := range docs {
+		claim := func(kind string, node *yaml.Node) {
+			forEachMappingEntry(node, func(name string, _ *yaml.Node) {
+				key := entryKey(kind, name)
+				if prev, ok := owner[key]; ok {
+					issues = append(issues, ValidationIssue{Ref: d.ref(kind + "." + name), Message: fmt.Sprintf("already defined in %s", docs[prev].name)})
+					return
+				}
+				owner[key] = i
+			})
+		}
+		claim(entryWorkspace, mappingValue(d.root, "workspaces"))
+		claim(entryPreset, mappingValue(d.root, "presets"))
+		claim(entryGCPolicy, mappingValue(mappingValue(d.root, "gc"), "policies"))
+	}
+	return issues
+}
@@ -0,0 +1,182 @@
+package manifest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeManifestFiles(t *testing.T, rootDir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(rootDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadSave_Includes(t *testing.T) {
+	rootDir := t.TempDir()
+	sharedPresets := `# shared with the team
+presets:
+  webapp:
+    repos:
+      - git@github.com:org/api.git
+`
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include:
+  - shared/*.yaml
+  - personal.yaml
+workspaces:
+  ROOT-1:
+    mode: repo
+    repos: []
+`,
+		"shared/presets.yaml": sharedPresets,
+		"personal.yaml": `workspaces:
+  MINE-1:
+    mode: repo
+    repos: []
+  MINE-2:
+    mode: repo
+    repos: []
+`,
+	})
+
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, ok := file.Presets["webapp"]; !ok {
+		t.Fatalf("expected preset from include, got %+v", file.Presets)
+	}
+	for _, id := range []string{"ROOT-1", "MINE-1", "MINE-2"} {
+		if _, ok := file.Workspaces[id]; !ok {
+			t.Fatalf("expected workspace %s, got %+v", id, file.Workspaces)
+		}
+	}
+	included, err := IncludedFiles(rootDir)
+	if err != nil {
+		t.Fatalf("IncludedFiles: %v", err)
+	}
+	if len(included) != 2 || filepath.Base(included[0]) != "presets.yaml" || filepath.Base(included[1]) != "personal.yaml" {
+		t.Fatalf("included = %v", included)
+	}
+
+	ws := file.Workspaces["MINE-1"]
+	ws.Description = "updated"
+	file.Workspaces["MINE-1"] = ws
+	delete(file.Workspaces, "MINE-2")
+	file.Workspaces["NEW-1"] = Workspace{Mode: "repo"}
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(rootDir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return string(data)
+	}
+	if got := read("shared/presets.yaml"); got != sharedPresets {
+		t.Fatalf("unchanged include was rewritten:\n%s", got)
+	}
+	personal := read("personal.yaml")
+	if !strings.Contains(personal, "description: updated") || strings.Contains(personal, "MINE-2") || strings.Contains(personal, "NEW-1") {
+		t.Fatalf("unexpected personal.yaml:\n%s", personal)
+	}
+	root := read(FileName)
+	if !strings.Contains(root, "NEW-1") || !strings.Contains(root, "ROOT-1") || strings.Contains(root, "MINE-1") || strings.Contains(root, "webapp") {
+		t.Fatalf("unexpected %s:\n%s", FileName, root)
+	}
+	if !strings.Contains(root, "include:") {
+		t.Fatalf("expected include list to be kept:\n%s", root)
+	}
+}
+
+func TestLoad_IncludeConflict(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName:     "version: 2\ninclude: [other.yaml]\nworkspaces:\n  WS-1:\n    repos: []\n",
+		"other.yaml": "workspaces:\n  WS-1:\n    repos: []\n",
+	})
+	if _, err := Load(rootDir); err == nil || !strings.Contains(err.Error(), "already defined in gion.yaml") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Ref != "other.yaml:workspaces.WS-1" || !strings.Contains(result.Issues[0].Message, "already defined in gion.yaml") {
+		t.Fatalf("unexpected issues: %+v", result.Issues)
+	}
+}
+
+func TestValidate_Includes(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [presets.yaml, missing.yaml, "extra/*.yaml"]
+workspaces:
+  WS-1:
+    mode: preset
+    preset_name: webapp
+    repos: []
+`,
+		"presets.yaml":      "presets:\n  webapp:\n    repos: [git@github.com:org/api.git]\n",
+		"extra/broken.yaml": "version: 2\nworkspaces:\n  WS-2:\n    owner: me\n    repos: []\n",
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "include[1],extra/broken.yaml:workspaces.WS-2.owner"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
+
+func TestSnapshot_RestoresIncludedFiles(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName:    "version: 2\ninclude: [team.yaml]\nworkspaces: {}\n",
+		"team.yaml": "workspaces:\n  WS-1:\n    repos: []\n",
+	})
+	snapshot, err := TakeSnapshot(rootDir)
+	if err != nil {
+		t.Fatalf("TakeSnapshot: %v", err)
+	}
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	delete(file.Workspaces, "WS-1")
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := snapshot.Restore(); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	restored, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, ok := restored.Workspaces["WS-1"]; !ok {
+		t.Fatalf("expected WS-1 to be restored, got %+v", restored.Workspaces)
+	}
+}
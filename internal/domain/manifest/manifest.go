@@ -13,55 +13,151 @@ import (
 const FileName = "gion.yaml"
 
 type File struct {
-	Version    int                  `yaml:"version"`
-	Workspaces map[string]Workspace `yaml:"workspaces"`
-	Presets    map[string]Preset    `yaml:"presets"`
+	// Version is the schema version (see CurrentVersion). Files without one are version 1.
+	Version int `yaml:"version"`
+	// Include lists files (paths or globs, relative to the including file) whose
+	// workspaces, presets and gc policies are merged into this one.
+	Include []string `yaml:"include,omitempty"`
+	// PresetCatalogs lists git repositories whose presets are merged in (read-only)
+	// once synced with `gion manifest preset sync`.
+	PresetCatalogs []PresetCatalog `yaml:"preset_catalogs,omitempty"`
+	// Branches configures default branch names and the branch naming policy.
+	Branches Branches `yaml:"branches,omitempty"`
+	// Hooks are commands `gion apply` runs when workspaces and repos are created or removed.
+	Hooks HookConfig `yaml:"hooks,omitempty"`
+	// Carry holds, per repo key, untracked files brought into new worktrees.
+	Carry      map[string][]CarryRule `yaml:"carry,omitempty"`
+	Workspaces map[string]Workspace   `yaml:"workspaces"`
+	Presets    map[string]Preset      `yaml:"presets"`
+	GC         GC                     `yaml:"gc,omitempty"`
+
+	// sources is set by Load when entries come from included files or catalogs.
+	sources *sourceSet
 }
 
 type Workspace struct {
-	Description string `yaml:"description,omitempty"`
-	Mode        string `yaml:"mode,omitempty"`
-	PresetName  string `yaml:"preset_name,omitempty"`
-	SourceURL   string `yaml:"source_url,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Mode        string   `yaml:"mode,omitempty"`
+	PresetName  string   `yaml:"preset_name,omitempty"`
+	SourceURL   string   `yaml:"source_url,omitempty"`
+	Labels      []string `yaml:"labels,omitempty"`
+	ExpiresAt   string   `yaml:"expires_at,omitempty"`
+	// RenamedFrom is the previous ID of a workspace renamed with `gion manifest mv`.
+	// Apply moves the existing workspace instead of recreating it; the field is
+	// dropped when gion.yaml is rebuilt afterwards.
+	RenamedFrom string `yaml:"renamed_from,omitempty"`
+	// CopiedFrom is the workspace a new workspace is copied from (`gion manifest cp`).
+	// Apply branches each repo from the HEAD of the same alias in that workspace
+	// and, with CopyChanges, brings its uncommitted changes along. Both fields are
+	// dropped when gion.yaml is rebuilt afterwards.
+	CopiedFrom  string `yaml:"copied_from,omitempty"`
+	CopyChanges bool   `yaml:"copy_changes,omitempty"`
+	// RestoreFrom names the archive (`gion workspace archive`) a new workspace is
+	// restored from: apply recreates each branch at its archived commit and
+	// reapplies the archived uncommitted changes. It is dropped when gion.yaml is
+	// rebuilt afterwards.
+	RestoreFrom string `yaml:"restore_from,omitempty"`
 	Repos       []Repo `yaml:"repos"`
 }
 
 type Preset struct {
-	Repos []string `yaml:"repos"`
+	// Extends lists presets whose repos are included before this preset's own
+	// (see ExpandPreset). Repos may be empty when Extends is set.
+	Extends []string     `yaml:"extends,omitempty"`
+	Repos   []PresetRepo `yaml:"repos,omitempty"`
+	// Hooks run for workspaces created from the preset, after the global hooks.
+	// They are not inherited through Extends.
+	Hooks Hooks `yaml:"hooks,omitempty"`
 }
 
-func (p *Preset) UnmarshalYAML(value *yaml.Node) error {
-	type rawPreset struct {
-		Repos []string `yaml:"repos"`
+// PresetRepo is one repo of a preset. Alias, BaseRef and Branch are defaults for
+// workspaces created from the preset; explicit flags and prompt input win.
+// Entries without defaults are written as plain repo spec strings.
+type PresetRepo struct {
+	Repo  string `yaml:"repo"`
+	Alias string `yaml:"alias,omitempty"`
+	// BaseRef is the base for new branches: origin/<branch>.
+	BaseRef string `yaml:"base_ref,omitempty"`
+	// Branch is a branch-name template such as `feature/{id}` (see workspace.ExpandBranchTemplate).
+	Branch string `yaml:"branch,omitempty"`
+}
+
+// HasDefaults reports whether the entry sets any per-repo default.
+func (r PresetRepo) HasDefaults() bool {
+	return r.Alias != "" || r.BaseRef != "" || r.Branch != ""
+}
+
+func (r PresetRepo) MarshalYAML() (any, error) {
+	if !r.HasDefaults() {
+		return r.Repo, nil
 	}
-	var direct rawPreset
-	if err := value.Decode(&direct); err == nil && len(direct.Repos) > 0 {
-		p.Repos = direct.Repos
-		return nil
+	type plain PresetRepo
+	return plain(r), nil
+}
+
+// RepoSpecs returns the repo specs of the preset in order.
+func (p Preset) RepoSpecs() []string {
+	specs := make([]string, 0, len(p.Repos))
+	for _, r := range p.Repos {
+		specs = append(specs, r.Repo)
 	}
+	return specs
+}
 
-	var legacy struct {
-		Repos []struct {
-			Repo string `yaml:"repo"`
-		} `yaml:"repos"`
+// UnmarshalYAML accepts repo spec strings and `{repo: ..., alias: ...}` mappings.
+// A mapping without defaults is the legacy entry form of version 1 files;
+// `gion manifest migrate` rewrites those entries as repo spec strings.
+func (p *Preset) UnmarshalYAML(value *yaml.Node) error {
+	if extendsNode := mappingValue(value, "extends"); extendsNode != nil {
+		if err := extendsNode.Decode(&p.Extends); err != nil {
+			return err
+		}
+	}
+	if hooksNode := mappingValue(value, "hooks"); hooksNode != nil {
+		if err := hooksNode.Decode(&p.Hooks); err != nil {
+			return err
+		}
 	}
-	if err := value.Decode(&legacy); err == nil && len(legacy.Repos) > 0 {
-		for _, item := range legacy.Repos {
-			if strings.TrimSpace(item.Repo) == "" {
+	reposNode := mappingValue(value, "repos")
+	if reposNode == nil || reposNode.Kind != yaml.SequenceNode {
+		var direct struct {
+			Repos []string `yaml:"repos"`
+		}
+		if err := value.Decode(&direct); err != nil {
+			return err
+		}
+		for _, repoSpec := range direct.Repos {
+			p.Repos = append(p.Repos, PresetRepo{Repo: repoSpec})
+		}
+		return nil
+	}
+	for _, entry := range reposNode.Content {
+		repoSpec, ok := presetRepoFromNode(entry)
+		if entry != nil && entry.Kind == yaml.MappingNode {
+			if !ok || strings.TrimSpace(repoSpec) == "" {
 				continue
 			}
-			p.Repos = append(p.Repos, item.Repo)
+			var r PresetRepo
+			if err := entry.Decode(&r); err != nil {
+				return err
+			}
+			p.Repos = append(p.Repos, r)
+			continue
 		}
-		return nil
+		if !ok {
+			return fmt.Errorf("line %d: invalid preset repo (must be a string or {repo: ...})", entry.Line)
+		}
+		p.Repos = append(p.Repos, PresetRepo{Repo: repoSpec})
 	}
-
-	return value.Decode(&direct)
+	return nil
 }
 
 type Repo struct {
 	Alias   string `yaml:"alias"`
 	RepoKey string `yaml:"repo_key"`
-	Branch  string `yaml:"branch"`
+	Branch  string `yaml:"branch,omitempty"`
+	// Ref pins the worktree to a tag or commit (detached HEAD) instead of a branch.
+	Ref     string `yaml:"ref,omitempty"`
 	BaseRef string `yaml:"base_ref,omitempty"`
 }
 
@@ -75,12 +171,26 @@ func Load(rootDir string) (File, error) {
 	if err != nil {
 		return File{}, fmt.Errorf("read %s: %w", FileName, err)
 	}
+	file, err := parse(FileName, data)
+	if err != nil {
+		return File{}, err
+	}
+	if len(file.Include) == 0 && len(file.PresetCatalogs) == 0 {
+		return file, nil
+	}
+	return loadIncludes(rootDir, file)
+}
+
+func parse(name string, data []byte) (File, error) {
 	var file File
 	if err := yaml.Unmarshal(data, &file); err != nil {
-		return File{}, fmt.Errorf("parse %s: %w", FileName, err)
+		return File{}, fmt.Errorf("parse %s: %w", name, err)
 	}
 	if file.Version == 0 {
-		file.Version = 1
+		file.Version = VersionV1
+	}
+	if !IsSupportedVersion(file.Version) {
+		return File{}, fmt.Errorf("%s: %s", name, unsupportedVersionMessage(file.Version))
 	}
 	if file.Workspaces == nil {
 		file.Workspaces = map[string]Workspace{}
@@ -91,7 +201,12 @@ func Load(rootDir string) (File, error) {
 	return file, nil
 }
 
+// Save writes file to gion.yaml. Entries loaded from included files are written
+// back to those files.
 func Save(rootDir string, file File) error {
+	if file.sources != nil {
+		return saveSources(file)
+	}
 	data, err := Marshal(file)
 	if err != nil {
 		return err
@@ -102,9 +217,16 @@ func Save(rootDir string, file File) error {
 	return nil
 }
 
+// Marshal encodes file as a single gion.yaml (included entries are inlined; Save
+// splits them back).
 func Marshal(file File) ([]byte, error) {
+	return marshal(file, false)
+}
+
+// marshal encodes file. Fragments (included files) omit empty sections.
+func marshal(file File, fragment bool) ([]byte, error) {
 	if file.Version == 0 {
-		file.Version = 1
+		file.Version = CurrentVersion
 	}
 	if file.Workspaces == nil {
 		file.Workspaces = map[string]Workspace{}
@@ -113,13 +235,37 @@ func Marshal(file File) ([]byte, error) {
 		file.Presets = map[string]Preset{}
 	}
 	type rest struct {
-		Presets    map[string]Preset    `yaml:"presets"`
-		Workspaces map[string]Workspace `yaml:"workspaces"`
+		Include        []string               `yaml:"include,omitempty"`
+		PresetCatalogs []PresetCatalog        `yaml:"preset_catalogs,omitempty"`
+		Branches       Branches               `yaml:"branches,omitempty"`
+		Hooks          HookConfig             `yaml:"hooks,omitempty"`
+		Carry          map[string][]CarryRule `yaml:"carry,omitempty"`
+		GC             *GC                    `yaml:"gc,omitempty"`
+		Presets        map[string]Preset      `yaml:"presets"`
+		Workspaces     map[string]Workspace   `yaml:"workspaces"`
+	}
+	type fragmentRest struct {
+		Include        []string               `yaml:"include,omitempty"`
+		PresetCatalogs []PresetCatalog        `yaml:"preset_catalogs,omitempty"`
+		Branches       Branches               `yaml:"branches,omitempty"`
+		Hooks          HookConfig             `yaml:"hooks,omitempty"`
+		Carry          map[string][]CarryRule `yaml:"carry,omitempty"`
+		GC             *GC                    `yaml:"gc,omitempty"`
+		Presets        map[string]Preset      `yaml:"presets,omitempty"`
+		Workspaces     map[string]Workspace   `yaml:"workspaces,omitempty"`
+	}
+	var gc *GC
+	if len(file.GC.Policies) > 0 {
+		gc = &file.GC
+	}
+	var body any = rest{Include: file.Include, PresetCatalogs: file.PresetCatalogs, Branches: file.Branches, Hooks: file.Hooks, Carry: file.Carry, GC: gc, Presets: file.Presets, Workspaces: file.Workspaces}
+	if fragment {
+		body = fragmentRest(body.(rest))
 	}
 	var buf bytes.Buffer
 	enc := yaml.NewEncoder(&buf)
 	enc.SetIndent(2)
-	if err := enc.Encode(rest{Presets: file.Presets, Workspaces: file.Workspaces}); err != nil {
+	if err := enc.Encode(body); err != nil {
 		_ = enc.Close()
 		return nil, fmt.Errorf("marshal %s: %w", FileName, err)
 	}
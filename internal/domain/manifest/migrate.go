@@ -0,0 +1,108 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MigrateResult describes how a gion.yaml is rewritten to CurrentVersion.
+type MigrateResult struct {
+	FromVersion int
+	ToVersion   int
+	// Data is the migrated file. It equals the input when no migration is needed.
+	Data []byte
+	// Dropped lists fields that are unknown to CurrentVersion and are removed by the rewrite.
+	Dropped []ValidationIssue
+}
+
+// Changed reports whether the migration rewrites the file.
+func (r MigrateResult) Changed() bool {
+	return r.FromVersion != r.ToVersion
+}
+
+// Migrate upgrades the contents of a gion.yaml to CurrentVersion.
+// Legacy preset entries ({repo: ...}) are rewritten as repo spec strings and unknown fields are dropped.
+func Migrate(data []byte) (MigrateResult, error) {
+	return migrate(FileName, data, false)
+}
+
+func migrate(name string, data []byte, fragment bool) (MigrateResult, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return MigrateResult{}, fmt.Errorf("parse %s: %w", name, err)
+	}
+	root := unwrapDocument(&doc)
+	version, issues := validateVersion(root)
+	if len(issues) > 0 {
+		return MigrateResult{}, fmt.Errorf("%s: %s", name, issues[0].Message)
+	}
+	if version == CurrentVersion {
+		return MigrateResult{FromVersion: version, ToVersion: version, Data: data}, nil
+	}
+
+	file, err := parse(name, data)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	file.Version = CurrentVersion
+	out, err := marshal(file, fragment)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	return MigrateResult{
+		FromVersion: version,
+		ToVersion:   CurrentVersion,
+		Data:        out,
+		Dropped:     unknownFieldIssues(root),
+	}, nil
+}
+
+// FileMigration is the migration of one file of the manifest: gion.yaml or a file it includes.
+type FileMigration struct {
+	Path string
+	// Name is the path relative to the root, as shown in messages.
+	Name     string
+	Original []byte
+	MigrateResult
+}
+
+// BackupPath returns where `gion manifest migrate` keeps the pre-migration file.
+func (m FileMigration) BackupPath() string {
+	return backupPath(m.Path, m.FromVersion)
+}
+
+// MigrateAll migrates gion.yaml and every file it includes (directly or through
+// other included files), in load order. Synced preset catalogs are left alone:
+// they are rewritten by `gion manifest preset sync`.
+func MigrateAll(rootDir string) ([]FileMigration, error) {
+	included, err := IncludedFiles(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	paths := append([]string{Path(rootDir)}, included...)
+	migrations := make([]FileMigration, 0, len(paths))
+	for i, path := range paths {
+		name := displayIncludePath(rootDir, path)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		result, err := migrate(name, data, i > 0)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, FileMigration{Path: path, Name: name, Original: data, MigrateResult: result})
+	}
+	return migrations, nil
+}
+
+// BackupPath returns where `gion manifest migrate` keeps the pre-migration gion.yaml.
+func BackupPath(rootDir string, fromVersion int) string {
+	return backupPath(Path(rootDir), fromVersion)
+}
+
+func backupPath(path string, fromVersion int) string {
+	return fmt.Sprintf("%s.v%d.bak", path, fromVersion)
+}
@@ -0,0 +1,106 @@
+package manifest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrate_V1ToCurrent(t *testing.T) {
+	data := []byte(`
+version: 1
+presets:
+  webapp:
+    repos:
+      - git@github.com:org/api.git
+      - repo: git@github.com:org/web.git
+workspaces:
+  WS-1:
+    owner: someone
+    repos:
+      - alias: api
+        repo_key: github.com/org/api.git
+        branch: WS-1
+`)
+	result, err := Migrate(data)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if !result.Changed() || result.FromVersion != VersionV1 || result.ToVersion != CurrentVersion {
+		t.Fatalf("unexpected versions: %+v", result)
+	}
+	if len(result.Dropped) != 1 || result.Dropped[0].Ref != "workspaces.WS-1.owner" {
+		t.Fatalf("dropped = %+v, want workspaces.WS-1.owner", result.Dropped)
+	}
+	out := string(result.Data)
+	if !strings.HasPrefix(out, "version: 2\n") {
+		t.Fatalf("expected version 2 header, got:\n%s", out)
+	}
+	if strings.Contains(out, "repo: git@") || !strings.Contains(out, "- git@github.com:org/web.git") {
+		t.Fatalf("expected legacy preset entry to become a string, got:\n%s", out)
+	}
+	if strings.Contains(out, "owner") {
+		t.Fatalf("expected unknown field to be dropped, got:\n%s", out)
+	}
+
+	again, err := Migrate(result.Data)
+	if err != nil {
+		t.Fatalf("Migrate (current): %v", err)
+	}
+	if again.Changed() || string(again.Data) != out {
+		t.Fatalf("expected migrating a current file to be a no-op, got %+v", again)
+	}
+}
+
+func TestMigrate_MissingVersionIsV1(t *testing.T) {
+	result, err := Migrate([]byte("workspaces: {}\n"))
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if result.FromVersion != VersionV1 || !result.Changed() {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
+
+func TestMigrate_RejectsNewerVersion(t *testing.T) {
+	if _, err := Migrate([]byte("version: 99\n")); err == nil || !strings.Contains(err.Error(), "newer gion") {
+		t.Fatalf("expected newer version error, got %v", err)
+	}
+}
+
+func TestMigrateAll_MigratesIncludedFiles(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: "version: 2\ninclude:\n  - team/*.yaml\nworkspaces: {}\npresets: {}\n",
+		"team/presets.yaml": `presets:
+  webapp:
+    repos:
+      - repo: git@github.com:org/web.git
+`,
+	})
+
+	migrations, err := MigrateAll(rootDir)
+	if err != nil {
+		t.Fatalf("MigrateAll: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("migrations = %+v, want gion.yaml and team/presets.yaml", migrations)
+	}
+	if migrations[0].Name != FileName || migrations[0].Changed() {
+		t.Fatalf("expected gion.yaml to be unchanged, got %+v", migrations[0])
+	}
+	included := migrations[1]
+	if included.Name != "team/presets.yaml" || !included.Changed() || included.FromVersion != VersionV1 {
+		t.Fatalf("unexpected included migration: %+v", included)
+	}
+	out := string(included.Data)
+	if !strings.HasPrefix(out, "version: 2\n") || !strings.Contains(out, "- git@github.com:org/web.git") {
+		t.Fatalf("expected included file migrated to version 2, got:\n%s", out)
+	}
+	if strings.Contains(out, "workspaces:") {
+		t.Fatalf("expected included file to keep omitting empty sections, got:\n%s", out)
+	}
+	if want := filepath.Join(rootDir, "team", "presets.yaml.v1.bak"); included.BackupPath() != want {
+		t.Fatalf("backup path = %q, want %q", included.BackupPath(), want)
+	}
+}
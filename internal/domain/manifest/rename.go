@@ -0,0 +1,83 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// Rename moves the workspace oldID to newID and records renamed_from so apply
+// moves the existing workspace directory instead of recreating it. A pending
+// renamed_from on oldID is carried over, so renaming twice before apply still
+// points at the workspace on disk.
+func (f *File) Rename(oldID, newID string) error {
+	oldID = strings.TrimSpace(oldID)
+	newID = strings.TrimSpace(newID)
+	ws, ok := f.Workspaces[oldID]
+	if !ok {
+		return fmt.Errorf("workspace not found in %s: %s", FileName, oldID)
+	}
+	if oldID == newID {
+		return fmt.Errorf("workspace %s: new id is the same as the current id", oldID)
+	}
+	if _, exists := f.Workspaces[newID]; exists {
+		return fmt.Errorf("workspace already exists in %s: %s", FileName, newID)
+	}
+	from := strings.TrimSpace(ws.RenamedFrom)
+	if from == "" {
+		from = oldID
+	}
+	if from == newID {
+		from = ""
+	}
+	ws.RenamedFrom = from
+	delete(f.Workspaces, oldID)
+	f.Workspaces[newID] = ws
+	if f.sources != nil {
+		// Keep the workspace in the file that declared it.
+		if idx, ok := f.sources.owner[entryKey(entryWorkspace, oldID)]; ok {
+			delete(f.sources.owner, entryKey(entryWorkspace, oldID))
+			f.sources.owner[entryKey(entryWorkspace, newID)] = idx
+		}
+	}
+	return nil
+}
+
+// renameIssues checks renamed_from across all files: it must be a valid
+// workspace ID that is no longer declared, and at most one workspace may claim it.
+func renameIssues(ctx context.Context, docs []validationDoc) []ValidationIssue {
+	declared := map[string]struct{}{}
+	for _, d := range docs {
+		forEachMappingEntry(mappingValue(d.root, "workspaces"), func(id string, _ *yaml.Node) {
+			declared[strings.TrimSpace(id)] = struct{}{}
+		})
+	}
+	var issues []ValidationIssue
+	claimed := map[string]string{}
+	for _, d := range docs {
+		forEachMappingEntry(mappingValue(d.root, "workspaces"), func(id string, ws *yaml.Node) {
+			from := strings.TrimSpace(scalarValue(mappingValue(ws, "renamed_from")))
+			if from == "" {
+				return
+			}
+			ref := d.ref(fmt.Sprintf("workspaces.%s.renamed_from", id))
+			if err := workspace.ValidateWorkspaceID(ctx, from); err != nil {
+				issues = append(issues, ValidationIssue{Ref: ref, Message: err.Error()})
+				return
+			}
+			if _, ok := declared[from]; ok {
+				issues = append(issues, ValidationIssue{Ref: ref, Message: fmt.Sprintf("workspace %s is still declared", from)})
+				return
+			}
+			if other, ok := claimed[from]; ok {
+				issues = append(issues, ValidationIssue{Ref: ref, Message: fmt.Sprintf("workspace %s is already renamed to %s", from, other)})
+				return
+			}
+			claimed[from] = id
+		})
+	}
+	return issues
+}
@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFileRename(t *testing.T) {
+	file := File{Workspaces: map[string]Workspace{
+		"WS-1": {Description: "login"},
+		"WS-3": {},
+	}}
+	if err := file.Rename("WS-1", "WS-2"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if ws := file.Workspaces["WS-2"]; ws.RenamedFrom != "WS-1" || ws.Description != "login" {
+		t.Fatalf("unexpected workspace: %+v", ws)
+	}
+	// Renaming again before apply still points at the workspace on disk.
+	if err := file.Rename("WS-2", "WS-4"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if ws := file.Workspaces["WS-4"]; ws.RenamedFrom != "WS-1" {
+		t.Fatalf("unexpected workspace: %+v", ws)
+	}
+	if err := file.Rename("WS-4", "WS-1"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if ws := file.Workspaces["WS-1"]; ws.RenamedFrom != "" {
+		t.Fatalf("renaming back should clear renamed_from: %+v", ws)
+	}
+	if err := file.Rename("WS-1", "WS-3"); err == nil {
+		t.Fatalf("expected error renaming onto an existing workspace")
+	}
+	if err := file.Rename("WS-9", "WS-10"); err == nil {
+		t.Fatalf("expected error renaming a missing workspace")
+	}
+}
+
+func TestValidate_RenamedFrom(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+include: [team.yaml]
+workspaces:
+  WS-2:
+    renamed_from: WS-1
+    repos: []
+  WS-3:
+    renamed_from: WS-4
+    repos: []
+  WS-5:
+    renamed_from: a/b
+    repos: []
+`,
+		"team.yaml": `workspaces:
+  WS-4:
+    repos: []
+  WS-6:
+    renamed_from: WS-1
+    repos: []
+`,
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "workspaces.WS-3.renamed_from,workspaces.WS-5.renamed_from,team.yaml:workspaces.WS-6.renamed_from"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
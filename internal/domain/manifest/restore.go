@@ -0,0 +1,27 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// validateRestoreFrom checks restore_from of a workspace entry. A restored
+// workspace is created from its archive, so it cannot also be renamed or copied.
+func validateRestoreFrom(workspaceID string, node *yaml.Node) []ValidationIssue {
+	value := mappingValue(node, "restore_from")
+	if value == nil {
+		return nil
+	}
+	ref := fmt.Sprintf("workspaces.%s.restore_from", workspaceID)
+	if value.Kind != yaml.ScalarNode || strings.TrimSpace(value.Value) == "" {
+		return []ValidationIssue{{Ref: ref, Message: "must be a non-empty string"}}
+	}
+	for _, other := range []string{"renamed_from", "copied_from"} {
+		if strings.TrimSpace(scalarValue(mappingValue(node, other))) != "" {
+			return []ValidationIssue{{Ref: ref, Message: fmt.Sprintf("cannot be combined with %s", other)}}
+		}
+	}
+	return nil
+}
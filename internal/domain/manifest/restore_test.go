@@ -0,0 +1,43 @@
+package manifest
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestValidate_RestoreFrom(t *testing.T) {
+	rootDir := t.TempDir()
+	writeManifestFiles(t, rootDir, map[string]string{
+		FileName: `version: 2
+workspaces:
+  WS-1:
+    restore_from: WS-1-20260102T030405Z
+    repos: []
+  WS-2:
+    restore_from: ""
+    repos: []
+  WS-3:
+    restore_from: WS-3-20260102T030405Z
+    copied_from: WS-1
+    repos: []
+  WS-4:
+    restore_from: [a]
+    repos: []
+`,
+	})
+
+	result, err := Validate(context.Background(), rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "workspaces.WS-2.restore_from,workspaces.WS-3.restore_from,workspaces.WS-4.restore_from"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
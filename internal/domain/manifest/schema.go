@@ -0,0 +1,435 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+// Patterns approximating the git ref rules Validate enforces with `git check-ref-format`.
+// JSON Schema has no lookahead-free way to express "none of these substrings", so each
+// rule is a positive pattern plus a `not` pattern.
+const (
+	schemaRefCharsPattern      = `^[^\x00-\x20\x7f~^:?*\[\\]+$`
+	schemaBranchInvalidPattern = `\.\.|@\{|//|^[-/.]|/\.|[/.]$|\.lock(/|$)|^@$`
+	schemaPinnedInvalidPattern = `\.\.|@\{|^-|/$|\.lock$`
+	schemaRepoKeyPattern       = `^[^/\s]+/[^/\s]+/[^/\s]+$`
+	schemaDurationPattern      = `^([0-9]+[dw]|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+	schemaRFC3339Pattern       = `^[0-9]{4}-[0-9]{2}-[0-9]{2}[Tt][0-9]{2}:[0-9]{2}:[0-9]{2}(\.[0-9]+)?([Zz]|[+-][0-9]{2}:[0-9]{2})$`
+	schemaURLPattern           = `^[A-Za-z][A-Za-z0-9+.-]*://[^/\s]+`
+	// Mirrors the placeholders of workspace.ExpandBranchTemplate.
+	schemaBranchTemplatePattern = `^[^\s{}]*(\{(id|issue|slug|user|date|preset)\}[^\s{}]*)*$`
+)
+
+var workspaceModes = []string{
+	workspace.MetadataModePreset,
+	workspace.MetadataModeRepo,
+	workspace.MetadataModeReview,
+	workspace.MetadataModeIssue,
+	workspace.MetadataModeResume,
+	workspace.MetadataModeAdd,
+}
+
+// schemaFields refines the schema generated from a struct field, keyed by
+// "<definition>.<yaml key>". Entries are merged into the generated property schema.
+var schemaFields = map[string]map[string]any{
+	"gion.version": {
+		"description": "Schema version. Files without a version are version 1.",
+		"enum":        supportedVersions(),
+	},
+	"gion.include": {
+		"description": "Files (paths or globs, relative to this file) whose workspaces, presets and gc policies are merged in.",
+		"items":       map[string]any{"type": "string", "minLength": 1},
+	},
+	"gion.preset_catalogs": {
+		"description": "Git repositories whose presets are merged in (read-only) after `gion manifest preset sync`.",
+	},
+	"gion.workspaces": {
+		"description":   "Workspaces keyed by workspace ID.",
+		"propertyNames": ref("workspaceId"),
+	},
+	"gion.presets": {
+		"description":   "Presets keyed by preset name.",
+		"propertyNames": map[string]any{"pattern": presetNamePattern.String()},
+	},
+	"workspace.mode": {
+		"enum": stringsToAny(workspaceModes),
+	},
+	"workspace.source_url": {
+		"format":  "uri",
+		"pattern": schemaURLPattern,
+	},
+	"workspace.labels": {
+		"items":       ref("label"),
+		"uniqueItems": true,
+	},
+	"workspace.expires_at": {
+		"description": "RFC 3339 timestamp after which the workspace is considered expired.",
+		"format":      "date-time",
+		"pattern":     schemaRFC3339Pattern,
+	},
+	"workspace.renamed_from": {
+		"description": "Previous workspace ID, set by `gion manifest mv`. Apply moves that workspace to this ID.",
+		"allOf":       []any{ref("workspaceId")},
+	},
+	"workspace.copied_from": {
+		"description": "Workspace this one is copied from, set by `gion manifest cp`. Apply branches each repo from the HEAD of the same alias there.",
+		"allOf":       []any{ref("workspaceId")},
+	},
+	"workspace.copy_changes": {
+		"description": "Also bring over the uncommitted changes of copied_from.",
+	},
+	"workspace.restore_from": {
+		"description": "Archive this workspace is restored from, set by `gion workspace restore`. A name under GION_ROOT/archive or a path.",
+		"minLength":   1,
+	},
+	"repo.alias": {
+		"description": "Directory name under the workspace.",
+		"minLength":   1,
+		"pattern":     `^[^/\\]+$`,
+		"not":         map[string]any{"const": workspace.MetadataDirName},
+	},
+	"repo.repo_key": {
+		"description": "Repo store key: <host>/<owner>/<repo>[.git].",
+		"pattern":     schemaRepoKeyPattern,
+	},
+	"repo.branch": {
+		"description": "Branch checked out in the worktree.",
+		"allOf":       []any{ref("branchName")},
+	},
+	"repo.ref": {
+		"description": "Tag or commit to pin the worktree to (detached HEAD) instead of a branch.",
+		"minLength":   1,
+		"pattern":     schemaRefCharsPattern,
+		"not":         map[string]any{"pattern": schemaPinnedInvalidPattern},
+	},
+	"repo.base_ref": {
+		"description": "Base ref for a new branch: origin/<branch>.",
+		"pattern":     `^origin/[^\x00-\x20\x7f~^:?*\[\\]+$`,
+		"not":         map[string]any{"pattern": `^origin/[-/.]|\.\.|@\{|//|/\.|[/.]$|\.lock(/|$)`},
+	},
+	"presetCatalog.repo": {
+		"description": "Repo spec of the catalog repository.",
+		"pattern":     `\S`,
+	},
+	"presetCatalog.ref": {
+		"description": "Branch, tag or commit to read. Defaults to the default branch.",
+		"minLength":   1,
+		"pattern":     `^[^-\s:][^\s:]*$`,
+	},
+	"presetCatalog.path": {
+		"description": "File in the repository holding a presets mapping. Defaults to " + DefaultCatalogPath + ".",
+		"minLength":   1,
+		"not":         map[string]any{"pattern": `^/|\\|(^|/)\.\.(/|$)`},
+	},
+	"preset.repos": {
+		"description": "Repo specs (e.g. git@github.com:org/repo.git), or mappings that add per-repo defaults.",
+		"minItems":    1,
+		"items": map[string]any{"oneOf": []any{
+			map[string]any{"type": "string", "pattern": `\S`},
+			ref("presetRepo"),
+		}},
+	},
+	"preset.extends": {
+		"description": "Presets whose repos are included before this preset's own.",
+		"minItems":    1,
+		"items":       map[string]any{"pattern": presetNamePattern.String()},
+		"uniqueItems": true,
+	},
+	"presetRepo.repo": {
+		"description": "Repo spec (e.g. git@github.com:org/repo.git).",
+		"pattern":     `\S`,
+	},
+	"presetRepo.alias": {
+		"description": "Default directory name under the workspace.",
+		"minLength":   1,
+		"pattern":     `^[^/\\]+$`,
+		"not":         map[string]any{"const": workspace.MetadataDirName},
+	},
+	"presetRepo.base_ref": {
+		"description": "Default base ref for new branches: origin/<branch>.",
+		"pattern":     `^origin/[^\s]+$`,
+	},
+	"presetRepo.branch": {
+		"description": "Default branch-name template (e.g. feature/{id}).",
+		"allOf":       []any{ref("branchTemplate")},
+	},
+	"gion.branches": {
+		"description": "Default branch names for `gion manifest add` and the branch naming policy.",
+	},
+	"branches.pattern": {
+		"description": "Regular expression every workspace branch must match (review workspaces are not checked).",
+		"minLength":   1,
+	},
+	"branchTemplates.repo": {
+		"description": "Branch-name template for repo mode. Defaults to {id}.",
+		"allOf":       []any{ref("branchTemplate")},
+	},
+	"branchTemplates.issue": {
+		"description": "Branch-name template for issue mode. Defaults to issue/{issue}.",
+		"allOf":       []any{ref("branchTemplate")},
+	},
+	"branchTemplates.preset": {
+		"description": "Branch-name template for preset mode, used for preset repos without their own branch. Defaults to {id}.",
+		"allOf":       []any{ref("branchTemplate")},
+	},
+	"gion.hooks": {
+		"description": "Commands `gion apply` runs after creating and before removing workspaces and repos.",
+	},
+	"hookConfig.repos": {
+		"description":   "Hooks keyed by repo key (<host>/<owner>/<repo>[.git]).",
+		"propertyNames": map[string]any{"pattern": schemaRepoKeyPattern},
+	},
+	"preset.hooks": {
+		"description": "Hooks for workspaces created from this preset, run after the global hooks. Not inherited through extends.",
+	},
+	"hook.run": {
+		"description": "Command passed to `sh -c`.",
+		"pattern":     `\S`,
+	},
+	"hook.on_failure": {
+		"description": "block (default) stops apply when the hook fails; warn reports the failure and continues.",
+		"enum":        []any{HookOnFailureBlock, HookOnFailureWarn},
+	},
+	"gion.carry": {
+		"description":   "Untracked files (e.g. .env, local certs) copied or symlinked into new worktrees, keyed by repo key (<host>/<owner>/<repo>[.git]).",
+		"propertyNames": map[string]any{"pattern": schemaRepoKeyPattern},
+	},
+	"carryRule.files": {
+		"description": "Globs relative to the source; ** matches any number of directories.",
+		"minItems":    1,
+		"items": map[string]any{
+			"minLength": 1,
+			"not":       map[string]any{"pattern": `^/|\\|(^|/)(\.|\.\.|\.git)?(/|$)`},
+		},
+	},
+	"carryRule.mode": {
+		"description": "copy (default) or symlink.",
+		"enum":        []any{workspace.CarryModeCopy, workspace.CarryModeSymlink},
+	},
+	"carryRule.from_workspace": {
+		"description": "Workspace whose worktree of the same repo is the source. Defaults to <root>/templates/<host>/<owner>/<repo>.",
+		"allOf":       []any{ref("workspaceId")},
+	},
+	"gc.policies": {
+		"description":   "Policies keyed by name, selected with `gion manifest gc --policy`.",
+		"propertyNames": map[string]any{"pattern": presetNamePattern.String()},
+	},
+	"gcPolicy.rules": {
+		"description": "Ordered rules; the first matching rule decides.",
+		"minItems":    1,
+	},
+	"gcRule.action": {
+		"enum": []any{GCActionRemove, GCActionKeep},
+	},
+	"gcRule.mode": {
+		"enum": stringsToAny(workspaceModes),
+	},
+	"gcRule.labels": {
+		"items": ref("label"),
+	},
+	"gcRule.inactive_for": {
+		"pattern": schemaDurationPattern,
+	},
+	"gcRule.older_than": {
+		"pattern": schemaDurationPattern,
+	},
+}
+
+// schemaObjects adds constraints that span fields of a definition.
+var schemaObjects = map[string]map[string]any{
+	"gion": {
+		"required": []any{"workspaces"},
+	},
+	"workspace": {
+		"required": []any{"repos"},
+		"if": map[string]any{
+			"required":   []any{"mode"},
+			"properties": map[string]any{"mode": map[string]any{"const": workspace.MetadataModePreset}},
+		},
+		"then": map[string]any{"required": []any{"preset_name"}},
+	},
+	"repo": {
+		"required": []any{"alias", "repo_key"},
+		"oneOf": []any{
+			map[string]any{"required": []any{"branch"}},
+			map[string]any{"required": []any{"ref"}},
+		},
+		"if":   map[string]any{"required": []any{"ref"}},
+		"then": map[string]any{"not": map[string]any{"required": []any{"base_ref"}}},
+	},
+	"presetCatalog": {
+		"required": []any{"repo"},
+	},
+	"preset": {
+		"anyOf": []any{
+			map[string]any{"required": []any{"repos"}},
+			map[string]any{"required": []any{"extends"}},
+		},
+	},
+	"presetRepo": {
+		"required": []any{"repo"},
+		// A mapping without defaults is the legacy version 1 form; use a repo spec string.
+		"anyOf": []any{
+			map[string]any{"required": []any{"alias"}},
+			map[string]any{"required": []any{"base_ref"}},
+			map[string]any{"required": []any{"branch"}},
+		},
+	},
+	"hook": {
+		"required": []any{"run"},
+	},
+	"carryRule": {
+		"required": []any{"files"},
+	},
+	"gcPolicy": {
+		"required": []any{"rules"},
+	},
+	"gcRule": {
+		"required": []any{"action"},
+		// A remove rule must have at least one condition.
+		"if": map[string]any{
+			"required":   []any{"action"},
+			"properties": map[string]any{"action": map[string]any{"const": GCActionRemove}},
+		},
+		"then": map[string]any{
+			"anyOf": []any{
+				map[string]any{"required": []any{"mode"}},
+				map[string]any{"required": []any{"labels"}, "properties": map[string]any{"labels": map[string]any{"minItems": 1}}},
+				map[string]any{"required": []any{"expired"}, "properties": map[string]any{"expired": map[string]any{"const": true}}},
+				map[string]any{"required": []any{"inactive_for"}},
+				map[string]any{"required": []any{"older_than"}},
+				map[string]any{"required": []any{"merged"}, "properties": map[string]any{"merged": map[string]any{"const": true}}},
+			},
+		},
+	},
+}
+
+// Schema returns a JSON Schema (draft-07) for gion.yaml at CurrentVersion.
+// Object definitions are generated from the yaml tags of the manifest model,
+// so every field Load understands is described and anything else is rejected.
+func Schema() map[string]any {
+	definitions := map[string]any{
+		"workspaceId": map[string]any{
+			"type":  "string",
+			"allOf": []any{ref("branchName"), map[string]any{"pattern": `^[^/\\]+$`}},
+		},
+		"branchName": map[string]any{
+			"type":      "string",
+			"minLength": 1,
+			"pattern":   schemaRefCharsPattern,
+			"not":       map[string]any{"pattern": schemaBranchInvalidPattern},
+		},
+		"label": map[string]any{
+			"type":      "string",
+			"maxLength": workspace.MaxLabelLength,
+			"pattern":   workspace.LabelPattern,
+		},
+		"branchTemplate": map[string]any{
+			"type":        "string",
+			"description": "Branch name with {id}, {issue}, {slug}, {user}, {date} or {preset} placeholders.",
+			"minLength":   1,
+			"pattern":     schemaBranchTemplatePattern,
+		},
+	}
+	root := schemaObject(reflect.TypeOf(File{}), "gion", definitions)
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = FileName
+	root["description"] = fmt.Sprintf("gion inventory (schema version %d).", CurrentVersion)
+	root["definitions"] = definitions
+	return root
+}
+
+// SchemaJSON returns Schema encoded as indented JSON.
+func SchemaJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func schemaObject(t reflect.Type, name string, definitions map[string]any) map[string]any {
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		prop := schemaType(field.Type, definitions)
+		for k, v := range schemaFields[name+"."+key] {
+			prop[k] = v
+		}
+		properties[key] = prop
+	}
+	obj := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	for k, v := range schemaObjects[name] {
+		obj[k] = v
+	}
+	return obj
+}
+
+func schemaType(t reflect.Type, definitions map[string]any) map[string]any {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int:
+		return map[string]any{"type": "integer"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaType(t.Elem(), definitions)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaType(t.Elem(), definitions)}
+	case reflect.Struct:
+		name := schemaDefinitionName(t)
+		if _, ok := definitions[name]; !ok {
+			definitions[name] = schemaObject(t, name, definitions)
+		}
+		return ref(name)
+	default:
+		panic(fmt.Sprintf("manifest schema: unsupported field type %s", t))
+	}
+}
+
+// schemaDefinitionName lower-cases the leading initialism of a type name
+// (Repo -> repo, GC -> gc, GCRule -> gcRule).
+func schemaDefinitionName(t reflect.Type) string {
+	name := t.Name()
+	upper := 0
+	for upper < len(name) && name[upper] >= 'A' && name[upper] <= 'Z' {
+		upper++
+	}
+	if upper > 1 && upper < len(name) {
+		upper--
+	}
+	return strings.ToLower(name[:upper]) + name[upper:]
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/definitions/" + name}
+}
+
+func supportedVersions() []any {
+	var versions []any
+	for v := VersionV1; v <= CurrentVersion; v++ {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+func stringsToAny(values []string) []any {
+	out := make([]any, 0, len(values))
+	for _, v := range values {
+		out = append(out, v)
+	}
+	return out
+}
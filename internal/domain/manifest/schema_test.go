@@ -0,0 +1,371 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestSchema_AgreesWithValidate checks that the published schema accepts and rejects
+// the same files as Validate for the rules a schema can express. Cross-references
+// (duplicate aliases, preset_name pointing at a missing preset) are Validate-only.
+func TestSchema_AgreesWithValidate(t *testing.T) {
+	schemaBytes, err := SchemaJSON()
+	if err != nil {
+		t.Fatalf("SchemaJSON: %v", err)
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(schemaBytes, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	checker := schemaChecker{root: schema}
+
+	const repo = "      - alias: api\n        repo_key: github.com/org/api.git\n"
+	cases := []struct {
+		name  string
+		yaml  string
+		valid bool
+	}{
+		{name: "minimal", valid: true, yaml: "version: 2\nworkspaces: {}\n"},
+		{name: "no_version", valid: true, yaml: "workspaces: {}\n"},
+		{name: "full", valid: true, yaml: `version: 2
+presets:
+  webapp:
+    repos:
+      - git@github.com:org/api.git
+gc:
+  policies:
+    weekly:
+      description: weekly cleanup
+      rules:
+        - name: stale
+          action: remove
+          inactive_for: 14d
+        - action: keep
+workspaces:
+  PROJ-1:
+    description: fix login
+    mode: preset
+    preset_name: webapp
+    source_url: https://github.com/org/api/issues/1
+    labels: [backend, team=payments]
+    expires_at: "2026-01-02T15:04:05Z"
+    repos:
+      - alias: api
+        repo_key: github.com/org/api.git
+        branch: feature/PROJ-1
+        base_ref: origin/main
+      - alias: web
+        repo_key: github.com/org/web
+        ref: v1.2.3
+`},
+		{name: "missing_workspaces", yaml: "version: 2\n"},
+		{name: "newer_version", yaml: "version: 3\nworkspaces: {}\n"},
+		{name: "unknown_root_field", yaml: "version: 2\nworkspaces: {}\nsettings: {}\n"},
+		{name: "unknown_workspace_field", yaml: "version: 2\nworkspaces:\n  WS-1:\n    owner: me\n    repos: []\n"},
+		{name: "unknown_repo_field", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        branch: WS-1\n        remote: upstream\n"},
+		{name: "workspace_id_with_space", yaml: "version: 2\nworkspaces:\n  \"WS 1\":\n    repos: []\n"},
+		{name: "workspace_id_with_slash", yaml: "version: 2\nworkspaces:\n  feature/x:\n    repos: []\n"},
+		{name: "missing_repos", yaml: "version: 2\nworkspaces:\n  WS-1:\n    mode: repo\n"},
+		{name: "bad_mode", yaml: "version: 2\nworkspaces:\n  WS-1:\n    mode: other\n    repos: []\n"},
+		{name: "preset_mode_without_name", yaml: "version: 2\nworkspaces:\n  WS-1:\n    mode: preset\n    repos: []\n"},
+		{name: "bad_source_url", yaml: "version: 2\nworkspaces:\n  WS-1:\n    source_url: not a url\n    repos: []\n"},
+		{name: "bad_label", yaml: "version: 2\nworkspaces:\n  WS-1:\n    labels: [\"bad label\"]\n    repos: []\n"},
+		{name: "duplicate_label", yaml: "version: 2\nworkspaces:\n  WS-1:\n    labels: [a, a]\n    repos: []\n"},
+		{name: "bad_expires_at", yaml: "version: 2\nworkspaces:\n  WS-1:\n    expires_at: tomorrow\n    repos: []\n"},
+		{name: "bad_copy_changes", yaml: "version: 2\nworkspaces:\n  WS-2:\n    copied_from: WS-1\n    copy_changes: yes please\n    repos: []\n"},
+		{name: "empty_restore_from", yaml: "version: 2\nworkspaces:\n  WS-1:\n    restore_from: \"\"\n    repos: []\n"},
+		{name: "bad_renamed_from", yaml: "version: 2\nworkspaces:\n  WS-2:\n    renamed_from: a/b\n    repos: []\n"},
+		{name: "reserved_alias", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n      - alias: .gion\n        repo_key: github.com/org/api.git\n        branch: WS-1\n"},
+		{name: "bad_repo_key", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n      - alias: api\n        repo_key: org/api\n        branch: WS-1\n"},
+		{name: "bad_branch", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        branch: \"bad branch\"\n"},
+		{name: "branch_with_double_dot", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        branch: a..b\n"},
+		{name: "branch_lock_suffix", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        branch: topic.lock\n"},
+		{name: "missing_branch_and_ref", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo},
+		{name: "branch_and_ref", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        branch: WS-1\n        ref: v1.0.0\n"},
+		{name: "ref_with_base_ref", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        ref: v1.0.0\n        base_ref: origin/main\n"},
+		{name: "bad_ref", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        ref: v1..0\n"},
+		{name: "base_ref_without_origin", yaml: "version: 2\nworkspaces:\n  WS-1:\n    repos:\n" + repo + "        branch: WS-1\n        base_ref: main\n"},
+		{name: "bad_preset_name", yaml: "version: 2\npresets:\n  \"web app\":\n    repos: [git@github.com:org/api.git]\nworkspaces: {}\n"},
+		{name: "empty_preset", yaml: "version: 2\npresets:\n  web:\n    repos: []\nworkspaces: {}\n"},
+		{name: "legacy_preset_entry", yaml: "version: 2\npresets:\n  web:\n    repos:\n      - repo: git@github.com:org/api.git\nworkspaces: {}\n"},
+		{name: "preset_repo_defaults", valid: true, yaml: "version: 2\npresets:\n  web:\n    repos:\n      - git@github.com:org/app.git\n      - repo: git@github.com:org/infra.git\n        alias: ops\n        base_ref: origin/develop\n        branch: infra/{id}\nworkspaces: {}\n"},
+		{name: "preset_repo_unknown_field", yaml: "version: 2\npresets:\n  web:\n    repos:\n      - repo: git@github.com:org/api.git\n        branch: \"{id}\"\n        remote: upstream\nworkspaces: {}\n"},
+		{name: "preset_repo_bad_base_ref", yaml: "version: 2\npresets:\n  web:\n    repos:\n      - repo: git@github.com:org/api.git\n        base_ref: develop\nworkspaces: {}\n"},
+		{name: "preset_repo_unknown_placeholder", yaml: "version: 2\npresets:\n  web:\n    repos:\n      - repo: git@github.com:org/api.git\n        branch: feature/{ticket}\nworkspaces: {}\n"},
+		{name: "preset_repo_reserved_alias", yaml: "version: 2\npresets:\n  web:\n    repos:\n      - repo: git@github.com:org/api.git\n        alias: .gion\nworkspaces: {}\n"},
+		{name: "preset_extends", valid: true, yaml: "version: 2\npresets:\n  app:\n    repos: [git@github.com:org/api.git]\n  app-full:\n    extends: [app]\n    repos: [git@github.com:org/infra.git]\n  app-only:\n    extends: [app]\nworkspaces: {}\n"},
+		{name: "preset_extends_bad_name", yaml: "version: 2\npresets:\n  app:\n    repos: [git@github.com:org/api.git]\n  app-full:\n    extends: [\"bad name\"]\nworkspaces: {}\n"},
+		{name: "preset_extends_empty", yaml: "version: 2\npresets:\n  app-full:\n    extends: []\nworkspaces: {}\n"},
+		{name: "branches", valid: true, yaml: "version: 2\nbranches:\n  templates:\n    repo: feature/{user}/{id}\n    issue: feature/{user}/{issue}-{slug}\n    preset: \"{preset}/{date}-{id}\"\n  pattern: ^(feature|fix)/\nworkspaces: {}\n"},
+		{name: "branches_unknown_mode", yaml: "version: 2\nbranches:\n  templates:\n    review: pr/{id}\nworkspaces: {}\n"},
+		{name: "branches_unknown_placeholder", yaml: "version: 2\nbranches:\n  templates:\n    issue: feature/{ticket}\nworkspaces: {}\n"},
+		{name: "branches_empty_pattern", yaml: "version: 2\nbranches:\n  pattern: \"\"\nworkspaces: {}\n"},
+		{name: "hooks", valid: true, yaml: "version: 2\nhooks:\n  post_create:\n    - name: devcontainer\n      run: devcontainer up --workspace-folder .\n      on_failure: warn\n  repos:\n    github.com/org/web:\n      post_repo_add:\n        - run: npm ci\npresets:\n  web:\n    repos: [git@github.com:org/web.git]\n    hooks:\n      pre_remove:\n        - run: docker compose down\nworkspaces: {}\n"},
+		{name: "hooks_missing_run", yaml: "version: 2\nhooks:\n  post_create:\n    - name: setup\nworkspaces: {}\n"},
+		{name: "hooks_bad_on_failure", yaml: "version: 2\nhooks:\n  post_create:\n    - run: make setup\n      on_failure: ignore\nworkspaces: {}\n"},
+		{name: "hooks_repo_post_create", yaml: "version: 2\nhooks:\n  repos:\n    github.com/org/web:\n      post_create:\n        - run: npm ci\nworkspaces: {}\n"},
+		{name: "hooks_bad_repo_key", yaml: "version: 2\nhooks:\n  repos:\n    web:\n      post_repo_add:\n        - run: npm ci\nworkspaces: {}\n"},
+		{name: "carry", valid: true, yaml: "version: 2\ncarry:\n  github.com/org/web:\n    - files: [.env, .envrc, \"certs/*.pem\", \".vscode/**\"]\n    - files: [node_modules]\n      mode: symlink\n      from_workspace: MAIN\nworkspaces: {}\n"},
+		{name: "carry_missing_files", yaml: "version: 2\ncarry:\n  github.com/org/web:\n    - mode: copy\nworkspaces: {}\n"},
+		{name: "carry_parent_dir", yaml: "version: 2\ncarry:\n  github.com/org/web:\n    - files: [../secrets/.env]\nworkspaces: {}\n"},
+		{name: "carry_git_dir", yaml: "version: 2\ncarry:\n  github.com/org/web:\n    - files: [.git/config]\nworkspaces: {}\n"},
+		{name: "carry_bad_mode", yaml: "version: 2\ncarry:\n  github.com/org/web:\n    - files: [.env]\n      mode: hardlink\nworkspaces: {}\n"},
+		{name: "carry_bad_repo_key", yaml: "version: 2\ncarry:\n  web:\n    - files: [.env]\nworkspaces: {}\n"},
+		{name: "catalog_without_repo", yaml: "version: 2\npreset_catalogs:\n  - ref: main\nworkspaces: {}\n"},
+		{name: "catalog_unknown_field", yaml: "version: 2\npreset_catalogs:\n  - repo: git@github.com:org/presets.git\n    branch: main\nworkspaces: {}\n"},
+		{name: "catalog_path_outside_repo", yaml: "version: 2\npreset_catalogs:\n  - repo: git@github.com:org/presets.git\n    path: ../presets.yaml\nworkspaces: {}\n"},
+		{name: "gc_remove_without_condition", yaml: "version: 2\ngc:\n  policies:\n    p:\n      rules:\n        - action: remove\nworkspaces: {}\n"},
+		{name: "gc_bad_action", yaml: "version: 2\ngc:\n  policies:\n    p:\n      rules:\n        - action: delete\n          merged: true\nworkspaces: {}\n"},
+		{name: "gc_bad_duration", yaml: "version: 2\ngc:\n  policies:\n    p:\n      rules:\n        - action: remove\n          older_than: soon\nworkspaces: {}\n"},
+		{name: "gc_empty_rules", yaml: "version: 2\ngc:\n  policies:\n    p:\n      rules: []\nworkspaces: {}\n"},
+	}
+
+	ctx := context.Background()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rootDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(tc.yaml), 0o644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			result, err := Validate(ctx, rootDir)
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if got := len(result.Issues) == 0; got != tc.valid {
+				t.Fatalf("Validate valid = %v, want %v (%+v)", got, tc.valid, result.Issues)
+			}
+			if got := checker.valid(schema, yamlToJSONValue(t, tc.yaml)); got != tc.valid {
+				t.Fatalf("schema valid = %v, want %v", got, tc.valid)
+			}
+		})
+	}
+}
+
+func TestSchema_PropertiesMatchKnownFields(t *testing.T) {
+	schema := Schema()
+	definitions := schema["definitions"].(map[string]any)
+	cases := map[string][]string{
+		"presetCatalog":   knownFields.catalog,
+		"branches":        knownFields.branches,
+		"branchTemplates": knownFields.branchTemplates,
+		"hookConfig":      knownFields.hookConfig,
+		"hooks":           knownFields.hooks,
+		"repoHooks":       knownFields.repoHooks,
+		"hook":            knownFields.hook,
+		"carryRule":       knownFields.carryRule,
+		"workspace":       knownFields.workspace,
+		"repo":            knownFields.repo,
+		"preset":          knownFields.preset,
+		"presetRepo":      knownFields.presetRepo,
+		"gc":              knownFields.gc,
+		"gcPolicy":        knownFields.policy,
+		"gcRule":          knownFields.rule,
+	}
+	check := func(name string, obj map[string]any, want []string) {
+		var got []string
+		for key := range obj["properties"].(map[string]any) {
+			got = append(got, key)
+		}
+		sort.Strings(got)
+		want = append([]string(nil), want...)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s properties = %v, want %v", name, got, want)
+		}
+	}
+	check("root", schema, knownFields.root)
+	for name, want := range cases {
+		obj, ok := definitions[name].(map[string]any)
+		if !ok {
+			t.Fatalf("missing definition %s", name)
+		}
+		check(name, obj, want)
+	}
+}
+
+func yamlToJSONValue(t *testing.T, content string) any {
+	t.Helper()
+	var value any
+	if err := yaml.Unmarshal([]byte(content), &value); err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	return out
+}
+
+// schemaChecker evaluates the subset of JSON Schema draft-07 that Schema emits.
+type schemaChecker struct {
+	root map[string]any
+}
+
+func (c schemaChecker) valid(schemaValue any, value any) bool {
+	if b, ok := schemaValue.(bool); ok {
+		return b
+	}
+	schema := schemaValue.(map[string]any)
+	if refValue, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(refValue, "#/definitions/")
+		if !c.valid(c.root["definitions"].(map[string]any)[name], value) {
+			return false
+		}
+	}
+	if typ, ok := schema["type"].(string); ok && !schemaTypeMatches(typ, value) {
+		return false
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, candidate := range enum {
+			if reflect.DeepEqual(candidate, value) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if constValue, ok := schema["const"]; ok && !reflect.DeepEqual(constValue, value) {
+		return false
+	}
+	if s, ok := value.(string); ok {
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(s) {
+			return false
+		}
+		if n, ok := schema["minLength"].(float64); ok && utf8.RuneCountInString(s) < int(n) {
+			return false
+		}
+		if n, ok := schema["maxLength"].(float64); ok && utf8.RuneCountInString(s) > int(n) {
+			return false
+		}
+	}
+	if obj, ok := value.(map[string]any); ok {
+		properties, _ := schema["properties"].(map[string]any)
+		for _, key := range asStrings(schema["required"]) {
+			if _, ok := obj[key]; !ok {
+				return false
+			}
+		}
+		for key, v := range obj {
+			if names, ok := schema["propertyNames"]; ok && !c.valid(names, key) {
+				return false
+			}
+			if propSchema, ok := properties[key]; ok {
+				if !c.valid(propSchema, v) {
+					return false
+				}
+				continue
+			}
+			if additional, ok := schema["additionalProperties"]; ok && !c.valid(additional, v) {
+				return false
+			}
+		}
+	}
+	if items, ok := value.([]any); ok {
+		if n, ok := schema["minItems"].(float64); ok && len(items) < int(n) {
+			return false
+		}
+		seen := map[string]struct{}{}
+		for _, item := range items {
+			if itemSchema, ok := schema["items"]; ok && !c.valid(itemSchema, item) {
+				return false
+			}
+			if unique, _ := schema["uniqueItems"].(bool); unique {
+				key := fmt.Sprint(item)
+				if _, dup := seen[key]; dup {
+					return false
+				}
+				seen[key] = struct{}{}
+			}
+		}
+	}
+	for _, sub := range asSlice(schema["allOf"]) {
+		if !c.valid(sub, value) {
+			return false
+		}
+	}
+	if anyOf := asSlice(schema["anyOf"]); len(anyOf) > 0 {
+		matched := false
+		for _, sub := range anyOf {
+			if c.valid(sub, value) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if oneOf := asSlice(schema["oneOf"]); len(oneOf) > 0 {
+		matched := 0
+		for _, sub := range oneOf {
+			if c.valid(sub, value) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return false
+		}
+	}
+	if not, ok := schema["not"]; ok && c.valid(not, value) {
+		return false
+	}
+	if cond, ok := schema["if"]; ok && c.valid(cond, value) {
+		if then, ok := schema["then"]; ok && !c.valid(then, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func schemaTypeMatches(typ string, value any) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	default:
+		return false
+	}
+}
+
+func asSlice(value any) []any {
+	items, _ := value.([]any)
+	return items
+}
+
+func asStrings(value any) []string {
+	var out []string
+	for _, item := range asSlice(value) {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+)
+
+// Snapshot holds the raw contents of gion.yaml and every file it includes, so a
+// mutation written by Save can be rolled back.
+type Snapshot struct {
+	files []snapshotFile
+}
+
+type snapshotFile struct {
+	path string
+	data []byte
+}
+
+// TakeSnapshot reads gion.yaml and its included files.
+func TakeSnapshot(rootDir string) (Snapshot, error) {
+	data, err := os.ReadFile(Path(rootDir))
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("read %s: %w", FileName, err)
+	}
+	snapshot := Snapshot{files: []snapshotFile{{path: Path(rootDir), data: data}}}
+	included, err := IncludedFiles(rootDir)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for _, path := range included {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("read %s: %w", displayIncludePath(rootDir, path), err)
+		}
+		snapshot.files = append(snapshot.files, snapshotFile{path: path, data: data})
+	}
+	return snapshot, nil
+}
+
+// Restore writes the snapshotted contents back.
+func (s Snapshot) Restore() error {
+	for _, file := range s.files {
+		if err := os.WriteFile(file.path, file.data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
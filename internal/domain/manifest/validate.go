@@ -61,10 +61,36 @@ func Validate(ctx context.Context, rootDir string) (ValidationResult, error) {
 	}
 
 	root := unwrapDocument(&doc)
-	var issues []ValidationIssue
-	issues = append(issues, validateVersion(root)...)
-	issues = append(issues, validateWorkspaces(ctx, root)...)
-	issues = append(issues, validatePresets(root)...)
+	version, versionIssues := validateVersion(root)
+	if len(versionIssues) > 0 {
+		// Rules differ between versions; do not guess at the rest of the file.
+		return ValidationResult{Path: path, Issues: versionIssues}, nil
+	}
+	rootDoc := validationDoc{path: filepath.Clean(path), name: FileName, root: root, version: version}
+	docs, issues := loadIncludeDocs(rootDir, rootDoc)
+	catalogDocs, catalogIssues := loadCatalogDocs(rootDir, rootDoc)
+	docs = append(docs, catalogDocs...)
+	issues = append(issues, catalogIssues...)
+	presetNames := collectPresetNames(docs)
+	for i, d := range docs {
+		var docIssues []ValidationIssue
+		if d.version >= VersionV2 {
+			docIssues = append(docIssues, unknownFieldIssues(d.root)...)
+		}
+		docIssues = append(docIssues, validateIncludeList(d.root)...)
+		docIssues = append(docIssues, validatePresetCatalogs(d.root, i == 0)...)
+		docIssues = append(docIssues, validateBranches(d.root, i == 0)...)
+		docIssues = append(docIssues, validateHooks(d.root, i == 0)...)
+		docIssues = append(docIssues, validateCarry(ctx, d.root, i == 0)...)
+		docIssues = append(docIssues, validateWorkspaces(ctx, d.root, presetNames, i == 0)...)
+		docIssues = append(docIssues, validatePresets(d.root, d.version)...)
+		docIssues = append(docIssues, validateGC(d.root)...)
+		issues = append(issues, d.refIssues(docIssues)...)
+	}
+	issues = append(issues, includeConflictIssues(docs)...)
+	issues = append(issues, presetExtendsIssues(docs)...)
+	issues = append(issues, branchPatternIssues(docs)...)
+	issues = append(issues, renameIssues(ctx, docs)...)
 	return ValidationResult{Path: path, Issues: issues}, nil
 }
 
@@ -78,33 +104,41 @@ func unwrapDocument(node *yaml.Node) *yaml.Node {
 	return node
 }
 
-func validateVersion(root *yaml.Node) []ValidationIssue {
+// validateVersion returns the schema version of the document (files without a
+// version key are version 1).
+func validateVersion(root *yaml.Node) (int, []ValidationIssue) {
 	if root == nil || root.Kind != yaml.MappingNode {
-		return nil
+		return VersionV1, nil
 	}
 	versionNode := mappingValue(root, "version")
 	if versionNode == nil {
-		return nil
+		return VersionV1, nil
 	}
 	if versionNode.Kind != yaml.ScalarNode {
-		return []ValidationIssue{{Ref: "version", Message: "invalid value (must be an integer)"}}
+		return 0, []ValidationIssue{{Ref: "version", Message: "invalid value (must be an integer)"}}
 	}
 	v, err := strconv.Atoi(strings.TrimSpace(versionNode.Value))
 	if err != nil {
-		return []ValidationIssue{{Ref: "version", Message: "invalid value (must be an integer)"}}
+		return 0, []ValidationIssue{{Ref: "version", Message: "invalid value (must be an integer)"}}
 	}
-	if v != 1 {
-		return []ValidationIssue{{Ref: "version", Message: fmt.Sprintf("unsupported version: %d (supported: 1)", v)}}
+	if !IsSupportedVersion(v) {
+		return v, []ValidationIssue{{Ref: "version", Message: unsupportedVersionMessage(v)}}
 	}
-	return nil
+	return v, nil
 }
 
-func validateWorkspaces(ctx context.Context, root *yaml.Node) []ValidationIssue {
+// validateWorkspaces checks the workspaces of one file. presetNames holds the presets
+// declared across all files (nil when no file declares presets); workspaces are only
+// required in gion.yaml itself.
+func validateWorkspaces(ctx context.Context, root *yaml.Node, presetNames map[string]struct{}, required bool) []ValidationIssue {
 	if root == nil || root.Kind != yaml.MappingNode {
 		return []ValidationIssue{{Ref: "workspaces", Message: "missing required field"}}
 	}
 	workspacesNode := mappingValue(root, "workspaces")
 	if workspacesNode == nil {
+		if !required {
+			return nil
+		}
 		return []ValidationIssue{{Ref: "workspaces", Message: "missing required field"}}
 	}
 	if workspacesNode.Kind != yaml.MappingNode {
@@ -137,12 +171,12 @@ func validateWorkspaces(ctx context.Context, root *yaml.Node) []ValidationIssue
 			continue
 		}
 
-		issues = append(issues, validateWorkspaceEntry(ctx, workspaceID, valueNode, root)...)
+		issues = append(issues, validateWorkspaceEntry(ctx, workspaceID, valueNode, presetNames)...)
 	}
 	return issues
 }
 
-func validateWorkspaceEntry(ctx context.Context, workspaceID string, node *yaml.Node, root *yaml.Node) []ValidationIssue {
+func validateWorkspaceEntry(ctx context.Context, workspaceID string, node *yaml.Node, presetNames map[string]struct{}) []ValidationIssue {
 	var issues []ValidationIssue
 
 	mode := strings.TrimSpace(scalarValue(mappingValue(node, "mode")))
@@ -165,14 +199,12 @@ func validateWorkspaceEntry(ctx context.Context, workspaceID string, node *yaml.
 		})
 	}
 
-	if presetName != "" {
-		if presetsNode := mappingValue(root, "presets"); presetsNode != nil && presetsNode.Kind == yaml.MappingNode {
-			if !mappingHasKey(presetsNode, presetName) {
-				issues = append(issues, ValidationIssue{
-					Ref:     fmt.Sprintf("workspaces.%s.preset_name", workspaceID),
-					Message: fmt.Sprintf("preset not found: %s", presetName),
-				})
-			}
+	if presetName != "" && presetNames != nil {
+		if _, ok := presetNames[presetName]; !ok {
+			issues = append(issues, ValidationIssue{
+				Ref:     fmt.Sprintf("workspaces.%s.preset_name", workspaceID),
+				Message: fmt.Sprintf("preset not found: %s", presetName),
+			})
 		}
 	}
 
@@ -187,6 +219,17 @@ func validateWorkspaceEntry(ctx context.Context, workspaceID string, node *yaml.
 		}
 	}
 
+	issues = append(issues, validateWorkspaceLabels(workspaceID, mappingValue(node, "labels"))...)
+
+	if expiresAt := strings.TrimSpace(scalarValue(mappingValue(node, "expires_at"))); expiresAt != "" {
+		if _, err := ParseExpiresAt(expiresAt); err != nil {
+			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("workspaces.%s.expires_at", workspaceID), Message: err.Error()})
+		}
+	}
+
+	issues = append(issues, validateCopyFields(ctx, workspaceID, node)...)
+	issues = append(issues, validateRestoreFrom(workspaceID, node)...)
+
 	reposNode := mappingValue(node, "repos")
 	if reposNode == nil {
 		issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("workspaces.%s.repos", workspaceID), Message: "missing required field"})
@@ -230,14 +273,26 @@ func validateWorkspaceEntry(ctx context.Context, workspaceID string, node *yaml.
 		}
 
 		branch := strings.TrimSpace(scalarValue(mappingValue(entry, "branch")))
-		if branch == "" {
-			issues = append(issues, ValidationIssue{Ref: refPrefix + ".branch", Message: "missing required field"})
-		} else if err := workspace.ValidateBranchName(ctx, branch); err != nil {
-			issues = append(issues, ValidationIssue{Ref: refPrefix + ".branch", Message: err.Error()})
+		pinnedRef := strings.TrimSpace(scalarValue(mappingValue(entry, "ref")))
+		switch {
+		case branch == "" && pinnedRef == "":
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".branch", Message: "missing required field (or set ref)"})
+		case branch != "" && pinnedRef != "":
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".ref", Message: "cannot be combined with branch"})
+		case branch != "":
+			if err := workspace.ValidateBranchName(ctx, branch); err != nil {
+				issues = append(issues, ValidationIssue{Ref: refPrefix + ".branch", Message: err.Error()})
+			}
+		default:
+			if err := workspace.ValidatePinnedRef(pinnedRef); err != nil {
+				issues = append(issues, ValidationIssue{Ref: refPrefix + ".ref", Message: err.Error()})
+			}
 		}
 
 		baseRef := strings.TrimSpace(scalarValue(mappingValue(entry, "base_ref")))
-		if baseRef != "" {
+		if baseRef != "" && pinnedRef != "" {
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".base_ref", Message: "cannot be combined with ref"})
+		} else if baseRef != "" {
 			if !strings.HasPrefix(baseRef, "origin/") || baseRef == "origin/" {
 				issues = append(issues, ValidationIssue{Ref: refPrefix + ".base_ref", Message: "invalid value (must be origin/<branch>)"})
 			} else if err := workspace.ValidateBranchName(ctx, strings.TrimPrefix(baseRef, "origin/")); err != nil {
@@ -249,7 +304,37 @@ func validateWorkspaceEntry(ctx context.Context, workspaceID string, node *yaml.
 	return issues
 }
 
-func validatePresets(root *yaml.Node) []ValidationIssue {
+func validateWorkspaceLabels(workspaceID string, node *yaml.Node) []ValidationIssue {
+	if node == nil {
+		return nil
+	}
+	ref := fmt.Sprintf("workspaces.%s.labels", workspaceID)
+	if node.Kind != yaml.SequenceNode {
+		return []ValidationIssue{{Ref: ref, Message: "invalid value (must be a list)"}}
+	}
+	var issues []ValidationIssue
+	seen := map[string]struct{}{}
+	for i, item := range node.Content {
+		itemRef := fmt.Sprintf("%s[%d]", ref, i)
+		if item == nil || item.Kind != yaml.ScalarNode {
+			issues = append(issues, ValidationIssue{Ref: itemRef, Message: "invalid value (label must be a string)"})
+			continue
+		}
+		label := strings.TrimSpace(item.Value)
+		if err := workspace.ValidateLabel(label); err != nil {
+			issues = append(issues, ValidationIssue{Ref: itemRef, Message: err.Error()})
+			continue
+		}
+		if _, ok := seen[label]; ok {
+			issues = append(issues, ValidationIssue{Ref: itemRef, Message: fmt.Sprintf("duplicate label %q", label)})
+			continue
+		}
+		seen[label] = struct{}{}
+	}
+	return issues
+}
+
+func validatePresets(root *yaml.Node, version int) []ValidationIssue {
 	if root == nil || root.Kind != yaml.MappingNode {
 		return nil
 	}
@@ -279,7 +364,7 @@ func validatePresets(root *yaml.Node) []ValidationIssue {
 		} else {
 			seen[name] = struct{}{}
 		}
-		issues = append(issues, validatePresetEntry(name, value)...)
+		issues = append(issues, validatePresetEntry(name, value, version)...)
 	}
 	return issues
 }
@@ -295,35 +380,69 @@ func validatePresetName(name string) error {
 	return nil
 }
 
-func validatePresetEntry(name string, node *yaml.Node) []ValidationIssue {
+func validatePresetEntry(name string, node *yaml.Node, version int) []ValidationIssue {
 	refPrefix := fmt.Sprintf("presets.%s", name)
 	if node == nil || node.Kind != yaml.MappingNode {
 		return []ValidationIssue{{Ref: refPrefix, Message: "invalid value (preset entry must be a mapping)"}}
 	}
+	issues, extends := validatePresetExtendsList(name, node)
+	issues = append(issues, validatePresetHooks(refPrefix, node)...)
 	reposNode := mappingValue(node, "repos")
 	if reposNode == nil {
-		return []ValidationIssue{{Ref: refPrefix + ".repos", Message: "missing or empty"}}
+		if extends {
+			return issues
+		}
+		return append(issues, ValidationIssue{Ref: refPrefix + ".repos", Message: "missing or empty"})
 	}
 	if reposNode.Kind != yaml.SequenceNode {
-		return []ValidationIssue{{Ref: refPrefix + ".repos", Message: "invalid value (must be a list)"}}
+		return append(issues, ValidationIssue{Ref: refPrefix + ".repos", Message: "invalid value (must be a list)"})
 	}
 
-	var issues []ValidationIssue
 	var foundRepo bool
+	seenAliases := map[string]int{}
 	for i, entry := range reposNode.Content {
+		entryRef := fmt.Sprintf("%s.repos[%d]", refPrefix, i)
+		if version >= VersionV2 && entry != nil && entry.Kind == yaml.MappingNode && !hasPresetRepoDefaults(entry) {
+			issues = append(issues, ValidationIssue{Ref: entryRef, Message: fmt.Sprintf("legacy {repo: ...} form is not allowed in version %d (use a repo spec string)", version)})
+			continue
+		}
 		repoSpec, ok := presetRepoFromNode(entry)
 		if !ok {
-			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("%s.repos[%d]", refPrefix, i), Message: "invalid value (must be a string or {repo: ...})"})
+			issues = append(issues, ValidationIssue{Ref: entryRef, Message: "invalid value (must be a string or {repo: ...})"})
 			continue
 		}
 		trimmed := strings.TrimSpace(repoSpec)
 		if trimmed == "" {
-			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("%s.repos[%d]", refPrefix, i), Message: "repo spec is empty"})
+			issues = append(issues, ValidationIssue{Ref: entryRef, Message: "repo spec is empty"})
 			continue
 		}
 		foundRepo = true
-		if _, _, err := repo.Normalize(trimmed); err != nil {
-			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("%s.repos[%d]", refPrefix, i), Message: err.Error()})
+		spec, _, err := repo.Normalize(trimmed)
+		if err != nil {
+			issues = append(issues, ValidationIssue{Ref: entryRef, Message: err.Error()})
+			continue
+		}
+		alias := spec.Repo
+		if entry.Kind == yaml.MappingNode {
+			r := PresetRepo{
+				Alias:   strings.TrimSpace(scalarValue(mappingValue(entry, "alias"))),
+				BaseRef: strings.TrimSpace(scalarValue(mappingValue(entry, "base_ref"))),
+				Branch:  strings.TrimSpace(scalarValue(mappingValue(entry, "branch"))),
+			}
+			for _, field := range []string{"alias", "base_ref", "branch"} {
+				if node := mappingValue(entry, field); node != nil && strings.TrimSpace(scalarValue(node)) == "" {
+					issues = append(issues, ValidationIssue{Ref: entryRef + "." + field, Message: "invalid value (must not be empty)"})
+				}
+			}
+			issues = append(issues, presetRepoDefaultIssues(entryRef, r)...)
+			if r.Alias != "" {
+				alias = r.Alias
+			}
+		}
+		if prev, ok := seenAliases[alias]; ok {
+			issues = append(issues, ValidationIssue{Ref: entryRef, Message: fmt.Sprintf("duplicate alias %q (already used by repos[%d])", alias, prev)})
+		} else {
+			seenAliases[alias] = i
 		}
 	}
 	if !foundRepo && len(issues) == 0 {
@@ -332,6 +451,87 @@ func validatePresetEntry(name string, node *yaml.Node) []ValidationIssue {
 	return issues
 }
 
+// validatePresetExtendsList checks the shape of a preset's extends list and reports
+// whether it names at least one preset. References are checked across files by
+// presetExtendsIssues.
+func validatePresetExtendsList(name string, node *yaml.Node) ([]ValidationIssue, bool) {
+	extendsNode := mappingValue(node, "extends")
+	if extendsNode == nil {
+		return nil, false
+	}
+	refPrefix := fmt.Sprintf("presets.%s.extends", name)
+	if extendsNode.Kind != yaml.SequenceNode {
+		return []ValidationIssue{{Ref: refPrefix, Message: "invalid value (must be a list of preset names)"}}, true
+	}
+	if len(extendsNode.Content) == 0 {
+		return []ValidationIssue{{Ref: refPrefix, Message: "missing or empty"}}, true
+	}
+	var issues []ValidationIssue
+	seen := map[string]struct{}{}
+	for i, entry := range extendsNode.Content {
+		entryRef := fmt.Sprintf("%s[%d]", refPrefix, i)
+		parent := strings.TrimSpace(scalarValue(entry))
+		switch {
+		case entry == nil || entry.Kind != yaml.ScalarNode:
+			issues = append(issues, ValidationIssue{Ref: entryRef, Message: "invalid value (must be a preset name)"})
+		case validatePresetName(parent) != nil:
+			issues = append(issues, ValidationIssue{Ref: entryRef, Message: validatePresetName(parent).Error()})
+		default:
+			if _, ok := seen[parent]; ok {
+				issues = append(issues, ValidationIssue{Ref: entryRef, Message: fmt.Sprintf("duplicate preset %q", parent)})
+			}
+			seen[parent] = struct{}{}
+		}
+	}
+	return issues, true
+}
+
+// ValidatePresetRepo checks the per-repo defaults of a preset entry.
+func ValidatePresetRepo(r PresetRepo) error {
+	if issues := presetRepoDefaultIssues("", r); len(issues) > 0 {
+		return fmt.Errorf("%s: %s", issues[0].Ref, issues[0].Message)
+	}
+	return nil
+}
+
+func presetRepoDefaultIssues(refPrefix string, r PresetRepo) []ValidationIssue {
+	fieldRef := func(field string) string {
+		if refPrefix == "" {
+			return field
+		}
+		return refPrefix + "." + field
+	}
+	var issues []ValidationIssue
+	if alias := strings.TrimSpace(r.Alias); alias != "" {
+		if alias == workspace.MetadataDirName {
+			issues = append(issues, ValidationIssue{Ref: fieldRef("alias"), Message: fmt.Sprintf("invalid value: %s is reserved", alias)})
+		} else if strings.Contains(alias, "/") || strings.Contains(alias, "\\") {
+			issues = append(issues, ValidationIssue{Ref: fieldRef("alias"), Message: "invalid value (must not contain path separators)"})
+		}
+	}
+	if baseRef := strings.TrimSpace(r.BaseRef); baseRef != "" {
+		if !strings.HasPrefix(baseRef, "origin/") || baseRef == "origin/" || strings.ContainsAny(baseRef, " \t") {
+			issues = append(issues, ValidationIssue{Ref: fieldRef("base_ref"), Message: "invalid value (must be origin/<branch>)"})
+		}
+	}
+	if branch := strings.TrimSpace(r.Branch); branch != "" {
+		if err := workspace.ValidateBranchTemplate(branch); err != nil {
+			issues = append(issues, ValidationIssue{Ref: fieldRef("branch"), Message: err.Error()})
+		}
+	}
+	return issues
+}
+
+// hasPresetRepoDefaults reports whether a mapping preset entry sets anything besides repo.
+func hasPresetRepoDefaults(node *yaml.Node) bool {
+	for _, field := range []string{"alias", "base_ref", "branch"} {
+		if mappingValue(node, field) != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func presetRepoFromNode(node *yaml.Node) (string, bool) {
 	if node == nil {
 		return "", false
@@ -352,6 +552,95 @@ func presetRepoFromNode(node *yaml.Node) (string, bool) {
 	return "", false
 }
 
+func validateGC(root *yaml.Node) []ValidationIssue {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+	gcNode := mappingValue(root, "gc")
+	if gcNode == nil {
+		return nil
+	}
+	if gcNode.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: "gc", Message: "invalid value (must be a mapping)"}}
+	}
+	policiesNode := mappingValue(gcNode, "policies")
+	if policiesNode == nil {
+		return nil
+	}
+	if policiesNode.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: "gc.policies", Message: "invalid value (must be a mapping)"}}
+	}
+
+	var issues []ValidationIssue
+	for i := 0; i+1 < len(policiesNode.Content); i += 2 {
+		name := strings.TrimSpace(nodeStringValue(policiesNode.Content[i]))
+		value := policiesNode.Content[i+1]
+		refPrefix := fmt.Sprintf("gc.policies.%s", name)
+		if !presetNamePattern.MatchString(name) {
+			issues = append(issues, ValidationIssue{Ref: refPrefix, Message: fmt.Sprintf("invalid policy name: %s", name)})
+		}
+		if value == nil || value.Kind != yaml.MappingNode {
+			issues = append(issues, ValidationIssue{Ref: refPrefix, Message: "invalid value (policy must be a mapping)"})
+			continue
+		}
+		rulesNode := mappingValue(value, "rules")
+		if rulesNode == nil || rulesNode.Kind != yaml.SequenceNode || len(rulesNode.Content) == 0 {
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".rules", Message: "missing or empty"})
+			continue
+		}
+		for j, ruleNode := range rulesNode.Content {
+			issues = append(issues, validateGCRule(fmt.Sprintf("%s.rules[%d]", refPrefix, j), ruleNode)...)
+		}
+	}
+	return issues
+}
+
+func validateGCRule(refPrefix string, node *yaml.Node) []ValidationIssue {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return []ValidationIssue{{Ref: refPrefix, Message: "invalid value (rule must be a mapping)"}}
+	}
+	var rule GCRule
+	if err := node.Decode(&rule); err != nil {
+		return []ValidationIssue{{Ref: refPrefix, Message: fmt.Sprintf("invalid rule (%s)", strings.TrimSpace(err.Error()))}}
+	}
+
+	var issues []ValidationIssue
+	switch strings.TrimSpace(rule.Action) {
+	case GCActionRemove:
+		if !rule.HasConditions() {
+			issues = append(issues, ValidationIssue{Ref: refPrefix, Message: "remove rule must have at least one condition"})
+		}
+	case GCActionKeep:
+	case "":
+		issues = append(issues, ValidationIssue{Ref: refPrefix + ".action", Message: "missing required field"})
+	default:
+		issues = append(issues, ValidationIssue{Ref: refPrefix + ".action", Message: fmt.Sprintf("invalid value: %s (must be remove or keep)", rule.Action)})
+	}
+	if mode := strings.TrimSpace(rule.Mode); mode != "" {
+		switch mode {
+		case workspace.MetadataModePreset, workspace.MetadataModeRepo, workspace.MetadataModeReview, workspace.MetadataModeIssue, workspace.MetadataModeResume, workspace.MetadataModeAdd:
+		default:
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".mode", Message: fmt.Sprintf("invalid value: %s", mode)})
+		}
+	}
+	for i, label := range rule.Labels {
+		if err := workspace.ValidateLabel(strings.TrimSpace(label)); err != nil {
+			issues = append(issues, ValidationIssue{Ref: fmt.Sprintf("%s.labels[%d]", refPrefix, i), Message: err.Error()})
+		}
+	}
+	if strings.TrimSpace(rule.InactiveFor) != "" {
+		if _, err := ParseGCDuration(rule.InactiveFor); err != nil {
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".inactive_for", Message: err.Error()})
+		}
+	}
+	if strings.TrimSpace(rule.OlderThan) != "" {
+		if _, err := ParseGCDuration(rule.OlderThan); err != nil {
+			issues = append(issues, ValidationIssue{Ref: refPrefix + ".older_than", Message: err.Error()})
+		}
+	}
+	return issues
+}
+
 func validateRepoKey(repoKey string) error {
 	if strings.ContainsAny(repoKey, " \t\r\n") {
 		return fmt.Errorf("invalid repo key (must not contain whitespace)")
@@ -381,19 +670,6 @@ func mappingValue(node *yaml.Node, key string) *yaml.Node {
 	return nil
 }
 
-func mappingHasKey(node *yaml.Node, key string) bool {
-	if node == nil || node.Kind != yaml.MappingNode {
-		return false
-	}
-	for i := 0; i+1 < len(node.Content); i += 2 {
-		k := node.Content[i]
-		if k != nil && strings.TrimSpace(k.Value) == strings.TrimSpace(key) {
-			return true
-		}
-	}
-	return false
-}
-
 func scalarValue(node *yaml.Node) string {
 	if node == nil || node.Kind != yaml.ScalarNode {
 		return ""
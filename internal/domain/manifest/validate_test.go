@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidate_MissingFileIsIssue(t *testing.T) {
@@ -144,3 +145,202 @@ workspaces:
 		t.Fatalf("expected missing preset issue, got: %+v", result.Issues)
 	}
 }
+
+func TestValidate_GCPolicies(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	content := `
+version: 1
+gc:
+  policies:
+    weekly:
+      rules:
+        - action: remove
+          mode: review
+          inactive_for: 14d
+        - action: remove
+        - action: delete
+          merged: true
+        - action: keep
+          older_than: soon
+        - action: keep
+          labels: [keep, "not ok"]
+workspaces: {}
+`
+	if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := Validate(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "gc.policies.weekly.rules[1],gc.policies.weekly.rules[2].action,gc.policies.weekly.rules[3].older_than,gc.policies.weekly.rules[4].labels[1]"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
+
+func TestValidate_WorkspaceLabels(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	content := `
+version: 1
+workspaces:
+  WS-1:
+    labels: [backend, team=payments]
+    repos: []
+  WS-2:
+    labels: [backend, backend, "bad label"]
+    repos: []
+  WS-3:
+    labels: backend
+    repos: []
+`
+	if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := Validate(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "workspaces.WS-2.labels[1],workspaces.WS-2.labels[2],workspaces.WS-3.labels"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
+
+func TestValidate_PinnedRef(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	content := `
+version: 1
+workspaces:
+  REPRO-1:
+    repos:
+      - alias: tag
+        repo_key: example.com/org/tag.git
+        ref: v2.3.1
+      - alias: sha
+        repo_key: example.com/org/sha.git
+        ref: 0123abcd
+      - alias: both
+        repo_key: example.com/org/both.git
+        branch: main
+        ref: v1.0.0
+      - alias: none
+        repo_key: example.com/org/none.git
+      - alias: based
+        repo_key: example.com/org/based.git
+        ref: v1.0.0
+        base_ref: origin/main
+      - alias: bad
+        repo_key: example.com/org/bad.git
+        ref: "v1..0"
+`
+	if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := Validate(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "workspaces.REPRO-1.repos[2].ref,workspaces.REPRO-1.repos[3].branch,workspaces.REPRO-1.repos[4].base_ref,workspaces.REPRO-1.repos[5].ref"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
+
+func TestValidate_Version2(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	content := `
+version: 2
+presets:
+  webapp:
+    repos:
+      - git@github.com:org/api.git
+      - repo: git@github.com:org/web.git
+workspaces:
+  WS-1:
+    owner: someone
+    repos:
+      - alias: api
+        repo_key: github.com/org/api.git
+        branch: WS-1
+        upstream: origin
+`
+	if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := Validate(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	var refs []string
+	for _, issue := range result.Issues {
+		refs = append(refs, issue.Ref)
+	}
+	got := strings.Join(refs, ",")
+	want := "workspaces.WS-1.owner,workspaces.WS-1.repos[0].upstream,presets.webapp.repos[1]"
+	if got != want {
+		t.Fatalf("issues = %s, want %s (%+v)", got, want, result.Issues)
+	}
+}
+
+func TestValidate_NewerVersionIsRejected(t *testing.T) {
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	content := "version: 3\nworkspaces: {}\n"
+	if err := os.WriteFile(filepath.Join(rootDir, FileName), []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	result, err := Validate(ctx, rootDir)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Ref != "version" || !strings.Contains(result.Issues[0].Message, "newer gion") {
+		t.Fatalf("expected a single newer-version issue, got: %+v", result.Issues)
+	}
+	if _, err := Load(rootDir); err == nil || !strings.Contains(err.Error(), "unsupported version: 3") {
+		t.Fatalf("Load: expected unsupported version error, got %v", err)
+	}
+}
+
+func TestParseGCDuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"14d": 14 * 24 * time.Hour,
+		"2w":  14 * 24 * time.Hour,
+		"36h": 36 * time.Hour,
+	}
+	for in, want := range cases {
+		got, err := ParseGCDuration(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseGCDuration(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "d", "-1d", "soon"} {
+		if _, err := ParseGCDuration(in); err == nil {
+			t.Fatalf("ParseGCDuration(%q): expected error", in)
+		}
+	}
+}
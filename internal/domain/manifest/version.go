@@ -0,0 +1,177 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema versions of gion.yaml.
+//
+// Version 2 tightens version 1:
+//   - unknown fields are rejected instead of being silently ignored (and dropped on rewrite),
+//   - preset repos must be repo spec strings; the legacy `{repo: ...}` form is no longer accepted
+//     (a mapping entry is only allowed when it sets per-repo defaults).
+const (
+	VersionV1 = 1
+	VersionV2 = 2
+
+	// CurrentVersion is written for new files and is the target of `gion manifest migrate`.
+	CurrentVersion = VersionV2
+)
+
+// IsSupportedVersion reports whether this build of gion can read a gion.yaml of version v.
+func IsSupportedVersion(v int) bool {
+	return v >= VersionV1 && v <= CurrentVersion
+}
+
+func unsupportedVersionMessage(v int) string {
+	if v > CurrentVersion {
+		return fmt.Sprintf("unsupported version: %d (supported: %s); this file was written by a newer gion, please upgrade", v, supportedVersionsLabel())
+	}
+	return fmt.Sprintf("unsupported version: %d (supported: %s)", v, supportedVersionsLabel())
+}
+
+func supportedVersionsLabel() string {
+	parts := make([]string, 0, CurrentVersion)
+	for v := VersionV1; v <= CurrentVersion; v++ {
+		parts = append(parts, fmt.Sprintf("%d", v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// knownFields lists the mapping keys allowed at each level of a version 2 file.
+var knownFields = struct {
+	root, catalog, branches, branchTemplates, hookConfig, hooks, repoHooks, hook, carryRule, workspace, repo, preset, presetRepo, gc, policy, rule []string
+}{
+	root:            []string{"version", "include", "preset_catalogs", "branches", "hooks", "carry", "workspaces", "presets", "gc"},
+	catalog:         []string{"repo", "ref", "path"},
+	branches:        []string{"templates", "pattern"},
+	branchTemplates: []string{"repo", "issue", "preset"},
+	hookConfig:      []string{"post_create", "post_repo_add", "pre_remove", "repos"},
+	hooks:           []string{"post_create", "post_repo_add", "pre_remove"},
+	repoHooks:       []string{"post_repo_add", "pre_remove"},
+	hook:            []string{"name", "run", "on_failure"},
+	carryRule:       []string{"files", "mode", "from_workspace"},
+	workspace:       []string{"description", "mode", "preset_name", "source_url", "labels", "expires_at", "renamed_from", "copied_from", "copy_changes", "restore_from", "repos"},
+	repo:            []string{"alias", "repo_key", "branch", "ref", "base_ref"},
+	preset:          []string{"extends", "repos", "hooks"},
+	presetRepo:      []string{"repo", "alias", "base_ref", "branch"},
+	gc:              []string{"policies"},
+	policy:          []string{"description", "rules"},
+	rule:            []string{"name", "action", "mode", "labels", "expired", "inactive_for", "older_than", "merged"},
+}
+
+// unknownFieldIssues reports keys that a version 2 file does not allow.
+// Structural problems (wrong node kinds) are left to the regular validators.
+func unknownFieldIssues(root *yaml.Node) []ValidationIssue {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+	var issues []ValidationIssue
+	issues = append(issues, unknownKeys("", root, knownFields.root)...)
+	forEachSequenceItem(mappingValue(root, "preset_catalogs"), func(i int, entry *yaml.Node) {
+		issues = append(issues, unknownKeys(fmt.Sprintf("preset_catalogs[%d]", i), entry, knownFields.catalog)...)
+	})
+	branchesNode := mappingValue(root, "branches")
+	issues = append(issues, unknownKeys("branches", branchesNode, knownFields.branches)...)
+	issues = append(issues, unknownKeys("branches.templates", mappingValue(branchesNode, "templates"), knownFields.branchTemplates)...)
+	hooksNode := mappingValue(root, "hooks")
+	issues = append(issues, unknownKeys("hooks", hooksNode, knownFields.hookConfig)...)
+	issues = append(issues, unknownHookKeys("hooks", hooksNode)...)
+	forEachMappingEntry(mappingValue(hooksNode, "repos"), func(repoKey string, repoHooks *yaml.Node) {
+		ref := "hooks.repos." + repoKey
+		issues = append(issues, unknownKeys(ref, repoHooks, knownFields.repoHooks)...)
+		issues = append(issues, unknownHookKeys(ref, repoHooks)...)
+	})
+	forEachMappingEntry(mappingValue(root, "carry"), func(repoKey string, rules *yaml.Node) {
+		forEachSequenceItem(rules, func(i int, entry *yaml.Node) {
+			issues = append(issues, unknownKeys(fmt.Sprintf("carry.%s[%d]", repoKey, i), entry, knownFields.carryRule)...)
+		})
+	})
+	forEachMappingEntry(mappingValue(root, "workspaces"), func(id string, ws *yaml.Node) {
+		ref := "workspaces." + id
+		issues = append(issues, unknownKeys(ref, ws, knownFields.workspace)...)
+		forEachSequenceItem(mappingValue(ws, "repos"), func(i int, entry *yaml.Node) {
+			issues = append(issues, unknownKeys(fmt.Sprintf("%s.repos[%d]", ref, i), entry, knownFields.repo)...)
+		})
+	})
+	forEachMappingEntry(mappingValue(root, "presets"), func(name string, preset *yaml.Node) {
+		ref := "presets." + name
+		issues = append(issues, unknownKeys(ref, preset, knownFields.preset)...)
+		forEachSequenceItem(mappingValue(preset, "repos"), func(i int, entry *yaml.Node) {
+			issues = append(issues, unknownKeys(fmt.Sprintf("%s.repos[%d]", ref, i), entry, knownFields.presetRepo)...)
+		})
+		presetHooks := mappingValue(preset, "hooks")
+		issues = append(issues, unknownKeys(ref+".hooks", presetHooks, knownFields.hooks)...)
+		issues = append(issues, unknownHookKeys(ref+".hooks", presetHooks)...)
+	})
+	gcNode := mappingValue(root, "gc")
+	issues = append(issues, unknownKeys("gc", gcNode, knownFields.gc)...)
+	forEachMappingEntry(mappingValue(gcNode, "policies"), func(name string, policy *yaml.Node) {
+		ref := "gc.policies." + name
+		issues = append(issues, unknownKeys(ref, policy, knownFields.policy)...)
+		forEachSequenceItem(mappingValue(policy, "rules"), func(i int, rule *yaml.Node) {
+			issues = append(issues, unknownKeys(fmt.Sprintf("%s.rules[%d]", ref, i), rule, knownFields.rule)...)
+		})
+	})
+	return issues
+}
+
+// unknownHookKeys checks the hook entries under each hook point of node.
+func unknownHookKeys(refPrefix string, node *yaml.Node) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, point := range knownFields.hooks {
+		forEachSequenceItem(mappingValue(node, point), func(i int, entry *yaml.Node) {
+			issues = append(issues, unknownKeys(fmt.Sprintf("%s.%s[%d]", refPrefix, point, i), entry, knownFields.hook)...)
+		})
+	}
+	return issues
+}
+
+func unknownKeys(refPrefix string, node *yaml.Node, allowed []string) []ValidationIssue {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var issues []ValidationIssue
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := nodeStringValue(node.Content[i])
+		if containsString(allowed, key) {
+			continue
+		}
+		ref := key
+		if refPrefix != "" {
+			ref = refPrefix + "." + key
+		}
+		issues = append(issues, ValidationIssue{Ref: ref, Message: "unknown field"})
+	}
+	return issues
+}
+
+func forEachMappingEntry(node *yaml.Node, fn func(key string, value *yaml.Node)) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(strings.TrimSpace(nodeStringValue(node.Content[i])), node.Content[i+1])
+	}
+}
+
+func forEachSequenceItem(node *yaml.Node, fn func(i int, item *yaml.Node)) {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, item := range node.Content {
+		fn(i, item)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package preset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+type Catalog = manifest.PresetCatalog
+
+// CatalogUpdate is the result of reading the latest presets of a catalog.
+type CatalogUpdate struct {
+	Catalog   Catalog
+	CachePath string
+	Commit    string
+	// Synced is false when the catalog has never been synced.
+	Synced bool
+	// Before and After hold the `presets:` mapping of the synced and the fetched catalog.
+	Before  []byte
+	After   []byte
+	Added   []string
+	Changed []string
+	Removed []string
+
+	data     []byte
+	previous []byte
+}
+
+// HasChanges reports whether the fetched presets differ from the synced ones.
+func (u CatalogUpdate) HasChanges() bool {
+	return !u.Synced || len(u.Added)+len(u.Changed)+len(u.Removed) > 0
+}
+
+// FetchCatalog fetches the catalog repo into the bare store and reads its presets
+// at the catalog ref. Presets that collide with presets defined outside the
+// catalog are rejected.
+func FetchCatalog(ctx context.Context, rootDir string, file File, catalog Catalog) (CatalogUpdate, error) {
+	cachePath, err := manifest.CatalogCachePath(rootDir, catalog.Repo)
+	if err != nil {
+		return CatalogUpdate{}, err
+	}
+	if _, err := repo.Get(ctx, rootDir, catalog.Repo); err != nil {
+		return CatalogUpdate{}, err
+	}
+	store, err := repo.Open(ctx, rootDir, catalog.Repo, true)
+	if err != nil {
+		return CatalogUpdate{}, err
+	}
+	commit, err := resolveCatalogCommit(ctx, store.StorePath, catalog.Ref)
+	if err != nil {
+		return CatalogUpdate{}, fmt.Errorf("catalog %s: %w", repo.DisplaySpec(catalog.Repo), err)
+	}
+	filePath := catalog.FilePath()
+	data, ok, err := gitcmd.CatFileBlob(ctx, store.StorePath, commit, filePath)
+	if err != nil {
+		return CatalogUpdate{}, err
+	}
+	if !ok {
+		return CatalogUpdate{}, fmt.Errorf("catalog %s: %s not found at %s", repo.DisplaySpec(catalog.Repo), filePath, shortCommit(commit))
+	}
+	presets, err := manifest.ParseCatalog(fmt.Sprintf("%s:%s", repo.DisplaySpec(catalog.Repo), filePath), data)
+	if err != nil {
+		return CatalogUpdate{}, err
+	}
+	if err := checkCatalogConflicts(file, catalog, presets); err != nil {
+		return CatalogUpdate{}, err
+	}
+
+	update := CatalogUpdate{Catalog: catalog, CachePath: cachePath, Commit: commit}
+	var previous map[string]Preset
+	current, err := os.ReadFile(cachePath)
+	switch {
+	case err == nil:
+		update.Synced = true
+		update.previous = current
+		if previous, err = manifest.ParseCatalog(cachePath, current); err != nil {
+			previous = nil
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return CatalogUpdate{}, err
+	}
+	if update.Synced {
+		if update.Before, err = manifest.MarshalPresets(previous); err != nil {
+			return CatalogUpdate{}, err
+		}
+	}
+	if update.After, err = manifest.MarshalPresets(presets); err != nil {
+		return CatalogUpdate{}, err
+	}
+	if update.data, err = manifest.MarshalCatalog(catalog, commit, presets); err != nil {
+		return CatalogUpdate{}, err
+	}
+	update.Added, update.Changed, update.Removed = diffPresets(previous, presets)
+	return update, nil
+}
+
+// WriteCatalog stores the fetched presets of a catalog under <root>/catalogs.
+func WriteCatalog(update CatalogUpdate) error {
+	if update.Synced && bytes.Equal(update.previous, update.data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(update.CachePath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(update.CachePath, update.data, 0o600)
+}
+
+// RevertCatalog puts back the catalog file that WriteCatalog replaced.
+func RevertCatalog(update CatalogUpdate) error {
+	if !update.Synced {
+		if err := os.Remove(update.CachePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(update.CachePath, update.previous, 0o600)
+}
+
+// FindCatalog returns the catalog of file that points at repoSpec.
+func FindCatalog(file File, repoSpec string) (Catalog, bool) {
+	spec, _, err := repo.Normalize(repoSpec)
+	if err != nil {
+		return Catalog{}, false
+	}
+	for _, catalog := range file.PresetCatalogs {
+		if other, _, err := repo.Normalize(catalog.Repo); err == nil && other.RepoKey == spec.RepoKey {
+			return catalog, true
+		}
+	}
+	return Catalog{}, false
+}
+
+func resolveCatalogCommit(ctx context.Context, storePath, ref string) (string, error) {
+	rev := strings.TrimSpace(ref)
+	if rev == "" {
+		head, ok, err := gitcmd.SymbolicRef(ctx, storePath, "refs/remotes/origin/HEAD")
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", fmt.Errorf("default branch not found")
+		}
+		rev = head
+	} else if _, ok, err := gitcmd.ShowRef(ctx, storePath, "refs/remotes/origin/"+rev); err != nil {
+		return "", err
+	} else if ok {
+		rev = "refs/remotes/origin/" + rev
+	}
+	commit, err := gitcmd.RevParse(ctx, storePath, "--verify", "--quiet", rev+"^{commit}")
+	if err != nil || commit == "" {
+		return "", fmt.Errorf("ref not found: %s", ref)
+	}
+	return commit, nil
+}
+
+func checkCatalogConflicts(file File, catalog Catalog, presets map[string]Preset) error {
+	self, _, err := repo.Normalize(catalog.Repo)
+	if err != nil {
+		return err
+	}
+	for _, name := range sortedPresetNames(presets) {
+		if _, exists := file.Presets[name]; !exists {
+			continue
+		}
+		owner, fromCatalog := file.PresetCatalog(name)
+		if !fromCatalog {
+			return fmt.Errorf("catalog %s: preset %s is already defined in %s", repo.DisplaySpec(catalog.Repo), name, manifest.FileName)
+		}
+		if other, _, err := repo.Normalize(owner); err != nil || other.RepoKey != self.RepoKey {
+			return fmt.Errorf("catalog %s: preset %s is already provided by catalog %s", repo.DisplaySpec(catalog.Repo), name, repo.DisplaySpec(owner))
+		}
+	}
+	return nil
+}
+
+func diffPresets(before, after map[string]Preset) (added, changed, removed []string) {
+	for _, name := range sortedPresetNames(after) {
+		prev, ok := before[name]
+		switch {
+		case !ok:
+			added = append(added, name)
+		case !reflect.DeepEqual(prev, after[name]):
+			changed = append(changed, name)
+		}
+	}
+	for _, name := range sortedPresetNames(before) {
+		if _, ok := after[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	return added, changed, removed
+}
+
+func sortedPresetNames(presets map[string]Preset) []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -7,10 +7,12 @@ import (
 	"strings"
 
 	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
 )
 
 type File = manifest.File
 type Preset = manifest.Preset
+type Repo = manifest.PresetRepo
 
 var namePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
 
@@ -60,6 +62,33 @@ func NormalizeRepos(repos []string) []string {
 	return out
 }
 
+// RepoBranch returns the default branch of a preset repo: its branch template
+// expanded with vars, else fallback (branches.templates.preset), else the workspace ID.
+func RepoBranch(entry Repo, fallback string, vars workspace.BranchTemplateVars) (string, error) {
+	tmpl := entry.Branch
+	if strings.TrimSpace(tmpl) == "" {
+		tmpl = fallback
+	}
+	branch, err := workspace.ExpandBranchTemplate(tmpl, vars)
+	if err != nil {
+		return "", fmt.Errorf("preset %s: %s: %w", vars.Preset, entry.Repo, err)
+	}
+	return branch, nil
+}
+
+// RepoBranches returns the default branch of every repo of a preset.
+func RepoBranches(p Preset, fallback string, vars workspace.BranchTemplateVars) ([]string, error) {
+	branches := make([]string, 0, len(p.Repos))
+	for _, entry := range p.Repos {
+		branch, err := RepoBranch(entry, fallback, vars)
+		if err != nil {
+			return nil, err
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
 func Save(rootDir string, file File) error {
 	return manifest.Save(rootDir, file)
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/tasuku43/gion/internal/domain/manifest"
+	"github.com/tasuku43/gion/internal/domain/workspace"
 )
 
 func TestLoadMissingFile(t *testing.T) {
@@ -96,7 +97,7 @@ func TestSave(t *testing.T) {
 	if err != nil {
 		t.Fatalf("load %s: %v", manifest.FileName, err)
 	}
-	file.Presets["new"] = Preset{Repos: []string{"git@github.com:org/new.git"}}
+	file.Presets["new"] = Preset{Repos: []Repo{{Repo: "git@github.com:org/new.git"}}}
 	if err := Save(rootDir, file); err != nil {
 		t.Fatalf("save %s: %v", manifest.FileName, err)
 	}
@@ -126,3 +127,65 @@ func TestSaveMissingFile(t *testing.T) {
 		t.Fatalf("%s not created: %v", manifest.FileName, err)
 	}
 }
+
+func TestRepoDefaults(t *testing.T) {
+	rootDir := t.TempDir()
+	path := filepath.Join(rootDir, manifest.FileName)
+	data := []byte(`version: 2
+presets:
+  webapp:
+    repos:
+      - git@github.com:org/app.git
+      - repo: git@github.com:org/infra.git
+        alias: ops
+        base_ref: origin/develop
+        branch: infra/{id}
+workspaces: {}
+`)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write %s: %v", manifest.FileName, err)
+	}
+	file, err := Load(rootDir)
+	if err != nil {
+		t.Fatalf("load %s: %v", manifest.FileName, err)
+	}
+	webapp := file.Presets["webapp"]
+	want := []Repo{
+		{Repo: "git@github.com:org/app.git"},
+		{Repo: "git@github.com:org/infra.git", Alias: "ops", BaseRef: "origin/develop", Branch: "infra/{id}"},
+	}
+	if len(webapp.Repos) != len(want) {
+		t.Fatalf("unexpected repos: %+v", webapp.Repos)
+	}
+	for i := range want {
+		if webapp.Repos[i] != want[i] {
+			t.Fatalf("repo mismatch at %d: got %+v want %+v", i, webapp.Repos[i], want[i])
+		}
+	}
+	vars := workspace.BranchTemplateVars{WorkspaceID: "PROJ-1", Preset: "webapp"}
+	branches, err := RepoBranches(webapp, "", vars)
+	if err != nil {
+		t.Fatalf("RepoBranches: %v", err)
+	}
+	if strings.Join(branches, ",") != "PROJ-1,infra/PROJ-1" {
+		t.Fatalf("unexpected branches: %v", branches)
+	}
+	branches, err = RepoBranches(webapp, "{preset}/{id}", vars)
+	if err != nil {
+		t.Fatalf("RepoBranches: %v", err)
+	}
+	if strings.Join(branches, ",") != "webapp/PROJ-1,infra/PROJ-1" {
+		t.Fatalf("unexpected branches with fallback template: %v", branches)
+	}
+
+	if err := Save(rootDir, file); err != nil {
+		t.Fatalf("save %s: %v", manifest.FileName, err)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", manifest.FileName, err)
+	}
+	if !strings.Contains(string(saved), "- git@github.com:org/app.git\n") || !strings.Contains(string(saved), "branch: infra/{id}") {
+		t.Fatalf("unexpected saved presets:\n%s", saved)
+	}
+}
@@ -29,6 +29,11 @@ const (
 	IssueKindDuplicatePreset   = "duplicate preset name"
 	IssueKindInvalidPresetName = "invalid preset name"
 	IssueKindInvalidRepoSpec   = "invalid repo spec"
+	IssueKindInvalidRepoOption = "invalid repo option"
+	IssueKindDuplicateAlias    = "duplicate alias"
+	IssueKindInvalidExtends    = "invalid extends"
+	IssueKindPresetCycle       = "preset cycle"
+	IssueKindInvalidHooks      = "invalid hooks"
 )
 
 func Validate(rootDir string) (ValidationResult, error) {
@@ -53,7 +58,57 @@ func Validate(rootDir string) (ValidationResult, error) {
 	}
 
 	root := unwrapDocument(&doc)
-	issues := validateRootPresets(root)
+	if !hasIncludes(root) {
+		issues := validateRootPresets(root)
+		issues = append(issues, validateExtends([]*yaml.Node{root})...)
+		return ValidationResult{Path: path, Issues: issues}, nil
+	}
+
+	// Presets may live in any included file or synced catalog; names must be unique across all of them.
+	var issues []ValidationIssue
+	included, err := manifest.IncludedFiles(rootDir)
+	if err != nil {
+		issues = append(issues, joinFileIssue(err))
+	}
+	if err == nil {
+		catalogs, err := manifest.CatalogFiles(rootDir)
+		if err != nil {
+			issues = append(issues, joinFileIssue(err))
+		}
+		included = append(included, catalogs...)
+	}
+	seen := make(map[string]struct{})
+	found := false
+	nodes := []*yaml.Node{root}
+	for _, includedPath := range included {
+		data, err := os.ReadFile(includedPath)
+		if err != nil {
+			issues = append(issues, joinFileIssue(err))
+			continue
+		}
+		var includedDoc yaml.Node
+		if err := yaml.Unmarshal(data, &includedDoc); err != nil {
+			issues = append(issues, joinIssue(IssueKindInvalidYAML, "", "", fmt.Sprintf("%s: %s", includedPath, err.Error())))
+			continue
+		}
+		nodes = append(nodes, unwrapDocument(&includedDoc))
+	}
+	for _, node := range nodes {
+		presetsNode := presetsNodeOf(node)
+		if presetsNode == nil {
+			continue
+		}
+		found = true
+		if presetsNode.Kind != yaml.MappingNode {
+			issues = append(issues, joinIssue(IssueKindMissingRequired, "", "", "presets must be a mapping"))
+			continue
+		}
+		issues = append(issues, validatePresetsMap(presetsNode, seen)...)
+	}
+	if !found {
+		issues = append(issues, joinIssue(IssueKindMissingRequired, "", "", "presets"))
+	}
+	issues = append(issues, validateExtends(nodes)...)
 	return ValidationResult{Path: path, Issues: issues}, nil
 }
 
@@ -71,15 +126,7 @@ func validateRootPresets(root *yaml.Node) []ValidationIssue {
 	if root == nil || root.Kind != yaml.MappingNode {
 		return []ValidationIssue{joinIssue(IssueKindMissingRequired, "", "", "presets")}
 	}
-	var presetsNode *yaml.Node
-	for i := 0; i+1 < len(root.Content); i += 2 {
-		key := root.Content[i]
-		value := root.Content[i+1]
-		if key != nil && key.Value == "presets" {
-			presetsNode = value
-			break
-		}
-	}
+	presetsNode := presetsNodeOf(root)
 	if presetsNode == nil {
 		return []ValidationIssue{joinIssue(IssueKindMissingRequired, "", "", "presets")}
 	}
@@ -87,12 +134,36 @@ func validateRootPresets(root *yaml.Node) []ValidationIssue {
 		return []ValidationIssue{joinIssue(IssueKindMissingRequired, "", "", "presets must be a mapping")}
 	}
 
-	return validatePresetsMap(presetsNode)
+	return validatePresetsMap(presetsNode, make(map[string]struct{}))
+}
+
+func hasIncludes(root *yaml.Node) bool {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if key := root.Content[i]; key != nil && (key.Value == "include" || key.Value == "preset_catalogs") {
+			return true
+		}
+	}
+	return false
+}
+
+func presetsNodeOf(root *yaml.Node) *yaml.Node {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key := root.Content[i]
+		if key != nil && key.Value == "presets" {
+			return root.Content[i+1]
+		}
+	}
+	return nil
 }
 
-func validatePresetsMap(node *yaml.Node) []ValidationIssue {
+func validatePresetsMap(node *yaml.Node, seen map[string]struct{}) []ValidationIssue {
 	var issues []ValidationIssue
-	seen := make(map[string]struct{})
 
 	for i := 0; i+1 < len(node.Content); i += 2 {
 		key := node.Content[i]
@@ -120,24 +191,36 @@ func validatePresetEntry(name string, node *yaml.Node) []ValidationIssue {
 	if node == nil || node.Kind != yaml.MappingNode {
 		return []ValidationIssue{joinIssue(IssueKindMissingRequired, name, "", "preset entry must be a mapping")}
 	}
-	var reposNode *yaml.Node
+	var reposNode, extendsNode, hooksNode *yaml.Node
 	for i := 0; i+1 < len(node.Content); i += 2 {
 		key := node.Content[i]
 		value := node.Content[i+1]
-		if key != nil && key.Value == "repos" {
+		if key == nil {
+			continue
+		}
+		switch key.Value {
+		case "repos":
 			reposNode = value
-			break
+		case "extends":
+			extendsNode = value
+		case "hooks":
+			hooksNode = value
 		}
 	}
+	issues := validateExtendsList(name, extendsNode)
+	issues = append(issues, validateHooks(name, hooksNode)...)
 	if reposNode == nil {
+		if extendsNode != nil {
+			return issues
+		}
 		return []ValidationIssue{joinIssue(IssueKindMissingRequired, name, "", "repos")}
 	}
 	if reposNode.Kind != yaml.SequenceNode {
-		return []ValidationIssue{joinIssue(IssueKindMissingRequired, name, "", "repos must be a list")}
+		return append(issues, joinIssue(IssueKindMissingRequired, name, "", "repos must be a list"))
 	}
 
-	var issues []ValidationIssue
 	var foundRepo bool
+	seenAliases := make(map[string]struct{})
 	for _, entry := range reposNode.Content {
 		repoSpec, ok := repoFromNode(entry)
 		if !ok {
@@ -150,8 +233,29 @@ func validatePresetEntry(name string, node *yaml.Node) []ValidationIssue {
 			continue
 		}
 		foundRepo = true
-		if _, _, err := repo.Normalize(trimmed); err != nil {
+		spec, _, err := repo.Normalize(trimmed)
+		if err != nil {
 			issues = append(issues, joinIssue(IssueKindInvalidRepoSpec, name, trimmed, err.Error()))
+			continue
+		}
+		alias := spec.Repo
+		if entry.Kind == yaml.MappingNode {
+			var options Repo
+			if err := entry.Decode(&options); err != nil {
+				issues = append(issues, joinIssue(IssueKindInvalidRepoOption, name, trimmed, err.Error()))
+				continue
+			}
+			if err := manifest.ValidatePresetRepo(options); err != nil {
+				issues = append(issues, joinIssue(IssueKindInvalidRepoOption, name, trimmed, err.Error()))
+			}
+			if strings.TrimSpace(options.Alias) != "" {
+				alias = strings.TrimSpace(options.Alias)
+			}
+		}
+		if _, ok := seenAliases[alias]; ok {
+			issues = append(issues, joinIssue(IssueKindDuplicateAlias, name, trimmed, fmt.Sprintf("alias %q is used by another repo", alias)))
+		} else {
+			seenAliases[alias] = struct{}{}
 		}
 	}
 	if !foundRepo && len(issues) == 0 {
@@ -160,6 +264,72 @@ func validatePresetEntry(name string, node *yaml.Node) []ValidationIssue {
 	return issues
 }
 
+func validateExtendsList(name string, node *yaml.Node) []ValidationIssue {
+	if node == nil {
+		return nil
+	}
+	if node.Kind != yaml.SequenceNode || len(node.Content) == 0 {
+		return []ValidationIssue{joinIssue(IssueKindInvalidExtends, name, "", "extends must be a non-empty list of preset names")}
+	}
+	var issues []ValidationIssue
+	for _, entry := range node.Content {
+		if entry == nil || entry.Kind != yaml.ScalarNode {
+			issues = append(issues, joinIssue(IssueKindInvalidExtends, name, "", "extends entry must be a preset name"))
+			continue
+		}
+		if err := ValidateName(entry.Value); err != nil {
+			issues = append(issues, joinIssue(IssueKindInvalidExtends, name, "", err.Error()))
+		}
+	}
+	return issues
+}
+
+func validateHooks(name string, node *yaml.Node) []ValidationIssue {
+	if node == nil {
+		return nil
+	}
+	var hooks manifest.Hooks
+	if err := node.Decode(&hooks); err != nil {
+		return []ValidationIssue{joinIssue(IssueKindInvalidHooks, name, "", err.Error())}
+	}
+	if err := manifest.ValidateHooks(hooks); err != nil {
+		return []ValidationIssue{joinIssue(IssueKindInvalidHooks, name, "", err.Error())}
+	}
+	return nil
+}
+
+// validateExtends reports extends entries that name unknown presets and extends
+// cycles, across every file that declares presets.
+func validateExtends(nodes []*yaml.Node) []ValidationIssue {
+	presets := map[string]Preset{}
+	for _, root := range nodes {
+		presetsNode := presetsNodeOf(root)
+		if presetsNode == nil || presetsNode.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(presetsNode.Content); i += 2 {
+			name := strings.TrimSpace(presetsNode.Content[i].Value)
+			if _, ok := presets[name]; ok || name == "" {
+				continue
+			}
+			var p Preset
+			if err := presetsNode.Content[i+1].Decode(&p); err != nil {
+				continue
+			}
+			presets[name] = p
+		}
+	}
+	var issues []ValidationIssue
+	for _, issue := range manifest.ValidatePresetExtends(presets) {
+		if len(issue.Cycle) > 0 {
+			issues = append(issues, joinIssue(IssueKindPresetCycle, issue.Preset, "", strings.Join(issue.Cycle, " -> ")))
+			continue
+		}
+		issues = append(issues, joinIssue(IssueKindInvalidExtends, issue.Preset, "", issue.Message))
+	}
+	return issues
+}
+
 func repoFromNode(node *yaml.Node) (string, bool) {
 	if node == nil {
 		return "", false
@@ -147,3 +147,31 @@ func hasIssueKind(issues []ValidationIssue, kind string) bool {
 	}
 	return false
 }
+
+func TestValidatePresetsExtends(t *testing.T) {
+	rootDir := t.TempDir()
+	data := []byte(`version: 2
+presets:
+  app:
+    repos:
+      - git@github.com:org/app.git
+  app-full:
+    extends: [app]
+  a:
+    extends: [b]
+  b:
+    extends: [a]
+workspaces: {}
+`)
+	path := filepath.Join(rootDir, manifest.FileName)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write %s: %v", manifest.FileName, err)
+	}
+	result, err := Validate(rootDir)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Kind != IssueKindPresetCycle || result.Issues[0].Message != "a -> b -> a" {
+		t.Fatalf("expected one cycle issue, got %+v", result.Issues)
+	}
+}
@@ -14,7 +14,9 @@ func Add(ctx context.Context, rootDir, workspaceID, repoSpec, alias string, fetc
 	return AddWithBranch(ctx, rootDir, workspaceID, repoSpec, alias, workspaceID, "", fetch)
 }
 
-func AddWithBranch(ctx context.Context, rootDir, workspaceID, repoSpec, alias, branch, baseRef string, fetch bool) (Repo, error) {
+// AddWithBranch adds a worktree on branch, creating the branch from baseRef
+// (or the default branch) when it does not exist, then applies the carry rules.
+func AddWithBranch(ctx context.Context, rootDir, workspaceID, repoSpec, alias, branch, baseRef string, fetch bool, carry ...CarryRule) (Repo, error) {
 	if err := validateBranchName(ctx, branch); err != nil {
 		return Repo{}, err
 	}
@@ -73,11 +75,15 @@ func AddWithBranch(ctx context.Context, rootDir, workspaceID, repoSpec, alias, b
 		WorktreePath: prep.worktreePath,
 		Branch:       branch,
 	}
+	repoEntry.Carried, err = carryIntoNewWorktree(ctx, rootDir, prep, carry)
+	if err != nil {
+		return Repo{}, err
+	}
 
 	return repoEntry, nil
 }
 
-func AddWithTrackingBranch(ctx context.Context, rootDir, workspaceID, repoSpec, alias, branch, remoteRef string, fetch bool) (Repo, error) {
+func AddWithTrackingBranch(ctx context.Context, rootDir, workspaceID, repoSpec, alias, branch, remoteRef string, fetch bool, carry ...CarryRule) (Repo, error) {
 	if err := validateBranchName(ctx, branch); err != nil {
 		return Repo{}, err
 	}
@@ -116,6 +122,10 @@ func AddWithTrackingBranch(ctx context.Context, rootDir, workspaceID, repoSpec,
 		WorktreePath: prep.worktreePath,
 		Branch:       branch,
 	}
+	repoEntry.Carried, err = carryIntoNewWorktree(ctx, rootDir, prep, carry)
+	if err != nil {
+		return Repo{}, err
+	}
 
 	return repoEntry, nil
 }
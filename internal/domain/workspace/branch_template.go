@@ -0,0 +1,145 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Placeholders of a branch-name template.
+const (
+	BranchVarWorkspaceID = "id"
+	BranchVarIssue       = "issue"
+	BranchVarSlug        = "slug"
+	BranchVarUser        = "user"
+	BranchVarDate        = "date"
+	BranchVarPreset      = "preset"
+)
+
+// BranchTemplateVars holds the values substituted into a branch-name template.
+// Values that do not apply (e.g. Issue outside issue mode) are empty, and a
+// template that refers to an empty value fails to expand.
+type BranchTemplateVars struct {
+	WorkspaceID string
+	// Issue is the issue number.
+	Issue string
+	// Slug is derived from the issue title (see BranchSlug).
+	Slug string
+	// User is derived from git's user.name (see BranchSlug).
+	User string
+	// Date is the creation date as YYYY-MM-DD.
+	Date   string
+	Preset string
+}
+
+func (v BranchTemplateVars) lookup(name string) (string, bool) {
+	switch name {
+	case BranchVarWorkspaceID:
+		return v.WorkspaceID, true
+	case BranchVarIssue:
+		return v.Issue, true
+	case BranchVarSlug:
+		return v.Slug, true
+	case BranchVarUser:
+		return v.User, true
+	case BranchVarDate:
+		return v.Date, true
+	case BranchVarPreset:
+		return v.Preset, true
+	default:
+		return "", false
+	}
+}
+
+// BranchSlugMaxLength bounds the length of BranchSlug results.
+const BranchSlugMaxLength = 40
+
+// BranchSlug lower-cases s and joins its ASCII letter and digit runs with `-`,
+// e.g. "Fix login: 500 on Safari" -> "fix-login-500-on-safari".
+func BranchSlug(s string) string {
+	var words []string
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			words = append(words, b.String())
+			b.Reset()
+		}
+	}
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			continue
+		}
+		flush()
+	}
+	flush()
+	slug := ""
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if len(next) > BranchSlugMaxLength {
+			if slug == "" {
+				slug = word[:BranchSlugMaxLength]
+			}
+			break
+		}
+		slug = next
+	}
+	return slug
+}
+
+// BranchTemplateUses reports whether tmpl refers to the placeholder name.
+func BranchTemplateUses(tmpl, name string) bool {
+	return strings.Contains(tmpl, "{"+name+"}")
+}
+
+// ExpandBranchTemplate replaces `{name}` placeholders of tmpl (e.g. `feature/{id}`)
+// with the values of vars. An empty template expands to the workspace ID.
+func ExpandBranchTemplate(tmpl string, vars BranchTemplateVars) (string, error) {
+	trimmed := strings.TrimSpace(tmpl)
+	if trimmed == "" {
+		return vars.WorkspaceID, nil
+	}
+	var b strings.Builder
+	rest := trimmed
+	for {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			b.WriteString(rest)
+			break
+		}
+		if rest[open] == '}' {
+			return "", fmt.Errorf("invalid branch template %q: unexpected }", tmpl)
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("invalid branch template %q: missing }", tmpl)
+		}
+		name := rest[open+1 : open+end]
+		value, ok := vars.lookup(name)
+		if !ok {
+			return "", fmt.Errorf("invalid branch template %q: unknown placeholder {%s}", tmpl, name)
+		}
+		if value == "" {
+			return "", fmt.Errorf("branch template %q: {%s} has no value here", tmpl, name)
+		}
+		b.WriteString(rest[:open])
+		b.WriteString(value)
+		rest = rest[open+end+1:]
+	}
+	return b.String(), nil
+}
+
+// ValidateBranchTemplate checks the placeholders of a branch-name template.
+// The expanded name is checked against git's rules when a branch is created.
+func ValidateBranchTemplate(tmpl string) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return fmt.Errorf("branch template is empty")
+	}
+	if strings.ContainsAny(strings.TrimSpace(tmpl), " \t") {
+		return fmt.Errorf("invalid branch template %q: must not contain spaces", tmpl)
+	}
+	_, err := ExpandBranchTemplate(tmpl, BranchTemplateVars{WorkspaceID: "x", Issue: "1", Slug: "x", User: "x", Date: "x", Preset: "x"})
+	return err
+}
@@ -0,0 +1,78 @@
+package workspace
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandBranchTemplate(t *testing.T) {
+	t.Parallel()
+
+	vars := BranchTemplateVars{WorkspaceID: "PROJ-1", Issue: "42", Slug: "fix-login", User: "alice", Date: "2026-01-02", Preset: "webapp"}
+	cases := []struct {
+		name     string
+		tmpl     string
+		want     string
+		contains string
+	}{
+		{name: "empty", tmpl: "", want: "PROJ-1"},
+		{name: "literal", tmpl: "develop-sync", want: "develop-sync"},
+		{name: "id", tmpl: "feature/{id}", want: "feature/PROJ-1"},
+		{name: "id_and_preset", tmpl: "{preset}/{id}-work", want: "webapp/PROJ-1-work"},
+		{name: "issue_vars", tmpl: "feature/{user}/{issue}-{slug}", want: "feature/alice/42-fix-login"},
+		{name: "date", tmpl: "{date}/{id}", want: "2026-01-02/PROJ-1"},
+		{name: "unknown", tmpl: "feature/{ticket}", contains: "unknown placeholder {ticket}"},
+		{name: "unclosed", tmpl: "feature/{id", contains: "missing }"},
+		{name: "stray_close", tmpl: "feature/id}", contains: "unexpected }"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ExpandBranchTemplate(tc.tmpl, vars)
+			if tc.contains != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.contains) {
+					t.Fatalf("expected error containing %q, got %v", tc.contains, err)
+				}
+				if ValidateBranchTemplate(tc.tmpl) == nil {
+					t.Fatalf("expected ValidateBranchTemplate to reject %q", tc.tmpl)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpandBranchTemplate_EmptyValue(t *testing.T) {
+	t.Parallel()
+
+	_, err := ExpandBranchTemplate("feature/{issue}", BranchTemplateVars{WorkspaceID: "PROJ-1"})
+	if err == nil || !strings.Contains(err.Error(), "{issue} has no value") {
+		t.Fatalf("expected empty value error, got %v", err)
+	}
+}
+
+func TestBranchSlug(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"Fix login: 500 on Safari":  "fix-login-500-on-safari",
+		"  Tasuku Ishikawa ":        "tasuku-ishikawa",
+		"[bug] Crash / panic!!":     "bug-crash-panic",
+		"日本語のみ":                     "",
+		strings.Repeat("word ", 20): "word-word-word-word-word-word-word-word",
+	}
+	for in, want := range cases {
+		if got := BranchSlug(in); got != want {
+			t.Fatalf("BranchSlug(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
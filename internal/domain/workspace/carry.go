@@ -0,0 +1,292 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/output"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// Carry modes: copy files into the new worktree, or symlink them to the source.
+const (
+	CarryModeCopy    = "copy"
+	CarryModeSymlink = "symlink"
+)
+
+// CarryRule brings untracked files (e.g. .env, local certs, IDE settings) into
+// a worktree right after it is added.
+type CarryRule struct {
+	// Patterns are globs relative to the source directory. `*`, `?` and `[...]`
+	// match within a path segment and `**` matches any number of segments.
+	// A pattern matching a directory carries the whole directory.
+	Patterns []string
+	// Mode is CarryModeCopy (default) or CarryModeSymlink.
+	Mode string
+	// SourceWorkspace takes files from the worktree of the same repo in another
+	// workspace. When empty, files come from TemplateDir.
+	SourceWorkspace string
+}
+
+// CarriedFile is a file or directory carried into a worktree.
+type CarriedFile struct {
+	// Path is relative to the worktree, slash-separated.
+	Path   string
+	Source string
+	Mode   string
+}
+
+// TemplateDir returns the directory holding files carried into worktrees of a repo
+// by default: <root>/templates/<host>/<owner>/<repo>.
+func TemplateDir(rootDir, repoKey string) string {
+	repoKey = strings.TrimSuffix(strings.TrimSpace(repoKey), ".git")
+	return filepath.Join(paths.TemplatesRoot(rootDir), filepath.FromSlash(repoKey))
+}
+
+// ValidateCarryPattern checks a carry glob: relative, inside the source and valid glob syntax.
+func ValidateCarryPattern(pattern string) error {
+	trimmed := strings.TrimSpace(pattern)
+	if trimmed == "" {
+		return fmt.Errorf("pattern is empty")
+	}
+	if strings.HasPrefix(trimmed, "/") || strings.Contains(trimmed, `\`) {
+		return fmt.Errorf("pattern %q must be a relative slash-separated path", pattern)
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		switch segment {
+		case "", ".", "..":
+			return fmt.Errorf("pattern %q must not contain empty, . or .. segments", pattern)
+		case ".git":
+			return fmt.Errorf("pattern %q must not refer to .git", pattern)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// carryIntoNewWorktree applies rules to the worktree just added for prep. When
+// carrying fails the worktree is removed again, so the next apply retries the
+// add instead of keeping a worktree without its carried files.
+func carryIntoNewWorktree(ctx context.Context, rootDir string, prep addPrep, rules []CarryRule) ([]CarriedFile, error) {
+	carried, err := carryFiles(ctx, rootDir, prep, rules)
+	if err == nil {
+		return carried, nil
+	}
+	gitcmd.Logf("git worktree remove --force %s", prep.worktreePath)
+	if removeErr := gitcmd.WorktreeRemove(ctx, prep.store.StorePath, prep.worktreePath, true); removeErr != nil {
+		return nil, errors.Join(err, removeErr)
+	}
+	return nil, err
+}
+
+// carryFiles applies rules to a new worktree. Files that already exist in the
+// worktree (e.g. tracked files) are left alone. A missing source directory
+// carries nothing.
+func carryFiles(ctx context.Context, rootDir string, prep addPrep, rules []CarryRule) ([]CarriedFile, error) {
+	var carried []CarriedFile
+	worktreePath := prep.worktreePath
+	for _, rule := range rules {
+		sourceDir, err := carrySourceDir(ctx, rootDir, prep, rule)
+		if err != nil {
+			return carried, err
+		}
+		if exists, err := paths.DirExists(sourceDir); err != nil {
+			return carried, err
+		} else if !exists {
+			continue
+		}
+		mode := strings.TrimSpace(rule.Mode)
+		if mode == "" {
+			mode = CarryModeCopy
+		}
+		err = filepath.WalkDir(sourceDir, func(current string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if current == sourceDir {
+				return nil
+			}
+			rel, err := filepath.Rel(sourceDir, current)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if entry.IsDir() && entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			if !matchCarryPatterns(rule.Patterns, rel) {
+				return nil
+			}
+			target := filepath.Join(worktreePath, filepath.FromSlash(rel))
+			if _, err := os.Lstat(target); err == nil {
+				output.Logf("carry skip %s (exists)", rel)
+				if entry.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+			if err := carryEntry(current, target, mode); err != nil {
+				return fmt.Errorf("carry %s: %w", rel, err)
+			}
+			output.Logf("carry %s %s", mode, rel)
+			carried = append(carried, CarriedFile{Path: rel, Source: current, Mode: mode})
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return carried, err
+		}
+	}
+	return carried, nil
+}
+
+// carrySourceDir returns the template directory of the repo, or the worktree
+// sharing the repo store of prep in the rule's source workspace.
+func carrySourceDir(ctx context.Context, rootDir string, prep addPrep, rule CarryRule) (string, error) {
+	sourceWorkspace := strings.TrimSpace(rule.SourceWorkspace)
+	if sourceWorkspace == "" {
+		return TemplateDir(rootDir, prep.spec.RepoKey), nil
+	}
+	wsDir := WorkspaceDir(rootDir, sourceWorkspace)
+	if exists, err := paths.DirExists(wsDir); err != nil {
+		return "", err
+	} else if !exists {
+		return "", fmt.Errorf("carry source workspace does not exist: %s", sourceWorkspace)
+	}
+	repos, _, err := ScanRepos(ctx, wsDir)
+	if err != nil {
+		return "", err
+	}
+	want := canonicalPath(prep.store.StorePath)
+	for _, r := range repos {
+		if r.StorePath != "" && canonicalPath(r.StorePath) == want {
+			return r.WorktreePath, nil
+		}
+	}
+	return "", fmt.Errorf("carry source workspace %s has no worktree of %s", sourceWorkspace, prep.spec.RepoKey)
+}
+
+func canonicalPath(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return filepath.Clean(p)
+}
+
+func carryEntry(source, target, mode string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	if mode == CarryModeSymlink {
+		return os.Symlink(source, target)
+	}
+	info, err := os.Lstat(source)
+	if err != nil {
+		return err
+	}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(source)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, target)
+	case info.IsDir():
+		return copyDir(source, target)
+	default:
+		return copyFile(source, target, info.Mode().Perm())
+	}
+}
+
+func copyDir(source, target string) error {
+	return filepath.WalkDir(source, func(current string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(source, current)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(target, rel)
+		info, err := os.Lstat(current)
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(dest, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(current)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, dest)
+		default:
+			return copyFile(current, dest, info.Mode().Perm())
+		}
+	})
+}
+
+func copyFile(source, target string, perm fs.FileMode) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func matchCarryPatterns(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if matchCarryPattern(strings.Split(strings.TrimSpace(pattern), "/"), strings.Split(rel, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCarryPattern matches path segments against pattern segments, where a
+// `**` segment matches zero or more path segments.
+func matchCarryPattern(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchCarryPattern(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, err := path.Match(pattern[0], segments[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchCarryPattern(pattern[1:], segments[1:])
+}
@@ -0,0 +1,183 @@
+package workspace_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestWorkspaceAddCarriesFiles(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	repoSpec := "https://example.com/org/repo.git"
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+
+	templateDir := workspace.TemplateDir(rootDir, "example.com/org/repo.git")
+	for name, content := range map[string]string{
+		".env":                  "TOKEN=1\n",
+		".vscode/settings.json": "{}\n",
+		"certs/dev.pem":         "cert\n",
+		"certs/notes.txt":       "skip\n",
+		"README.md":             "template\n",
+	} {
+		path := filepath.Join(templateDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir template: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write template: %v", err)
+		}
+	}
+
+	if _, err := workspace.New(ctx, rootDir, "WS-1"); err != nil {
+		t.Fatalf("workspace new: %v", err)
+	}
+	added, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, "", "WS-1", "", true, workspace.CarryRule{
+		Patterns: []string{".env", ".vscode", "certs/*.pem", "README.md"},
+	})
+	if err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	var carried []string
+	for _, file := range added.Carried {
+		carried = append(carried, file.Path)
+	}
+	sort.Strings(carried)
+	if strings.Join(carried, ",") != ".env,.vscode,certs/dev.pem" {
+		t.Fatalf("carried = %v", carried)
+	}
+	worktreePath := workspace.WorktreePath(rootDir, "WS-1", "repo")
+	if data, err := os.ReadFile(filepath.Join(worktreePath, ".vscode", "settings.json")); err != nil || string(data) != "{}\n" {
+		t.Fatalf("settings.json not copied: %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(worktreePath, "README.md")); err != nil || string(data) != "hello\n" {
+		t.Fatalf("tracked README.md was overwritten: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "certs", "notes.txt")); !os.IsNotExist(err) {
+		t.Fatalf("unmatched file carried: %v", err)
+	}
+
+	if _, err := workspace.New(ctx, rootDir, "WS-2"); err != nil {
+		t.Fatalf("workspace new: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-2", repoSpec, "", "WS-2", "", false, workspace.CarryRule{
+		Patterns:        []string{"**/*.pem"},
+		Mode:            workspace.CarryModeSymlink,
+		SourceWorkspace: "WS-1",
+	}); err != nil {
+		t.Fatalf("workspace add: %v", err)
+	}
+	link := filepath.Join(workspace.WorktreePath(rootDir, "WS-2", "repo"), "certs", "dev.pem")
+	target, err := os.Readlink(link)
+	if err != nil {
+		t.Fatalf("readlink: %v", err)
+	}
+	if target != filepath.Join(worktreePath, "certs", "dev.pem") {
+		t.Fatalf("symlink target = %s", target)
+	}
+	if _, err := workspace.New(ctx, rootDir, "WS-3"); err != nil {
+		t.Fatalf("workspace new: %v", err)
+	}
+	if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-3", repoSpec, "", "WS-3", "", false, workspace.CarryRule{
+		Patterns:        []string{".env"},
+		SourceWorkspace: "MISSING",
+	}); err == nil {
+		t.Fatalf("expected carry from a missing workspace to fail")
+	}
+	failedPath := workspace.WorktreePath(rootDir, "WS-3", "repo")
+	if _, err := os.Stat(failedPath); !os.IsNotExist(err) {
+		t.Fatalf("expected worktree to be removed after a failed carry: %v", err)
+	}
+	retried, err := workspace.AddWithBranch(ctx, rootDir, "WS-3", repoSpec, "", "WS-3", "", false, workspace.CarryRule{
+		Patterns: []string{".env"},
+	})
+	if err != nil {
+		t.Fatalf("workspace add retry: %v", err)
+	}
+	if len(retried.Carried) != 1 || retried.Carried[0].Path != ".env" {
+		t.Fatalf("carried on retry = %+v", retried.Carried)
+	}
+	runGit(t, seedDir, "tag", "v1")
+	runGit(t, seedDir, "push", "origin", "v1")
+	if _, err := workspace.New(ctx, rootDir, "WS-4"); err != nil {
+		t.Fatalf("workspace new: %v", err)
+	}
+	if _, err := workspace.AddDetached(ctx, rootDir, "WS-4", repoSpec, "", "v1", false, workspace.CarryRule{
+		Patterns:        []string{".env"},
+		SourceWorkspace: "MISSING",
+	}); err == nil {
+		t.Fatalf("expected carry into a pinned worktree from a missing workspace to fail")
+	}
+	pinnedPath := workspace.WorktreePath(rootDir, "WS-4", "repo")
+	if _, err := os.Stat(pinnedPath); !os.IsNotExist(err) {
+		t.Fatalf("expected pinned worktree to be removed after a failed carry: %v", err)
+	}
+	pinned, err := workspace.AddDetached(ctx, rootDir, "WS-4", repoSpec, "", "v1", false, workspace.CarryRule{
+		Patterns: []string{".env"},
+	})
+	if err != nil {
+		t.Fatalf("pinned add retry: %v", err)
+	}
+	if len(pinned.Carried) != 1 || pinned.Carried[0].Path != ".env" {
+		t.Fatalf("carried into pinned worktree on retry = %+v", pinned.Carried)
+	}
+}
+
+func TestValidateCarryPattern(t *testing.T) {
+	for _, pattern := range []string{".env", ".vscode/**", "certs/*.pem", "**/local.properties"} {
+		if err := workspace.ValidateCarryPattern(pattern); err != nil {
+			t.Fatalf("ValidateCarryPattern(%q): %v", pattern, err)
+		}
+	}
+	for _, pattern := range []string{"", "/etc/passwd", "../secrets", "a//b", ".git/config", "[a"} {
+		if err := workspace.ValidateCarryPattern(pattern); err == nil {
+			t.Fatalf("ValidateCarryPattern(%q) succeeded", pattern)
+		}
+	}
+}
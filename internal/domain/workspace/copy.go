@@ -0,0 +1,64 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/output"
+)
+
+// CopyChanges brings the uncommitted work of the worktree at sourcePath into the
+// worktree at targetPath: staged and unstaged changes are applied as one patch
+// and untracked (non-ignored) files are copied. Untracked files that already
+// exist in the target are left alone. The target is expected to be at the
+// source's HEAD commit.
+func CopyChanges(ctx context.Context, sourcePath, targetPath string) error {
+	patch, err := gitcmd.DiffBinaryHEAD(ctx, sourcePath)
+	if err != nil {
+		return err
+	}
+	if patch != "" {
+		if err := applyPatch(ctx, targetPath, patch); err != nil {
+			return err
+		}
+	}
+
+	untracked, err := gitcmd.UntrackedFiles(ctx, sourcePath)
+	if err != nil {
+		return err
+	}
+	for _, rel := range untracked {
+		target := filepath.Join(targetPath, filepath.FromSlash(rel))
+		if _, err := os.Lstat(target); err == nil {
+			output.Logf("copy skip %s (exists)", rel)
+			continue
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		if err := carryEntry(filepath.Join(sourcePath, filepath.FromSlash(rel)), target, CarryModeCopy); err != nil {
+			return fmt.Errorf("copy %s: %w", rel, err)
+		}
+		output.Logf("copy %s", rel)
+	}
+	return nil
+}
+
+func applyPatch(ctx context.Context, worktreePath, patch string) error {
+	file, err := os.CreateTemp("", "gion-*.patch")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(patch); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	gitcmd.Logf("git apply --binary %s", file.Name())
+	return gitcmd.ApplyPatch(ctx, worktreePath, file.Name())
+}
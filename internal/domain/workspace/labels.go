@@ -0,0 +1,104 @@
+package workspace
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LabelPattern allows plain labels ("backend") as well as key/value style tags
+// ("team=payments", "jira:PROJ-1").
+const LabelPattern = `^[A-Za-z0-9][A-Za-z0-9._:/=-]*$`
+
+// MaxLabelLength is the longest label ValidateLabel accepts.
+const MaxLabelLength = 63
+
+var labelPattern = regexp.MustCompile(LabelPattern)
+
+// ValidateLabel reports whether label can be stored on a workspace.
+func ValidateLabel(label string) error {
+	if label == "" {
+		return fmt.Errorf("label is required")
+	}
+	if len(label) > MaxLabelLength {
+		return fmt.Errorf("label is too long (max %d): %s", MaxLabelLength, label)
+	}
+	if !labelPattern.MatchString(label) {
+		return fmt.Errorf("invalid label: %s", label)
+	}
+	return nil
+}
+
+// NormalizeLabels trims, de-duplicates and sorts labels. Empty entries are dropped.
+func NormalizeLabels(labels []string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+	seen := make(map[string]struct{}, len(labels))
+	var normalized []string
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" {
+			continue
+		}
+		if _, ok := seen[label]; ok {
+			continue
+		}
+		seen[label] = struct{}{}
+		normalized = append(normalized, label)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ParseLabelFilter splits repeated/comma-separated --label values into a normalized filter.
+func ParseLabelFilter(values []string) ([]string, error) {
+	var labels []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if err := ValidateLabel(part); err != nil {
+				return nil, err
+			}
+			labels = append(labels, part)
+		}
+	}
+	return NormalizeLabels(labels), nil
+}
+
+// MatchLabels reports whether labels contains every label in filter.
+// An empty filter matches everything.
+func MatchLabels(labels, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	have := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		have[strings.TrimSpace(label)] = struct{}{}
+	}
+	for _, want := range filter {
+		if _, ok := have[want]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualLabels reports whether two label sets are equal after normalization.
+func EqualLabels(a, b []string) bool {
+	a = NormalizeLabels(a)
+	b = NormalizeLabels(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -12,6 +12,7 @@ type Entry struct {
 	WorkspaceID   string
 	WorkspacePath string
 	Description   string
+	Labels        []string
 }
 
 func List(rootDir string) ([]Entry, []error, error) {
@@ -40,17 +41,20 @@ func List(rootDir string) ([]Entry, []error, error) {
 		wsPath := WorkspaceDir(rootDir, wsID)
 
 		description := ""
+		var labels []string
 		meta, err := LoadMetadata(wsPath)
 		if err != nil {
 			warnings = append(warnings, fmt.Errorf("workspace %s metadata: %w", wsID, err))
-		} else if strings.TrimSpace(meta.Description) != "" {
+		} else {
 			description = strings.TrimSpace(meta.Description)
+			labels = NormalizeLabels(meta.Labels)
 		}
 
 		result := Entry{
 			WorkspaceID:   wsID,
 			WorkspacePath: wsPath,
 			Description:   description,
+			Labels:        labels,
 		}
 		results = append(results, result)
 	}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 const (
@@ -24,11 +25,15 @@ const (
 )
 
 type Metadata struct {
-	Description string `json:"description,omitempty"`
-	Mode        string `json:"mode,omitempty"`
-	PresetName  string `json:"preset_name,omitempty"`
-	SourceURL   string `json:"source_url,omitempty"`
-	BaseBranch  string `json:"base_branch,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Mode        string   `json:"mode,omitempty"`
+	PresetName  string   `json:"preset_name,omitempty"`
+	SourceURL   string   `json:"source_url,omitempty"`
+	BaseBranch  string   `json:"base_branch,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	ExpiresAt   string   `json:"expires_at,omitempty"`
+	// Pins maps repo aliases to the tag/commit their detached worktree was pinned to.
+	Pins map[string]string `json:"pins,omitempty"`
 }
 
 func LoadMetadata(wsDir string) (Metadata, error) {
@@ -55,7 +60,7 @@ func SaveMetadata(wsDir string, meta Metadata) error {
 		return fmt.Errorf("workspace dir is required")
 	}
 	meta = normalizeMetadata(meta)
-	if meta == (Metadata{}) {
+	if metadataEmpty(meta) {
 		return nil
 	}
 	if err := validateMetadata(meta); err != nil {
@@ -75,6 +80,50 @@ func SaveMetadata(wsDir string, meta Metadata) error {
 	return nil
 }
 
+// SaveLabels replaces the labels recorded in an existing workspace's metadata.
+func SaveLabels(wsDir string, labels []string) error {
+	return updateMetadata(wsDir, func(meta *Metadata) {
+		meta.Labels = labels
+	})
+}
+
+// SaveExpiresAt replaces the expiry recorded in an existing workspace's metadata.
+// An empty value clears it.
+func SaveExpiresAt(wsDir string, expiresAt string) error {
+	return updateMetadata(wsDir, func(meta *Metadata) {
+		meta.ExpiresAt = expiresAt
+	})
+}
+
+// SavePin records (or, with an empty ref, clears) the pinned ref for a repo alias.
+func SavePin(wsDir, alias, ref string) error {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return fmt.Errorf("alias is required")
+	}
+	return updateMetadata(wsDir, func(meta *Metadata) {
+		if meta.Pins == nil {
+			meta.Pins = map[string]string{}
+		}
+		meta.Pins[alias] = ref
+	})
+}
+
+func updateMetadata(wsDir string, update func(*Metadata)) error {
+	meta, err := LoadMetadata(wsDir)
+	if err != nil {
+		return err
+	}
+	update(&meta)
+	if metadataEmpty(normalizeMetadata(meta)) {
+		if err := os.Remove(metadataPath(wsDir)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove metadata: %w", err)
+		}
+		return nil
+	}
+	return SaveMetadata(wsDir, meta)
+}
+
 func ReadDescription(wsDir string) (string, error) {
 	meta, err := LoadMetadata(wsDir)
 	if err != nil {
@@ -93,9 +142,34 @@ func normalizeMetadata(meta Metadata) Metadata {
 	meta.PresetName = strings.TrimSpace(meta.PresetName)
 	meta.SourceURL = strings.TrimSpace(meta.SourceURL)
 	meta.BaseBranch = strings.TrimSpace(meta.BaseBranch)
+	meta.Labels = NormalizeLabels(meta.Labels)
+	meta.ExpiresAt = strings.TrimSpace(meta.ExpiresAt)
+	meta.Pins = normalizePins(meta.Pins)
 	return meta
 }
 
+func metadataEmpty(meta Metadata) bool {
+	return meta.Description == "" && meta.Mode == "" && meta.PresetName == "" &&
+		meta.SourceURL == "" && meta.BaseBranch == "" && len(meta.Labels) == 0 &&
+		meta.ExpiresAt == "" && len(meta.Pins) == 0
+}
+
+func normalizePins(pins map[string]string) map[string]string {
+	var normalized map[string]string
+	for alias, ref := range pins {
+		alias = strings.TrimSpace(alias)
+		ref = strings.TrimSpace(ref)
+		if alias == "" || ref == "" {
+			continue
+		}
+		if normalized == nil {
+			normalized = map[string]string{}
+		}
+		normalized[alias] = ref
+	}
+	return normalized
+}
+
 func validateMetadata(meta Metadata) error {
 	if meta.Mode != "" {
 		switch meta.Mode {
@@ -121,5 +195,20 @@ func validateMetadata(meta Metadata) error {
 			return fmt.Errorf("invalid metadata base_branch (must be origin/<branch>): %s", meta.BaseBranch)
 		}
 	}
+	for _, label := range meta.Labels {
+		if err := ValidateLabel(label); err != nil {
+			return fmt.Errorf("invalid metadata labels: %w", err)
+		}
+	}
+	if meta.ExpiresAt != "" {
+		if _, err := time.Parse(time.RFC3339, meta.ExpiresAt); err != nil {
+			return fmt.Errorf("invalid metadata expires_at: %s", meta.ExpiresAt)
+		}
+	}
+	for alias, ref := range meta.Pins {
+		if err := ValidatePinnedRef(ref); err != nil {
+			return fmt.Errorf("invalid metadata pins.%s: %w", alias, err)
+		}
+	}
 	return nil
 }
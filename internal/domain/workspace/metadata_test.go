@@ -3,6 +3,7 @@ package workspace
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -80,3 +81,74 @@ func TestSaveMetadataBaseBranchValidation(t *testing.T) {
 		t.Fatalf("expected error for base_branch origin/ with empty branch")
 	}
 }
+
+func TestSaveLabels(t *testing.T) {
+	wsDir := t.TempDir()
+	if err := SaveMetadata(wsDir, Metadata{Mode: MetadataModeRepo, Labels: []string{" team=payments", "backend", "backend"}}); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	meta, err := LoadMetadata(wsDir)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if got := strings.Join(meta.Labels, ","); got != "backend,team=payments" {
+		t.Fatalf("labels not normalized: %q", got)
+	}
+
+	if err := SaveLabels(wsDir, []string{"frontend"}); err != nil {
+		t.Fatalf("save labels: %v", err)
+	}
+	meta, err = LoadMetadata(wsDir)
+	if err != nil {
+		t.Fatalf("load metadata: %v", err)
+	}
+	if meta.Mode != MetadataModeRepo || strings.Join(meta.Labels, ",") != "frontend" {
+		t.Fatalf("unexpected metadata after SaveLabels: %+v", meta)
+	}
+
+	if err := SaveLabels(wsDir, []string{"bad label"}); err == nil {
+		t.Fatalf("expected invalid label error")
+	}
+}
+
+func TestSaveLabels_RemovesEmptyMetadata(t *testing.T) {
+	wsDir := t.TempDir()
+	if err := SaveMetadata(wsDir, Metadata{Labels: []string{"tmp"}}); err != nil {
+		t.Fatalf("save metadata: %v", err)
+	}
+	if err := SaveLabels(wsDir, nil); err != nil {
+		t.Fatalf("save labels: %v", err)
+	}
+	if _, err := os.Stat(metadataPath(wsDir)); !os.IsNotExist(err) {
+		t.Fatalf("expected metadata file to be removed, got: %v", err)
+	}
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := []string{"backend", "team=payments"}
+	cases := []struct {
+		filter []string
+		want   bool
+	}{
+		{nil, true},
+		{[]string{"backend"}, true},
+		{[]string{"backend", "team=payments"}, true},
+		{[]string{"backend", "frontend"}, false},
+	}
+	for _, tc := range cases {
+		if got := MatchLabels(labels, tc.filter); got != tc.want {
+			t.Fatalf("MatchLabels(%v): expected %v, got %v", tc.filter, tc.want, got)
+		}
+	}
+
+	filter, err := ParseLabelFilter([]string{"team=payments, backend", "backend"})
+	if err != nil {
+		t.Fatalf("ParseLabelFilter: %v", err)
+	}
+	if got := strings.Join(filter, ","); got != "backend,team=payments" {
+		t.Fatalf("unexpected filter: %q", got)
+	}
+	if _, err := ParseLabelFilter([]string{"-bad"}); err == nil {
+		t.Fatalf("expected invalid label error")
+	}
+}
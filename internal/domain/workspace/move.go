@@ -0,0 +1,65 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// MoveRepo moves the worktree fromAlias of workspace fromID to toAlias in
+// workspace toID (which may be the same workspace) with `git worktree move`, so
+// uncommitted changes are kept and the repo store keeps tracking it. The
+// destination workspace must exist and the destination alias must be free.
+func MoveRepo(ctx context.Context, rootDir, fromID, fromAlias, toID, toAlias string) error {
+	if rootDir == "" {
+		return fmt.Errorf("root directory is required")
+	}
+	for _, id := range []string{fromID, toID} {
+		if err := validateWorkspaceID(ctx, id); err != nil {
+			return err
+		}
+	}
+	for _, alias := range []string{fromAlias, toAlias} {
+		if strings.TrimSpace(alias) == "" || strings.ContainsAny(alias, `/\`) || alias == "." || alias == ".." || alias == MetadataDirName {
+			return fmt.Errorf("invalid alias: %q", alias)
+		}
+	}
+	if fromID == toID && fromAlias == toAlias {
+		return nil
+	}
+
+	if exists, err := paths.DirExists(WorkspaceDir(rootDir, toID)); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("workspace does not exist: %s", toID)
+	}
+	target := WorktreePath(rootDir, toID, toAlias)
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Errorf("alias already exists: %s/%s", toID, toAlias)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	repos, _, err := ScanRepos(ctx, WorkspaceDir(rootDir, fromID))
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		if repo.Alias != fromAlias {
+			continue
+		}
+		if repo.StorePath == "" {
+			return fmt.Errorf("repo store not found for %s/%s", fromID, fromAlias)
+		}
+		gitcmd.Logf("git worktree move %s %s", repo.WorktreePath, target)
+		if err := gitcmd.WorktreeMove(ctx, repo.StorePath, repo.WorktreePath, target); err != nil {
+			return fmt.Errorf("move worktree %s/%s: %w", fromID, fromAlias, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("repo not found: %s/%s", fromID, fromAlias)
+}
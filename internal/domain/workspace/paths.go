@@ -2,6 +2,7 @@ package workspace
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/tasuku43/gion/internal/infra/paths"
 )
@@ -20,3 +21,22 @@ func WorkspaceDir(rootDir, workspaceID string) string {
 func WorktreePath(rootDir, workspaceID, alias string) string {
 	return filepath.Join(WorkspaceDir(rootDir, workspaceID), alias)
 }
+
+// WorkspaceIDFromPath returns the ID of the workspace that contains path (the
+// workspace directory itself or anything below it), resolving symlinks on both
+// sides.
+func WorkspaceIDFromPath(rootDir, path string) (string, bool) {
+	wsRoot := WorkspacesRoot(rootDir)
+	if resolved, err := filepath.EvalSymlinks(wsRoot); err == nil {
+		wsRoot = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	rel, err := filepath.Rel(wsRoot, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	id, _, _ := strings.Cut(rel, string(filepath.Separator))
+	return id, true
+}
@@ -0,0 +1,40 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorkspaceIDFromPath(t *testing.T) {
+	rootDir := t.TempDir()
+	repoDir := filepath.Join(WorktreePath(rootDir, "PROJ-1", "backend"), "src")
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(WorkspaceDir(rootDir, "PROJ-1"), link); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		path   string
+		wantID string
+		wantOK bool
+	}{
+		{name: "workspace_dir", path: WorkspaceDir(rootDir, "PROJ-1"), wantID: "PROJ-1", wantOK: true},
+		{name: "nested_in_repo", path: repoDir, wantID: "PROJ-1", wantOK: true},
+		{name: "via_symlink", path: filepath.Join(link, "backend"), wantID: "PROJ-1", wantOK: true},
+		{name: "workspaces_root", path: WorkspacesRoot(rootDir), wantOK: false},
+		{name: "outside", path: rootDir, wantOK: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			id, ok := WorkspaceIDFromPath(rootDir, tc.path)
+			if ok != tc.wantOK || id != tc.wantID {
+				t.Fatalf("WorkspaceIDFromPath(%s) = %q, %v; want %q, %v", tc.path, id, ok, tc.wantID, tc.wantOK)
+			}
+		})
+	}
+}
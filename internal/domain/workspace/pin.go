@@ -0,0 +1,128 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+)
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// ValidatePinnedRef reports whether ref can be used to pin a detached worktree.
+// A pinned ref is a tag name or a (possibly abbreviated) commit SHA.
+func ValidatePinnedRef(ref string) error {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return fmt.Errorf("ref is required")
+	}
+	if strings.ContainsAny(ref, " \t\r\n~^:?*[\\") || strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid ref: %s", ref)
+	}
+	if strings.Contains(ref, "..") || strings.Contains(ref, "@{") || strings.HasSuffix(ref, ".lock") || strings.HasSuffix(ref, "/") {
+		return fmt.Errorf("invalid ref: %s", ref)
+	}
+	return nil
+}
+
+// AddDetached adds a worktree whose HEAD is detached at ref (a tag or commit),
+// then applies the carry rules.
+func AddDetached(ctx context.Context, rootDir, workspaceID, repoSpec, alias, ref string, fetch bool, carry ...CarryRule) (Repo, error) {
+	if err := ValidatePinnedRef(ref); err != nil {
+		return Repo{}, err
+	}
+	prep, err := prepareAdd(ctx, rootDir, workspaceID, repoSpec, alias, fetch)
+	if err != nil {
+		return Repo{}, err
+	}
+	commit, err := ResolvePinnedRef(ctx, prep.store.StorePath, ref)
+	if err != nil {
+		return Repo{}, err
+	}
+
+	gitcmd.Logf("git worktree add --detach %s %s", prep.worktreePath, ref)
+	if err := worktreeAddWithRetry(ctx, prep.store.StorePath, func() error {
+		return gitcmd.WorktreeAddDetached(ctx, prep.store.StorePath, prep.worktreePath, commit)
+	}); err != nil {
+		return Repo{}, err
+	}
+
+	repoEntry := Repo{
+		Alias:        prep.alias,
+		RepoSpec:     repoSpec,
+		RepoKey:      prep.spec.RepoKey,
+		StorePath:    prep.store.StorePath,
+		WorktreePath: prep.worktreePath,
+	}
+	repoEntry.Carried, err = carryIntoNewWorktree(ctx, rootDir, prep, carry)
+	if err != nil {
+		return Repo{}, err
+	}
+
+	return repoEntry, nil
+}
+
+// ResolvePinnedRef resolves ref to a full commit SHA in the repo store.
+// Tags (and, for SHAs, the commit itself) are fetched from origin when missing locally.
+func ResolvePinnedRef(ctx context.Context, storePath, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if commit, ok := resolveCommit(ctx, storePath, ref); ok {
+		return commit, nil
+	}
+	gitcmd.Logf("git fetch origin --tags")
+	if _, err := gitcmd.Run(ctx, []string{"fetch", "origin", "--tags"}, gitcmd.Options{Dir: storePath}); err != nil {
+		return "", err
+	}
+	if commit, ok := resolveCommit(ctx, storePath, ref); ok {
+		return commit, nil
+	}
+	if commitSHAPattern.MatchString(ref) {
+		// Servers only serve full SHAs on demand; abbreviated ones must already be reachable.
+		gitcmd.Logf("git fetch origin %s", ref)
+		if _, err := gitcmd.Run(ctx, []string{"fetch", "origin", ref}, gitcmd.Options{Dir: storePath}); err == nil {
+			if commit, ok := resolveCommit(ctx, storePath, ref); ok {
+				return commit, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("ref not found: %s", ref)
+}
+
+// PinMatches reports whether the worktree HEAD is detached at ref.
+func PinMatches(ctx context.Context, worktreePath, ref string) bool {
+	if strings.TrimSpace(ref) == "" {
+		return false
+	}
+	if _, ok, err := gitcmd.SymbolicRef(ctx, worktreePath, "HEAD"); err != nil || ok {
+		return false
+	}
+	head, ok := resolveCommit(ctx, worktreePath, "HEAD")
+	if !ok {
+		return false
+	}
+	want, ok := resolveCommit(ctx, worktreePath, ref)
+	return ok && head == want
+}
+
+// DetachedRef returns the ref a detached worktree should be recorded as:
+// the pinned ref when HEAD still points at it, otherwise the HEAD commit itself.
+func DetachedRef(ctx context.Context, worktreePath, pinned string) (string, error) {
+	if PinMatches(ctx, worktreePath, pinned) {
+		return strings.TrimSpace(pinned), nil
+	}
+	head, ok := resolveCommit(ctx, worktreePath, "HEAD")
+	if !ok {
+		return "", fmt.Errorf("cannot resolve HEAD: %s", worktreePath)
+	}
+	return head, nil
+}
+
+func resolveCommit(ctx context.Context, dir, ref string) (string, bool) {
+	commit, err := gitcmd.RevParse(ctx, dir, "--verify", "--quiet", ref+"^{commit}")
+	if err != nil || strings.TrimSpace(commit) == "" {
+		return "", false
+	}
+	return commit, true
+}
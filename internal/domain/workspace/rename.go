@@ -0,0 +1,116 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tasuku43/gion/internal/infra/gitcmd"
+	"github.com/tasuku43/gion/internal/infra/paths"
+)
+
+// Rename moves the workspace oldID to newID. Worktrees are moved with
+// `git worktree move`, so uncommitted changes are kept and the repo stores
+// keep tracking them; everything else in the workspace directory (metadata,
+// untracked top-level files) is moved as is. When a step fails, everything
+// moved so far is moved back, so the workspace stays under oldID.
+func Rename(ctx context.Context, rootDir, oldID, newID string) error {
+	if rootDir == "" {
+		return fmt.Errorf("root directory is required")
+	}
+	for _, id := range []string{oldID, newID} {
+		if err := validateWorkspaceID(ctx, id); err != nil {
+			return err
+		}
+	}
+	if oldID == newID {
+		return fmt.Errorf("workspace %s: new id is the same as the current id", oldID)
+	}
+
+	oldDir := WorkspaceDir(rootDir, oldID)
+	newDir := WorkspaceDir(rootDir, newID)
+	if exists, err := paths.DirExists(oldDir); err != nil {
+		return err
+	} else if !exists {
+		return fmt.Errorf("workspace does not exist: %s", oldDir)
+	}
+	if _, err := os.Lstat(newDir); err == nil {
+		return fmt.Errorf("workspace already exists: %s", newDir)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	repos, _, err := ScanRepos(ctx, oldDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(newDir, 0o755); err != nil {
+		return fmt.Errorf("create workspace dir: %w", err)
+	}
+	var moved renameMoves
+	for _, repo := range repos {
+		if repo.StorePath == "" {
+			continue
+		}
+		target := filepath.Join(newDir, repo.Alias)
+		gitcmd.Logf("git worktree move %s %s", repo.WorktreePath, target)
+		if err := gitcmd.WorktreeMove(ctx, repo.StorePath, repo.WorktreePath, target); err != nil {
+			return moved.undo(ctx, newDir, fmt.Errorf("move worktree %q: %w", repo.Alias, err))
+		}
+		moved = append(moved, renameMove{storePath: repo.StorePath, from: repo.WorktreePath, to: target})
+	}
+
+	entries, err := os.ReadDir(oldDir)
+	if err != nil {
+		return moved.undo(ctx, newDir, err)
+	}
+	for _, entry := range entries {
+		from := filepath.Join(oldDir, entry.Name())
+		to := filepath.Join(newDir, entry.Name())
+		if err := os.Rename(from, to); err != nil {
+			return moved.undo(ctx, newDir, fmt.Errorf("move %s: %w", entry.Name(), err))
+		}
+		moved = append(moved, renameMove{from: from, to: to})
+	}
+	if err := os.Remove(oldDir); err != nil {
+		return moved.undo(ctx, newDir, fmt.Errorf("remove workspace dir: %w", err))
+	}
+	return nil
+}
+
+// renameMove is one completed step of Rename: a worktree moved with git (storePath
+// set) or a plain directory entry.
+type renameMove struct {
+	storePath string
+	from      string
+	to        string
+}
+
+type renameMoves []renameMove
+
+// undo moves everything back in reverse order, removes newDir and returns cause
+// joined with any error of the rollback.
+func (moves renameMoves) undo(ctx context.Context, newDir string, cause error) error {
+	errs := []error{cause}
+	for i := len(moves) - 1; i >= 0; i-- {
+		move := moves[i]
+		if move.storePath == "" {
+			if err := os.Rename(move.to, move.from); err != nil {
+				errs = append(errs, fmt.Errorf("move back %s: %w", move.to, err))
+			}
+			continue
+		}
+		gitcmd.Logf("git worktree move %s %s", move.to, move.from)
+		if err := gitcmd.WorktreeMove(ctx, move.storePath, move.to, move.from); err != nil {
+			errs = append(errs, fmt.Errorf("move back worktree %s: %w", move.to, err))
+		}
+	}
+	if len(errs) == 1 {
+		if err := os.Remove(newDir); err != nil {
+			errs = append(errs, fmt.Errorf("remove workspace dir: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,98 @@
+package workspace_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tasuku43/gion/internal/domain/repo"
+	"github.com/tasuku43/gion/internal/domain/workspace"
+)
+
+func TestRenameMovesBackOnFailure(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "gion")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gion@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gion")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gion@example.com")
+
+	ctx := context.Background()
+	tmp := t.TempDir()
+	rootDir := filepath.Join(tmp, "gion")
+
+	remoteBase := filepath.Join(tmp, "remotes")
+	remotePath := filepath.Join(remoteBase, "org", "repo.git")
+	if err := os.MkdirAll(filepath.Dir(remotePath), 0o755); err != nil {
+		t.Fatalf("mkdir remote: %v", err)
+	}
+	runGit(t, "", "init", "--bare", remotePath)
+
+	seedDir := filepath.Join(tmp, "seed")
+	runGit(t, "", "init", seedDir)
+	runGit(t, seedDir, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(seedDir, "README.md"), []byte("hello\n"), 0o644); err != nil {
+		t.Fatalf("write seed file: %v", err)
+	}
+	runGit(t, seedDir, "add", ".")
+	runGit(t, seedDir, "commit", "-m", "init")
+	runGit(t, seedDir, "remote", "add", "origin", remotePath)
+	runGit(t, seedDir, "push", "origin", "main")
+	runGit(t, "", "--git-dir", remotePath, "symbolic-ref", "HEAD", "refs/heads/main")
+
+	configPath := filepath.Join(tmp, "gitconfig")
+	fileURL := "file://" + filepath.ToSlash(remoteBase) + "/"
+	configData := fmt.Sprintf("[url \"%s\"]\n\tinsteadOf = https://example.com/\n", fileURL)
+	if err := os.WriteFile(configPath, []byte(configData), 0o644); err != nil {
+		t.Fatalf("write gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", configPath)
+	t.Setenv("GIT_CONFIG_SYSTEM", "/dev/null")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_TERMINAL_PROMPT", "0")
+
+	repoSpec := "https://example.com/org/repo.git"
+	if _, err := repo.Get(ctx, rootDir, repoSpec); err != nil {
+		t.Fatalf("repo get: %v", err)
+	}
+	if _, err := workspace.New(ctx, rootDir, "WS-1"); err != nil {
+		t.Fatalf("workspace new: %v", err)
+	}
+	for alias, branch := range map[string]string{"api": "WS-1", "web": "WS-1-web"} {
+		if _, err := workspace.AddWithBranch(ctx, rootDir, "WS-1", repoSpec, alias, branch, "", false); err != nil {
+			t.Fatalf("workspace add %s: %v", alias, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(workspace.WorktreePath(rootDir, "WS-1", "api"), "wip.txt"), []byte("wip\n"), 0o644); err != nil {
+		t.Fatalf("write wip: %v", err)
+	}
+	// A locked worktree cannot be moved, so the rename fails after api was moved.
+	webPath := workspace.WorktreePath(rootDir, "WS-1", "web")
+	runGit(t, webPath, "worktree", "lock", webPath)
+
+	if err := workspace.Rename(ctx, rootDir, "WS-1", "WS-2"); err == nil {
+		t.Fatalf("expected rename to fail on a locked worktree")
+	}
+	if _, err := os.Stat(workspace.WorkspaceDir(rootDir, "WS-2")); !os.IsNotExist(err) {
+		t.Fatalf("expected new workspace dir to be removed: %v", err)
+	}
+	repos, _, err := workspace.ScanRepos(ctx, workspace.WorkspaceDir(rootDir, "WS-1"))
+	if err != nil {
+		t.Fatalf("scan repos: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("expected both repos back under WS-1, got %+v", repos)
+	}
+	if data, err := os.ReadFile(filepath.Join(workspace.WorktreePath(rootDir, "WS-1", "api"), "wip.txt")); err != nil || string(data) != "wip\n" {
+		t.Fatalf("uncommitted file not moved back: %q, %v", data, err)
+	}
+	runGit(t, workspace.WorktreePath(rootDir, "WS-1", "api"), "status")
+
+	runGit(t, webPath, "worktree", "unlock", webPath)
+	if err := workspace.Rename(ctx, rootDir, "WS-1", "WS-2"); err != nil {
+		t.Fatalf("rename after unlock: %v", err)
+	}
+	if _, err := os.Stat(workspace.WorktreePath(rootDir, "WS-2", "web")); err != nil {
+		t.Fatalf("web not moved: %v", err)
+	}
+}
@@ -7,4 +7,6 @@ type Repo struct {
 	StorePath    string
 	WorktreePath string
 	Branch       string
+	// Carried lists the files carried into a worktree when it was added.
+	Carried []CarriedFile
 }
@@ -43,6 +43,7 @@ type RepoState struct {
 	UnstagedCount  int
 	UntrackedCount int
 	UnmergedCount  int
+	Pinned         string
 	Kind           RepoStateKind
 	Error          error
 }
@@ -79,8 +80,15 @@ func repoStateFromStatus(repo RepoStatus) RepoState {
 		UnstagedCount:  repo.UnstagedCount,
 		UntrackedCount: repo.UntrackedCount,
 		UnmergedCount:  repo.UnmergedCount,
+		Pinned:         repo.Pinned,
 		Error:          repo.Error,
 	}
+	if repo.Pinned != "" && repo.Error == nil && !repo.Dirty {
+		// A clean worktree detached at its pinned ref has nothing to lose: the commit
+		// is reachable from the pinned tag/commit in the repo store.
+		state.Kind = RepoStateClean
+		return state
+	}
 	kind := coreworkspacerisk.ClassifyRepoStatus(coreworkspacerisk.RepoStatus{
 		Upstream:    repo.Upstream,
 		AheadCount:  repo.AheadCount,
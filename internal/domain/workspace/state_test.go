@@ -85,6 +85,26 @@ func TestStateFromStatus(t *testing.T) {
 			},
 			want: WorkspaceStateUnknown,
 		},
+		{
+			name: "detached_at_pin_is_clean",
+			in: StatusResult{
+				WorkspaceID: "ws-pinned",
+				Repos: []RepoStatus{
+					{Alias: "app", Detached: true, Pinned: "v2.3.1"},
+				},
+			},
+			want: WorkspaceStateClean,
+		},
+		{
+			name: "dirty_pinned_is_dirty",
+			in: StatusResult{
+				WorkspaceID: "ws-pinned-dirty",
+				Repos: []RepoStatus{
+					{Alias: "app", Detached: true, Pinned: "v2.3.1", Dirty: true, UnstagedCount: 1},
+				},
+			},
+			want: WorkspaceStateDirty,
+		},
 		{
 			name: "unknown",
 			in: StatusResult{
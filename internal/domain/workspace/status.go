@@ -21,6 +21,7 @@ type RepoStatus struct {
 	Upstream       string
 	Head           string
 	Detached       bool
+	Pinned         string
 	HeadMissing    bool
 	Dirty          bool
 	UntrackedCount int
@@ -62,6 +63,10 @@ func Status(ctx context.Context, rootDir, workspaceID string) (StatusResult, err
 		WorkspaceID: workspaceID,
 		Warnings:    warnings,
 	}
+	meta, err := LoadMetadata(wsDir)
+	if err != nil {
+		result.Warnings = append(result.Warnings, err)
+	}
 	for _, repo := range repos {
 		repoStatus := RepoStatus{
 			Alias:        repo.Alias,
@@ -79,6 +84,9 @@ func Status(ctx context.Context, rootDir, workspaceID string) (StatusResult, err
 		repoStatus.RawStatus = statusOut
 		repoStatus.Branch, repoStatus.Upstream, repoStatus.Head, repoStatus.Detached, repoStatus.HeadMissing, repoStatus.Dirty, repoStatus.UntrackedCount, repoStatus.StagedCount, repoStatus.UnstagedCount, repoStatus.UnmergedCount, repoStatus.AheadCount, repoStatus.BehindCount = parseStatusPorcelainV2(statusOut, repoStatus.Branch)
 		repoStatus.ChangedFiles = parseChangedFilesPorcelainV2(statusOut)
+		if pinned := meta.Pins[repo.Alias]; repoStatus.Detached && PinMatches(ctx, repo.WorktreePath, pinned) {
+			repoStatus.Pinned = pinned
+		}
 		result.Repos = append(result.Repos, repoStatus)
 	}
 
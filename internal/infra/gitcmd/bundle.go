@@ -0,0 +1,51 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RevListCount counts the commits selected by revs (rev-list syntax) in dir.
+func RevListCount(ctx context.Context, dir string, revs ...string) (int, error) {
+	args := append([]string{"rev-list", "--count"}, revs...)
+	res, err := Run(ctx, args, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return 0, fmt.Errorf("git rev-list failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return 0, fmt.Errorf("git rev-list failed: %w", err)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(res.Stdout))
+	if err != nil {
+		return 0, fmt.Errorf("git rev-list: unexpected output %q", strings.TrimSpace(res.Stdout))
+	}
+	return count, nil
+}
+
+// BundleCreate writes the commits selected by revs (rev-list syntax) to a bundle file.
+func BundleCreate(ctx context.Context, dir, path string, revs ...string) error {
+	args := append([]string{"bundle", "create", path}, revs...)
+	res, err := Run(ctx, args, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git bundle create failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git bundle create failed: %w", err)
+	}
+	return nil
+}
+
+// BundleFetchBranch creates refs/heads/<branch> in dir from the same branch in a bundle file.
+func BundleFetchBranch(ctx context.Context, dir, path, branch string) error {
+	refspec := fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch)
+	res, err := Run(ctx, []string{"fetch", path, refspec}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git fetch from bundle failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git fetch from bundle failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CatFileBlob returns the contents of path at rev. ok is false when the path does
+// not exist at rev.
+func CatFileBlob(ctx context.Context, dir, rev, path string) ([]byte, bool, error) {
+	object := fmt.Sprintf("%s:%s", rev, path)
+	res, err := Run(ctx, []string{"cat-file", "blob", object}, Options{Dir: dir})
+	if err == nil {
+		return []byte(res.Stdout), true, nil
+	}
+	if res.ExitCode == 128 && (strings.Contains(res.Stderr, "does not exist") || strings.Contains(res.Stderr, "Not a valid object name")) {
+		return nil, false, nil
+	}
+	if strings.TrimSpace(res.Stderr) != "" {
+		return nil, false, fmt.Errorf("git cat-file blob %s failed: %w: %s", object, err, strings.TrimSpace(res.Stderr))
+	}
+	return nil, false, fmt.Errorf("git cat-file blob %s failed: %w", object, err)
+}
@@ -0,0 +1,54 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CheckoutDetach switches the worktree in dir to a detached HEAD at commit.
+func CheckoutDetach(ctx context.Context, dir, commit string) error {
+	commit = strings.TrimSpace(commit)
+	if commit == "" {
+		return fmt.Errorf("commit is required")
+	}
+	return runCheckout(ctx, dir, []string{"checkout", "--detach", commit})
+}
+
+// CheckoutBranch switches the worktree in dir to an existing local branch.
+func CheckoutBranch(ctx context.Context, dir, branch string) error {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return fmt.Errorf("branch is required")
+	}
+	return runCheckout(ctx, dir, []string{"checkout", branch})
+}
+
+// CheckoutNewBranch creates branch at startPoint and switches the worktree in dir to it.
+func CheckoutNewBranch(ctx context.Context, dir, branch, startPoint string) error {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return fmt.Errorf("branch is required")
+	}
+	return runCheckout(ctx, dir, []string{"checkout", "-b", branch, startPoint})
+}
+
+// CheckoutTrackingBranch creates branch tracking remoteName and switches the worktree in dir to it.
+func CheckoutTrackingBranch(ctx context.Context, dir, branch, remoteName string) error {
+	branch = strings.TrimSpace(branch)
+	if branch == "" {
+		return fmt.Errorf("branch is required")
+	}
+	return runCheckout(ctx, dir, []string{"checkout", "-b", branch, "--track", remoteName})
+}
+
+func runCheckout(ctx context.Context, dir string, args []string) error {
+	res, err := Run(ctx, args, Options{Dir: dir, ShowOutput: true})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git checkout failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git checkout failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CherryCommit is one line of git cherry output.
+type CherryCommit struct {
+	Hash string
+	// Equivalent is true when a patch-equivalent commit exists in upstream ("-" lines).
+	Equivalent bool
+}
+
+// Cherry lists commits in head that are not in upstream, marking the ones whose
+// patch already exists in upstream.
+func Cherry(ctx context.Context, dir, upstream, head string) ([]CherryCommit, error) {
+	upstream = strings.TrimSpace(upstream)
+	head = strings.TrimSpace(head)
+	if upstream == "" || head == "" {
+		return nil, fmt.Errorf("upstream and head are required")
+	}
+	res, err := Run(ctx, []string{"cherry", upstream, head}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return nil, fmt.Errorf("git cherry failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return nil, fmt.Errorf("git cherry failed: %w", err)
+	}
+	var commits []CherryCommit
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		commits = append(commits, CherryCommit{
+			Hash:       fields[1],
+			Equivalent: fields[0] == "-",
+		})
+	}
+	return commits, nil
+}
+
+// CommitTree creates a dangling commit object for tree with the given parent and
+// returns its hash. The commit is not referenced by any branch.
+func CommitTree(ctx context.Context, dir, tree, parent, message string) (string, error) {
+	tree = strings.TrimSpace(tree)
+	parent = strings.TrimSpace(parent)
+	if tree == "" || parent == "" {
+		return "", fmt.Errorf("tree and parent are required")
+	}
+	res, err := Run(ctx, []string{"commit-tree", tree, "-p", parent, "-m", message}, Options{
+		Dir: dir,
+		Env: []string{
+			"GIT_AUTHOR_NAME=gion",
+			"GIT_AUTHOR_EMAIL=gion@localhost",
+			"GIT_COMMITTER_NAME=gion",
+			"GIT_COMMITTER_EMAIL=gion@localhost",
+		},
+	})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return "", fmt.Errorf("git commit-tree failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return "", fmt.Errorf("git commit-tree failed: %w", err)
+	}
+	return strings.TrimSpace(res.Stdout), nil
+}
@@ -0,0 +1,22 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ConfigGet reads a git config value. ok is false when the key is not set.
+func ConfigGet(ctx context.Context, dir, key string) (string, bool, error) {
+	res, err := Run(ctx, []string{"config", "--get", key}, Options{Dir: dir})
+	if err == nil {
+		return strings.TrimSpace(res.Stdout), true, nil
+	}
+	if res.ExitCode == 1 {
+		return "", false, nil
+	}
+	if strings.TrimSpace(res.Stderr) != "" {
+		return "", false, fmt.Errorf("git config --get %s failed: %w: %s", key, err, strings.TrimSpace(res.Stderr))
+	}
+	return "", false, err
+}
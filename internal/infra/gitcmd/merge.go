@@ -0,0 +1,35 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Merge merges ref into the current branch in dir with the default commit message.
+func Merge(ctx context.Context, dir, ref string) error {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return fmt.Errorf("ref is required")
+	}
+	res, err := Run(ctx, []string{"merge", "--no-edit", ref}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git merge failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git merge failed: %w", err)
+	}
+	return nil
+}
+
+// MergeAbort stops an in-progress merge in dir and restores the branch.
+func MergeAbort(ctx context.Context, dir string) error {
+	res, err := Run(ctx, []string{"merge", "--abort"}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git merge --abort failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git merge --abort failed: %w", err)
+	}
+	return nil
+}
@@ -28,3 +28,20 @@ func IsAncestor(ctx context.Context, dir, ancestor, descendant string) (bool, er
 	}
 	return false, fmt.Errorf("git merge-base --is-ancestor failed: %w", err)
 }
+
+// MergeBase returns the best common ancestor of a and b.
+func MergeBase(ctx context.Context, dir, a, b string) (string, error) {
+	a = strings.TrimSpace(a)
+	b = strings.TrimSpace(b)
+	if a == "" || b == "" {
+		return "", fmt.Errorf("two commits are required")
+	}
+	res, err := Run(ctx, []string{"merge-base", a, b}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return "", fmt.Errorf("git merge-base failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return "", fmt.Errorf("git merge-base failed: %w", err)
+	}
+	return strings.TrimSpace(res.Stdout), nil
+}
@@ -0,0 +1,80 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DiffBinaryHEAD returns a binary-safe patch of the staged and unstaged changes
+// in dir relative to HEAD. It is empty for a clean worktree.
+func DiffBinaryHEAD(ctx context.Context, dir string) (string, error) {
+	res, err := Run(ctx, []string{"diff", "--binary", "HEAD"}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return "", fmt.Errorf("git diff failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return res.Stdout, nil
+}
+
+// ApplyPatch applies the patch file at patchPath to the worktree in dir.
+func ApplyPatch(ctx context.Context, dir, patchPath string) error {
+	res, err := Run(ctx, []string{"apply", "--binary", patchPath}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git apply failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git apply failed: %w", err)
+	}
+	return nil
+}
+
+// UntrackedFiles lists untracked files in dir that are not ignored, relative to dir.
+func UntrackedFiles(ctx context.Context, dir string) ([]string, error) {
+	res, err := Run(ctx, []string{"ls-files", "--others", "--exclude-standard", "-z"}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return nil, fmt.Errorf("git ls-files failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+	var files []string
+	for _, name := range strings.Split(res.Stdout, "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// DiffBinaryWorktree returns a binary-safe patch of every change in the worktree
+// at dir relative to HEAD, including untracked files that are not ignored. A
+// temporary index is used, so the worktree's own index is left untouched.
+func DiffBinaryWorktree(ctx context.Context, dir string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "gion-index-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+	opts := Options{Dir: dir, Env: []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}}
+	for _, args := range [][]string{{"read-tree", "HEAD"}, {"add", "-A"}} {
+		if res, err := Run(ctx, args, opts); err != nil {
+			if strings.TrimSpace(res.Stderr) != "" {
+				return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(res.Stderr))
+			}
+			return "", fmt.Errorf("git %s failed: %w", args[0], err)
+		}
+	}
+	res, err := Run(ctx, []string{"diff", "--cached", "--binary", "HEAD"}, opts)
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return "", fmt.Errorf("git diff failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return res.Stdout, nil
+}
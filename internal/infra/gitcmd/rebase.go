@@ -0,0 +1,55 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Rebase rebases the current branch in dir onto onto. When upstream is set, only
+// the commits after upstream are replayed (`git rebase --onto <onto> <upstream>`).
+func Rebase(ctx context.Context, dir, onto, upstream string) error {
+	onto = strings.TrimSpace(onto)
+	if onto == "" {
+		return fmt.Errorf("onto is required")
+	}
+	args := []string{"rebase", onto}
+	if upstream = strings.TrimSpace(upstream); upstream != "" {
+		args = []string{"rebase", "--onto", onto, upstream}
+	}
+	res, err := Run(ctx, args, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git rebase failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git rebase failed: %w", err)
+	}
+	return nil
+}
+
+// RebaseAbort stops an in-progress rebase in dir and restores the branch.
+func RebaseAbort(ctx context.Context, dir string) error {
+	res, err := Run(ctx, []string{"rebase", "--abort"}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git rebase --abort failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git rebase --abort failed: %w", err)
+	}
+	return nil
+}
+
+// ConflictedFiles lists the unmerged paths in dir.
+func ConflictedFiles(ctx context.Context, dir string) ([]string, error) {
+	res, err := Run(ctx, []string{"diff", "--name-only", "--diff-filter=U"}, Options{Dir: dir})
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
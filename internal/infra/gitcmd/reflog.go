@@ -0,0 +1,44 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReflogTimes returns the times ref's reflog entries were recorded, newest first.
+// A ref without a reflog returns an empty slice.
+func ReflogTimes(ctx context.Context, dir, ref string) ([]time.Time, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("ref is required")
+	}
+	res, err := Run(ctx, []string{"reflog", "show", "--format=%gd", "--date=unix", ref, "--"}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return nil, fmt.Errorf("git reflog failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return nil, fmt.Errorf("git reflog failed: %w", err)
+	}
+	var times []time.Time
+	for _, line := range strings.Split(res.Stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		// %gd with --date=unix renders as ref@{<unix>}.
+		selector := fields[0]
+		start := strings.LastIndex(selector, "@{")
+		if start < 0 || !strings.HasSuffix(selector, "}") {
+			continue
+		}
+		sec, err := strconv.ParseInt(selector[start+2:len(selector)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		times = append(times, time.Unix(sec, 0))
+	}
+	return times, nil
+}
@@ -0,0 +1,39 @@
+package gitcmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MergeFastForwardOnly fast-forwards the current branch in dir to ref.
+func MergeFastForwardOnly(ctx context.Context, dir, ref string) error {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return fmt.Errorf("ref is required")
+	}
+	res, err := Run(ctx, []string{"merge", "--ff-only", ref}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git merge --ff-only failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git merge --ff-only failed: %w", err)
+	}
+	return nil
+}
+
+// ResetHard moves the current branch in dir to ref and discards worktree changes.
+func ResetHard(ctx context.Context, dir, ref string) error {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return fmt.Errorf("ref is required")
+	}
+	res, err := Run(ctx, []string{"reset", "--hard", ref}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git reset --hard failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git reset --hard failed: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 
 	"github.com/tasuku43/gion/internal/infra/debuglog"
@@ -21,6 +22,8 @@ type Options struct {
 	Dir string
 	// ShowOutput prints stdout/stderr even when debug logging is off.
 	ShowOutput bool
+	// Env is appended to the current process environment.
+	Env []string
 }
 
 func Run(ctx context.Context, args []string, opts Options) (Result, error) {
@@ -35,6 +38,9 @@ func Run(ctx context.Context, args []string, opts Options) (Result, error) {
 	if opts.Dir != "" {
 		cmd.Dir = opts.Dir
 	}
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -87,16 +93,31 @@ func isAllowedSubcommand(subcommand string) bool {
 }
 
 var allowedSubcommands = map[string]struct{}{
+	"add":              {},
+	"apply":            {},
 	"branch":           {},
+	"bundle":           {},
+	"cat-file":         {},
 	"check-ref-format": {},
+	"checkout":         {},
+	"cherry":           {},
 	"clone":            {},
+	"commit-tree":      {},
 	"config":           {},
+	"diff":             {},
 	"fetch":            {},
 	"init":             {},
+	"ls-files":         {},
 	"ls-remote":        {},
+	"merge":            {},
 	"merge-base":       {},
+	"read-tree":        {},
+	"rebase":           {},
+	"rev-list":         {},
 	"rev-parse":        {},
+	"reflog":           {},
 	"remote":           {},
+	"reset":            {},
 	"show-ref":         {},
 	"symbolic-ref":     {},
 	"status":           {},
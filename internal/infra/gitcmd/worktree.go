@@ -66,6 +66,18 @@ func WorktreeAddTrackingBranch(ctx context.Context, dir, branch, path, remoteNam
 	return nil
 }
 
+// WorktreeAddDetached adds a worktree with a detached HEAD at commit.
+func WorktreeAddDetached(ctx context.Context, dir, path, commit string) error {
+	res, err := Run(ctx, []string{"worktree", "add", "--detach", path, commit}, Options{Dir: dir, ShowOutput: true})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git worktree add failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git worktree add failed: %w", err)
+	}
+	return nil
+}
+
 // WorktreeRemove removes a worktree.
 func WorktreeRemove(ctx context.Context, dir, path string, force bool) error {
 	args := []string{"worktree", "remove", path}
@@ -81,3 +93,15 @@ func WorktreeRemove(ctx context.Context, dir, path string, force bool) error {
 	}
 	return nil
 }
+
+// WorktreeMove moves a worktree to newPath. Uncommitted changes move with it.
+func WorktreeMove(ctx context.Context, dir, path, newPath string) error {
+	res, err := Run(ctx, []string{"worktree", "move", path, newPath}, Options{Dir: dir})
+	if err != nil {
+		if strings.TrimSpace(res.Stderr) != "" {
+			return fmt.Errorf("git worktree move failed: %w: %s", err, strings.TrimSpace(res.Stderr))
+		}
+		return fmt.Errorf("git worktree move failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package hookcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/tasuku43/gion/internal/infra/debuglog"
+	"github.com/tasuku43/gion/internal/infra/output"
+)
+
+// Run runs command with `sh -c` in dir. Env is appended to the current process
+// environment. Stdout and stderr are streamed line by line to the step logger
+// while the command runs.
+func Run(ctx context.Context, command, dir string, env []string) error {
+	args := []string{"-c", command}
+	cmd := exec.CommandContext(ctx, "sh", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+
+	output.Logf("$ %s", command)
+	trace := ""
+	if debuglog.Enabled() {
+		trace = debuglog.NewTrace("hook")
+		debuglog.LogCommand(trace, debuglog.FormatCommand("sh", args))
+	}
+	w := &lineWriter{}
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	w.flush()
+	if debuglog.Enabled() {
+		debuglog.LogStdoutLines(trace, w.log.String())
+		debuglog.LogExit(trace, debuglog.ExitCode(err))
+	}
+	if err != nil {
+		return fmt.Errorf("sh -c %q: %w", command, err)
+	}
+	return nil
+}
+
+// lineWriter forwards complete lines to output.LogOutput. exec.Cmd writes to
+// it from one goroutine at a time because Stdout and Stderr are the same writer;
+// the mutex keeps flush safe as well.
+type lineWriter struct {
+	mu      sync.Mutex
+	pending []byte
+	log     strings.Builder
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		w.emit(string(w.pending[:idx]))
+		w.pending = w.pending[idx+1:]
+	}
+	return len(p), nil
+}
+
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) > 0 {
+		w.emit(string(w.pending))
+		w.pending = nil
+	}
+}
+
+func (w *lineWriter) emit(line string) {
+	line = strings.TrimRight(line, "\r")
+	w.log.WriteString(line)
+	w.log.WriteByte('\n')
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	output.LogOutput(line)
+}
@@ -11,3 +11,18 @@ func BareRoot(rootDir string) string {
 func WorkspacesRoot(rootDir string) string {
 	return filepath.Join(rootDir, "workspaces")
 }
+
+// CatalogsRoot returns the path to the synced preset catalogs root.
+func CatalogsRoot(rootDir string) string {
+	return filepath.Join(rootDir, "catalogs")
+}
+
+// TemplatesRoot returns the path to the per-repo file templates root.
+func TemplatesRoot(rootDir string) string {
+	return filepath.Join(rootDir, "templates")
+}
+
+// ArchiveRoot returns the path to the workspace archives root.
+func ArchiveRoot(rootDir string) string {
+	return filepath.Join(rootDir, "archive")
+}
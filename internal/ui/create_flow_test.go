@@ -28,3 +28,56 @@ func TestCreateFlow_PresetBranchInput_UsesSeparateInputLine(t *testing.T) {
 		t.Fatalf("expected separateInputLine=true for preset branch input")
 	}
 }
+
+func TestCreateFlow_PresetBranchInput_UsesPresetDefaults(t *testing.T) {
+	m := createFlowModel{
+		title:          "gion manifest add",
+		mode:           "preset",
+		theme:          DefaultTheme(),
+		useColor:       false,
+		validateBranch: func(string) error { return nil },
+	}
+	m.presetModel = newInputsModelWithLabel(m.title, []string{"app"}, "app", "PROJ-123", "preset", nil, m.theme, m.useColor)
+	m.presetRepos = []string{"git@github.com:org/app.git", "git@github.com:org/infra.git"}
+	m.presetBranches = []string{"", "infra/PROJ-123"}
+	m.beginDescriptionStage()
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got := next.(createFlowModel)
+	if value := got.branchModel.input.Value(); value != "PROJ-123" {
+		t.Fatalf("first branch default = %q, want workspace id", value)
+	}
+	model, _ := got.branchModel.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	branchModel := model.(branchInputModel)
+	if value := branchModel.input.Value(); value != "infra/PROJ-123" {
+		t.Fatalf("second branch default = %q, want preset default", value)
+	}
+}
+
+func TestCreateFlow_RepoBranchInput_UsesRepoDefault(t *testing.T) {
+	m := createFlowModel{
+		title:          "gion manifest add",
+		mode:           "repo",
+		stage:          createStageRepoWorkspace,
+		theme:          DefaultTheme(),
+		useColor:       false,
+		validateBranch: func(string) error { return nil },
+		loadRepoBranch: func(workspaceID string) (string, error) {
+			return "feature/" + workspaceID, nil
+		},
+	}
+	m.repoSelected = "git@github.com:org/app.git"
+	m.presetRepos = []string{m.repoSelected}
+	m.presetModel = newInputsModelWithLabel(m.title, nil, m.repoSelected, "PROJ-123", "repo", nil, m.theme, m.useColor)
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got := next.(createFlowModel)
+	if got.stage != createStagePresetDesc {
+		t.Fatalf("expected stage %v, got %v", createStagePresetDesc, got.stage)
+	}
+	next, _ = got.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got = next.(createFlowModel)
+	if value := got.branchModel.input.Value(); value != "feature/PROJ-123" {
+		t.Fatalf("branch default = %q, want repo template default", value)
+	}
+}
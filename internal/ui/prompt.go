@@ -20,6 +20,8 @@ type PromptChoice struct {
 	Value       string
 	Description string
 	Details     []string
+	// Branch is the branch suggested for the choice (issue choices).
+	Branch string
 }
 
 type IssueSelection struct {
@@ -105,6 +107,7 @@ type createFlowModel struct {
 
 	presetModel        inputsModel
 	presetRepos        []string
+	presetBranches     []string
 	description        string
 	descInput          textinput.Model
 	branches           []string
@@ -129,7 +132,8 @@ type createFlowModel struct {
 	repoSelectModel     choiceSelectModel
 	loadReviewPRs       func(string) ([]PromptChoice, error)
 	loadIssueChoices    func(string) ([]PromptChoice, error)
-	loadPresetRepos     func(string) ([]string, error)
+	loadPresetRepos     func(string, string) ([]string, []string, error)
+	loadRepoBranch      func(string) (string, error)
 	onReposResolved     func([]string)
 	validateBranch      func(string) error
 	validateWorkspaceID func(string) error
@@ -138,7 +142,7 @@ type createFlowModel struct {
 	useColor bool
 }
 
-func newCreateFlowModel(title string, presets []string, tmplErr error, repoChoices []PromptChoice, repoErr error, defaultWorkspaceID string, presetName string, reviewRepos []PromptChoice, issueRepos []PromptChoice, loadReview func(string) ([]PromptChoice, error), loadIssue func(string) ([]PromptChoice, error), loadPresetRepos func(string) ([]string, error), onReposResolved func([]string), validateBranch func(string) error, validateWorkspaceID func(string) error, theme Theme, useColor bool, startMode string, selectedRepo string) createFlowModel {
+func newCreateFlowModel(title string, presets []string, tmplErr error, repoChoices []PromptChoice, repoErr error, defaultWorkspaceID string, presetName string, reviewRepos []PromptChoice, issueRepos []PromptChoice, loadReview func(string) ([]PromptChoice, error), loadIssue func(string) ([]PromptChoice, error), loadPresetRepos func(string, string) ([]string, []string, error), loadRepoBranch func(string) (string, error), onReposResolved func([]string), validateBranch func(string) error, validateWorkspaceID func(string) error, theme Theme, useColor bool, startMode string, selectedRepo string) createFlowModel {
 	input := textinput.New()
 	input.Prompt = ""
 	input.Placeholder = "search"
@@ -160,6 +164,7 @@ func newCreateFlowModel(title string, presets []string, tmplErr error, repoChoic
 		loadReviewPRs:       loadReview,
 		loadIssueChoices:    loadIssue,
 		loadPresetRepos:     loadPresetRepos,
+		loadRepoBranch:      loadRepoBranch,
 		onReposResolved:     onReposResolved,
 		validateBranch:      validateBranch,
 		validateWorkspaceID: validateWorkspaceID,
@@ -172,6 +177,7 @@ func newCreateFlowModel(title string, presets []string, tmplErr error, repoChoic
 		if startMode == "repo" && m.repoSelected != "" {
 			m.mode = "repo"
 			m.presetRepos = []string{m.repoSelected}
+			m.presetBranches = nil
 			if m.onReposResolved != nil {
 				m.onReposResolved(m.presetRepos)
 			}
@@ -348,12 +354,13 @@ func (m createFlowModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model, _ := m.presetModel.Update(msg)
 		m.presetModel = model.(inputsModel)
 		if m.presetModel.done {
-			repos, err := m.loadPresetRepos(m.presetName())
+			repos, branches, err := m.loadPresetRepos(m.presetName(), m.workspaceID())
 			if err != nil {
 				m.err = err
 				return m, tea.Quit
 			}
 			m.presetRepos = repos
+			m.presetBranches = branches
 			if m.onReposResolved != nil {
 				m.onReposResolved(m.presetRepos)
 			}
@@ -389,7 +396,7 @@ func (m createFlowModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 						return fmt.Sprintf("repo #%d (%s)", index+1, label)
 					},
-					func(PromptChoice) string { return m.workspaceID() },
+					m.presetBranchDefault,
 					m.validateBranch,
 					false,
 					m.theme,
@@ -497,6 +504,7 @@ func (m createFlowModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.repoSelectModel.done {
 			m.repoSelected = m.repoSelectModel.value
 			m.presetRepos = []string{m.repoSelected}
+			m.presetBranches = nil
 			if m.onReposResolved != nil {
 				m.onReposResolved(m.presetRepos)
 			}
@@ -510,6 +518,14 @@ func (m createFlowModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		model, _ := m.presetModel.Update(msg)
 		m.presetModel = model.(inputsModel)
 		if m.presetModel.done {
+			if m.loadRepoBranch != nil {
+				branch, err := m.loadRepoBranch(m.workspaceID())
+				if err != nil {
+					m.err = err
+					return m, tea.Quit
+				}
+				m.presetBranches = []string{branch}
+			}
 			m.beginDescriptionStage()
 			return m, nil
 		}
@@ -629,6 +645,17 @@ func (m createFlowModel) workspaceID() string {
 	return m.presetModel.currentWorkspaceID()
 }
 
+// presetBranchDefault returns the branch suggested for a repo: the preset (or
+// repo mode) default when one is set, else the workspace ID.
+func (m createFlowModel) presetBranchDefault(index int, _ PromptChoice) string {
+	if index < len(m.presetBranches) {
+		if branch := strings.TrimSpace(m.presetBranches[index]); branch != "" {
+			return branch
+		}
+	}
+	return m.workspaceID()
+}
+
 func (m createFlowModel) selectionLabel() string {
 	if m.mode == "repo" {
 		return "repo"
@@ -1661,7 +1688,7 @@ type branchInputModel struct {
 	selections        []BranchSelection
 	usedBranches      map[string]int
 	itemLabel         func(int, PromptChoice) string
-	defaultBranch     func(PromptChoice) string
+	defaultBranch     func(int, PromptChoice) string
 	validateBranch    func(string) error
 	ensureUnique      bool
 	separateInputLine bool
@@ -1669,7 +1696,7 @@ type branchInputModel struct {
 	useColor          bool
 }
 
-func newBranchInputModel(title string, items []PromptChoice, itemLabel func(int, PromptChoice) string, defaultBranch func(PromptChoice) string, validateBranch func(string) error, ensureUnique bool, theme Theme, useColor bool) branchInputModel {
+func newBranchInputModel(title string, items []PromptChoice, itemLabel func(int, PromptChoice) string, defaultBranch func(int, PromptChoice) string, validateBranch func(string) error, ensureUnique bool, theme Theme, useColor bool) branchInputModel {
 	input := textinput.New()
 	input.Prompt = ""
 	input.Placeholder = "branch"
@@ -1693,7 +1720,7 @@ func newBranchInputModel(title string, items []PromptChoice, itemLabel func(int,
 		m.usedBranches = map[string]int{}
 	}
 	if len(items) > 0 && m.defaultBranch != nil {
-		value := strings.TrimSpace(m.defaultBranch(items[0]))
+		value := strings.TrimSpace(m.defaultBranch(0, items[0]))
 		if value != "" {
 			m.input.SetValue(value)
 			m.input.CursorEnd()
@@ -1720,7 +1747,7 @@ func (m branchInputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			value := strings.TrimSpace(m.input.Value())
 			if value == "" && m.defaultBranch != nil {
-				value = strings.TrimSpace(m.defaultBranch(m.items[m.index]))
+				value = strings.TrimSpace(m.defaultBranch(m.index, m.items[m.index]))
 			}
 			if m.validateBranch != nil {
 				if err := m.validateBranch(value); err != nil {
@@ -1749,7 +1776,7 @@ func (m branchInputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 			if m.defaultBranch != nil {
-				m.input.SetValue(m.defaultBranch(m.items[m.index]))
+				m.input.SetValue(m.defaultBranch(m.index, m.items[m.index]))
 				m.input.CursorEnd()
 			} else {
 				m.input.SetValue("")
@@ -2053,7 +2080,10 @@ func (m issueBranchSelectModel) startBranchInput() issueBranchSelectModel {
 			}
 			return fmt.Sprintf("issue #%d (%s)", index+1, label)
 		},
-		func(choice PromptChoice) string {
+		func(_ int, choice PromptChoice) string {
+			if branch := strings.TrimSpace(choice.Branch); branch != "" {
+				return branch
+			}
 			return defaultIssueBranch(choice.Value)
 		},
 		m.validateBranch,
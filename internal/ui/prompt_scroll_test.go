@@ -127,7 +127,7 @@ func TestBranchInputModel_SeparateInputLineKeepsBranchVisible(t *testing.T) {
 		func(index int, choice PromptChoice) string {
 			return fmt.Sprintf("issue #%d (%s)", index+1, choice.Label)
 		},
-		func(choice PromptChoice) string {
+		func(int, PromptChoice) string {
 			return "issue/96"
 		},
 		nil,